@@ -0,0 +1,146 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// An arrayLanguage describes how to write a byte array literal in some
+// programming language.
+type arrayLanguage struct {
+	// defaultName is the variable name to use if none is specified.
+	defaultName string
+	// prefix and suffix are format strings, taking the variable name and
+	// length, for the lines before and after the array elements.
+	prefix, suffix string
+	// indent is the indentation for lines of array elements.
+	indent string
+	// allowEmpty is true if the language allows empty arrays.
+	allowEmpty bool
+}
+
+var arrayLanguages = map[string]arrayLanguage{
+	"c": {
+		defaultName: "kData",
+		prefix:      "static const uint8_t %[1]s[%[2]d] = {",
+		suffix:      "};",
+		indent:      "    ",
+	},
+	"go": {
+		defaultName: "data",
+		prefix:      "var %[1]s = []byte{",
+		suffix:      "}",
+		indent:      "\t",
+		allowEmpty:  true,
+	},
+	"rust": {
+		defaultName: "DATA",
+		prefix:      "const %[1]s: [u8; %[2]d] = [",
+		suffix:      "];",
+		indent:      "    ",
+		allowEmpty:  true,
+	},
+}
+
+// isIdentifier returns whether name is an identifier in each of the array
+// languages: a non-empty sequence of ASCII letters, digits, and underscores
+// which does not begin with a digit.
+func isIdentifier(name string) bool {
+	if name == "" || '0' <= name[0] && name[0] <= '9' {
+		return false
+	}
+	for _, c := range name {
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// wrapLines splits s into lines of at most width characters and returns the
+// result with a trailing newline. If width is zero, s is not split.
+func wrapLines(s string, width int) []byte {
+	var out bytes.Buffer
+	for width > 0 && len(s) > width {
+		out.WriteString(s[:width])
+		out.WriteByte('\n')
+		s = s[width:]
+	}
+	if len(s) > 0 {
+		out.WriteString(s)
+		out.WriteByte('\n')
+	}
+	return out.Bytes()
+}
+
+// formatHex encodes in as hex with wrap bytes per line. If wrap is zero, the
+// output is a single line.
+func formatHex(in []byte, wrap int) []byte {
+	return wrapLines(hex.EncodeToString(in), 2*wrap)
+}
+
+// formatBase64 encodes in as base64 with wrap bytes per line. wrap must be a
+// multiple of three, so each line may be decoded separately. If wrap is zero,
+// the output is a single line.
+func formatBase64(in []byte, wrap int) ([]byte, error) {
+	if wrap%3 != 0 {
+		return nil, fmt.Errorf("base64 line wrapping must be a multiple of 3 bytes, got %d", wrap)
+	}
+	return wrapLines(base64.StdEncoding.EncodeToString(in), wrap/3*4), nil
+}
+
+// formatArray encodes in as a byte array literal in the specified language,
+// with wrap bytes per line. If name is empty, a default variable name is used.
+// If wrap is zero, the elements are written on a single line.
+func formatArray(in []byte, language, name string, wrap int) ([]byte, error) {
+	lang, ok := arrayLanguages[language]
+	if !ok {
+		return nil, fmt.Errorf("unknown language %q", language)
+	}
+	if name == "" {
+		name = lang.defaultName
+	}
+	if !isIdentifier(name) {
+		return nil, fmt.Errorf("invalid variable name %q", name)
+	}
+	if len(in) == 0 && !lang.allowEmpty {
+		return nil, fmt.Errorf("%s does not allow empty arrays", language)
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, lang.prefix, name, len(in))
+	out.WriteByte('\n')
+	for i, b := range in {
+		if i == 0 || (wrap > 0 && i%wrap == 0) {
+			if i != 0 {
+				out.WriteByte('\n')
+			}
+			out.WriteString(lang.indent)
+		} else {
+			out.WriteByte(' ')
+		}
+		fmt.Fprintf(&out, "0x%02x,", b)
+	}
+	if len(in) != 0 {
+		out.WriteByte('\n')
+	}
+	out.WriteString(lang.suffix)
+	out.WriteByte('\n')
+	return out.Bytes(), nil
+}
@@ -0,0 +1,134 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import "testing"
+
+var formatHexTests = []struct {
+	in   []byte
+	wrap int
+	out  string
+}{
+	{[]byte{}, 0, ""},
+	{[]byte{0x30, 0x03, 0x02, 0x01, 0x01}, 0, "3003020101\n"},
+	{[]byte{0x30, 0x03, 0x02, 0x01, 0x01}, 2, "3003\n0201\n01\n"},
+	{[]byte{0x30, 0x03, 0x02, 0x01}, 2, "3003\n0201\n"},
+}
+
+func TestFormatHex(t *testing.T) {
+	for i, tt := range formatHexTests {
+		if out := string(formatHex(tt.in, tt.wrap)); out != tt.out {
+			t.Errorf("%d. formatHex(%x, %d) = %q, want %q.", i, tt.in, tt.wrap, out, tt.out)
+		}
+	}
+}
+
+var formatBase64Tests = []struct {
+	in   []byte
+	wrap int
+	out  string
+	ok   bool
+}{
+	{[]byte{}, 0, "", true},
+	{[]byte{0x30, 0x03, 0x02, 0x01, 0x01}, 0, "MAMCAQE=\n", true},
+	{[]byte{0x30, 0x03, 0x02, 0x01, 0x01}, 3, "MAMC\nAQE=\n", true},
+	{[]byte{0x30, 0x03, 0x02, 0x01, 0x01}, 2, "", false},
+}
+
+func TestFormatBase64(t *testing.T) {
+	for i, tt := range formatBase64Tests {
+		out, err := formatBase64(tt.in, tt.wrap)
+		if ok := err == nil; ok != tt.ok {
+			t.Errorf("%d. formatBase64(%x, %d) returned error %v, wanted success %v.", i, tt.in, tt.wrap, err, tt.ok)
+		} else if ok && string(out) != tt.out {
+			t.Errorf("%d. formatBase64(%x, %d) = %q, want %q.", i, tt.in, tt.wrap, out, tt.out)
+		}
+	}
+}
+
+var formatArrayTests = []struct {
+	in       []byte
+	language string
+	name     string
+	wrap     int
+	out      string
+	ok       bool
+}{
+	{
+		[]byte{0x30, 0x03, 0x02, 0x01, 0x01},
+		"c",
+		"",
+		2,
+		`static const uint8_t kData[5] = {
+    0x30, 0x03,
+    0x02, 0x01,
+    0x01,
+};
+`,
+		true,
+	},
+	{
+		[]byte{0x30, 0x03, 0x02, 0x01, 0x01},
+		"go",
+		"kCert",
+		0,
+		`var kCert = []byte{
+	0x30, 0x03, 0x02, 0x01, 0x01,
+}
+`,
+		true,
+	},
+	{
+		[]byte{0x30, 0x03, 0x02, 0x01, 0x01},
+		"rust",
+		"",
+		3,
+		`const DATA: [u8; 5] = [
+    0x30, 0x03, 0x02,
+    0x01, 0x01,
+];
+`,
+		true,
+	},
+	{
+		[]byte{},
+		"go",
+		"",
+		12,
+		"var data = []byte{\n}\n",
+		true,
+	},
+	{[]byte{}, "rust", "", 12, "const DATA: [u8; 0] = [\n];\n", true},
+	// C does not allow empty arrays.
+	{[]byte{}, "c", "", 12, "", false},
+	// Names must be identifiers.
+	{[]byte{0x01}, "go", "_data1", 0, "var _data1 = []byte{\n\t0x01,\n}\n", true},
+	{[]byte{0x01}, "c", "1 bad", 0, "", false},
+	{[]byte{0x01}, "go", "1data", 0, "", false},
+	{[]byte{0x01}, "rust", "DATA-1", 0, "", false},
+	{[]byte{0x01}, "go", "daté", 0, "", false},
+	{[]byte{}, "cobol", "", 12, "", false},
+}
+
+func TestFormatArray(t *testing.T) {
+	for i, tt := range formatArrayTests {
+		out, err := formatArray(tt.in, tt.language, tt.name, tt.wrap)
+		if ok := err == nil; ok != tt.ok {
+			t.Errorf("%d. formatArray(%x, %q) returned error %v, wanted success %v.", i, tt.in, tt.language, err, tt.ok)
+		} else if ok && string(out) != tt.out {
+			t.Errorf("%d. formatArray(%x, %q) = %q, want %q.", i, tt.in, tt.language, out, tt.out)
+		}
+	}
+}
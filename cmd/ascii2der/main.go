@@ -20,15 +20,69 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"strings"
+
+	"github.com/google/der-ascii/internal/ascii2der"
 )
 
 var inPath = flag.String("i", "", "input file to use (defaults to stdin)")
 var outPath = flag.String("o", "", "output file to use (defaults to stdout)")
 var pemType = flag.String("pem", "", "if provided, format the output as a PEM block with this type")
+var format = flag.String("format", "", "the output format: raw, pem, hex, base64, c, go, or rust (defaults to pem if -pem is provided and raw otherwise)")
+var varName = flag.String("name", "", "with -format=c, go, or rust, the variable name to use, which must be an identifier")
+var wrap = flag.Int("wrap", -1, "with -format=hex, base64, c, go, or rust, the number of bytes per line, or 0 to disable wrapping (defaults to 32 for hex, 48 for base64, and 12 otherwise)")
+var isJSON = flag.Bool("json", false, "treat the input as a JSON element tree, as output by der2ascii -json, instead of DER ASCII")
+var varsPath = flag.String("vars", "", "if provided, a file of variable definitions which take precedence over those in the input")
+
+// defineFlags collects the values of the repeatable -D flag.
+type defineFlags []string
+
+func (d *defineFlags) String() string {
+	return strings.Join(*d, " ")
+}
+
+func (d *defineFlags) Set(value string) error {
+	if !strings.Contains(value, "=") {
+		return fmt.Errorf("expected name=value, got %q", value)
+	}
+	*d = append(*d, value)
+	return nil
+}
+
+var defines defineFlags
+
+func init() {
+	flag.Var(&defines, "D", "define a variable as name=<DER ASCII fragment>, taking precedence over the input and -vars (may be repeated)")
+}
 
 func main() {
 	flag.Parse()
 
+	vars := ascii2der.NewVariables()
+	if *varsPath != "" {
+		varsBytes, err := ioutil.ReadFile(*varsPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading %s: %s\n", *varsPath, err)
+			os.Exit(1)
+		}
+		vars, err = ascii2der.ParseVariables(string(varsBytes))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Syntax error in %s: %s\n", *varsPath, err)
+			os.Exit(1)
+		}
+	}
+	for _, define := range defines {
+		name, value, _ := strings.Cut(define, "=")
+		if !ascii2der.IsVariableName(name) {
+			fmt.Fprintf(os.Stderr, "Invalid variable name %q\n", name)
+			os.Exit(1)
+		}
+		if err := vars.Define(name, value); err != nil {
+			fmt.Fprintf(os.Stderr, "Syntax error in -D %s: %s\n", name, err)
+			os.Exit(1)
+		}
+	}
+
 	if flag.NArg() > 0 {
 		fmt.Fprintf(os.Stderr, "Usage: %s [OPTION...]\n", os.Args[0])
 		flag.PrintDefaults()
@@ -52,17 +106,74 @@ func main() {
 		os.Exit(1)
 	}
 
-	outBytes, err := asciiToDER(string(inBytes))
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Syntax error: %s\n", err)
+	var outBytes []byte
+	var isPEM bool
+	if *isJSON {
+		if *varsPath != "" || len(defines) != 0 {
+			fmt.Fprintf(os.Stderr, "-D and -vars may not be used with -json\n")
+			os.Exit(1)
+		}
+		outBytes, isPEM, err = ascii2der.AssembleJSON(inBytes)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error decoding JSON: %s\n", err)
+			os.Exit(1)
+		}
+	} else {
+		outBytes, isPEM, err = ascii2der.Assemble(string(inBytes), vars)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Syntax error: %s\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if isPEM && *pemType != "" {
+		fmt.Fprintf(os.Stderr, "-pem may not be used with an input containing pem blocks\n")
 		os.Exit(1)
 	}
 
-	if *pemType != "" {
+	if *format == "" {
+		*format = "raw"
+		if *pemType != "" {
+			*format = "pem"
+		}
+	}
+	if isPEM && *format != "raw" {
+		fmt.Fprintf(os.Stderr, "-format=%s may not be used with an input containing pem blocks\n", *format)
+		os.Exit(1)
+	}
+	if (*format == "pem") != (*pemType != "") {
+		fmt.Fprintf(os.Stderr, "-pem must be provided if and only if -format=pem\n")
+		os.Exit(1)
+	}
+
+	switch *format {
+	case "raw":
+	case "pem":
 		outBytes = pem.EncodeToMemory(&pem.Block{
 			Type:  *pemType,
 			Bytes: outBytes,
 		})
+	case "hex":
+		if *wrap < 0 {
+			*wrap = 32
+		}
+		outBytes = formatHex(outBytes, *wrap)
+	case "base64":
+		if *wrap < 0 {
+			*wrap = 48
+		}
+		outBytes, err = formatBase64(outBytes, *wrap)
+	case "c", "go", "rust":
+		if *wrap < 0 {
+			*wrap = 12
+		}
+		outBytes, err = formatArray(outBytes, *format, *varName, *wrap)
+	default:
+		err = fmt.Errorf("unknown format %q", *format)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error formatting output: %s\n", err)
+		os.Exit(1)
 	}
 
 	outFile := os.Stdout
@@ -0,0 +1,220 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Input formats returned by sniffInputFormat.
+const (
+	inputRaw    = "raw"
+	inputPEM    = "pem"
+	inputHex    = "hex"
+	inputArray  = "array"
+	inputBase64 = "base64"
+	inputXXD    = "xxd"
+)
+
+// decodeHex decodes in as hex, ignoring punctuation and whitespace.
+func decodeHex(in []byte) ([]byte, error) {
+	stripped := strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || unicode.IsPunct(r) {
+			return -1
+		}
+		return r
+	}, string(in))
+	return hex.DecodeString(stripped)
+}
+
+// decodeArray decodes in as an array of comma-separated integers, each of
+// which may be decimal or use a 0x prefix for hex. If in contains curly braces
+// or square brackets, only the contents of the outermost pair are decoded, so
+// array literals from C, Go, Rust, etc., may be used directly.
+func decodeArray(in []byte) ([]byte, error) {
+	s := string(in)
+	if end := strings.LastIndexAny(s, "}]"); end >= 0 {
+		// Find the matching open bracket. Rust array types, for
+		// instance, also use square brackets.
+		openBracket, closeBracket := byte('{'), s[end]
+		if closeBracket == ']' {
+			openBracket = '['
+		}
+		depth := 0
+		for start := end; start >= 0; start-- {
+			if s[start] == closeBracket {
+				depth++
+			} else if s[start] == openBracket {
+				depth--
+				if depth == 0 {
+					s = s[start+1 : end]
+					break
+				}
+			}
+		}
+		if depth != 0 {
+			return nil, fmt.Errorf("unmatched %q", closeBracket)
+		}
+	}
+
+	var buf bytes.Buffer
+	for _, num := range strings.Split(s, ",") {
+		num = strings.TrimSpace(num)
+		if len(num) == 0 {
+			// Tolerate trailing commas and empty arrays.
+			continue
+		}
+		v, err := strconv.ParseUint(num, 0, 8)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteByte(byte(v))
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeBase64 decodes in as base64, ignoring whitespace. Padding is optional,
+// and both the standard and URL-safe alphabets are accepted.
+func decodeBase64(in []byte) ([]byte, error) {
+	stripped := strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, string(in))
+	stripped = strings.TrimRight(stripped, "=")
+	if strings.ContainsAny(stripped, "-_") {
+		return base64.RawURLEncoding.DecodeString(stripped)
+	}
+	return base64.RawStdEncoding.DecodeString(stripped)
+}
+
+// regexpDumpOffset matches the offset at the start of a line of a hex dump.
+// xxd uses "00000000: ", hexdump -C uses "00000000  ", and OpenSSL uses
+// "0000 - ".
+var regexpDumpOffset = regexp.MustCompile(`^[0-9a-fA-F]{4,}(: | - |  )`)
+
+// decodeXXD decodes in as a hex dump, such as the output of xxd, hexdump -C,
+// or OpenSSL's BIO_dump (used by openssl asn1parse -dump and others). Each
+// non-empty line must begin with an offset, followed by the data, and
+// optionally an ASCII rendering of the data separated by at least two spaces.
+func decodeXXD(in []byte) ([]byte, error) {
+	var out []byte
+	for i, line := range strings.Split(string(in), "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		if _, err := hex.DecodeString(line); err == nil && len(line) <= 8 {
+			// hexdump ends with a line containing only the final offset.
+			continue
+		}
+		loc := regexpDumpOffset.FindStringIndex(line)
+		if loc == nil {
+			return nil, fmt.Errorf("line %d: missing offset", i+1)
+		}
+		line = line[loc[1]:]
+
+		if idx := strings.LastIndex(line, "  |"); idx >= 0 && strings.HasSuffix(line, "|") {
+			// hexdump -C delimits the ASCII rendering with |, and
+			// splits the data into two columns.
+			line = strings.Replace(line[:idx], "  ", " ", 1)
+		} else {
+			// Otherwise, the data ends at the first pair of spaces.
+			line, _, _ = strings.Cut(line, "  ")
+		}
+		line = strings.Map(func(r rune) rune {
+			if r == ' ' || r == '-' {
+				return -1
+			}
+			return r
+		}, line)
+		b, err := hex.DecodeString(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", i+1, err)
+		}
+		out = append(out, b...)
+	}
+	if len(out) == 0 {
+		return nil, errors.New("no data found")
+	}
+	return out, nil
+}
+
+// isText returns true if in is entirely printable ASCII and whitespace.
+func isText(in []byte) bool {
+	for _, b := range in {
+		if b >= 0x80 || !(unicode.IsPrint(rune(b)) || unicode.IsSpace(rune(b))) {
+			return false
+		}
+	}
+	return true
+}
+
+// isHexWords returns true if in consists of words of hex digits of even
+// length, separated by whitespace and punctuation.
+func isHexWords(in []byte) bool {
+	words := strings.FieldsFunc(string(in), func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	})
+	for _, w := range words {
+		if len(w)%2 != 0 {
+			return false
+		}
+		if _, err := hex.DecodeString(w); err != nil {
+			return false
+		}
+	}
+	return len(words) != 0
+}
+
+// sniffInputFormat heuristically determines the format of in and returns
+// one of the input format constants.
+func sniffInputFormat(in []byte) string {
+	if !isText(in) {
+		return inputRaw
+	}
+	if bytes.Contains(in, []byte("-----BEGIN ")) {
+		return inputPEM
+	}
+	if _, err := decodeXXD(in); err == nil {
+		return inputXXD
+	}
+	if bytes.ContainsAny(in, "{[") || bytes.Contains(bytes.ToLower(in), []byte("0x")) {
+		if _, err := decodeArray(in); err == nil {
+			return inputArray
+		}
+	}
+	if isHexWords(in) {
+		return inputHex
+	}
+	if bytes.ContainsRune(in, ',') {
+		if _, err := decodeArray(in); err == nil {
+			return inputArray
+		}
+	}
+	if _, err := decodeBase64(in); err == nil {
+		return inputBase64
+	}
+	return inputRaw
+}
@@ -0,0 +1,124 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+var inputData = []byte{0x30, 0x06, 0x02, 0x01, 0x7c, 0x02, 0x01, 0x2e}
+
+type decodeInputTest struct {
+	in string
+	ok bool
+}
+
+func testDecodeInput(t *testing.T, name string, decode func([]byte) ([]byte, error), tests []decodeInputTest) {
+	for i, tt := range tests {
+		out, err := decode([]byte(tt.in))
+		if ok := err == nil; ok != tt.ok {
+			t.Errorf("%d. %s(%q) returned error %v, wanted success %v.", i, name, tt.in, err, tt.ok)
+		} else if ok && !bytes.Equal(out, inputData) {
+			t.Errorf("%d. %s(%q) = %x, wanted %x.", i, name, tt.in, out, inputData)
+		}
+	}
+}
+
+var decodeHexTests = []decodeInputTest{
+	{"300602017c02012e", true},
+	{"30 06 02 01 7C 02 01 2E\n", true},
+	{"30:06:02:01:7c:02:01:2e", true},
+	{"300602017c02012", false},
+	{"0x30", false},
+}
+
+func TestDecodeHex(t *testing.T) {
+	testDecodeInput(t, "decodeHex", decodeHex, decodeHexTests)
+}
+
+var decodeArrayTests = []decodeInputTest{
+	{"48, 6, 2, 1, 124, 2, 1, 46", true},
+	{"[48, 6, 2, 1, 124, 2, 1, 46]", true},
+	{"{0x30, 0x06, 0x02, 0x01, 0x7c, 0x02, 0x01, 0x2e,}", true},
+	{"unsigned char data[] = {\n  0x30, 0x06, 0x02, 0x01, 0x7c, 0x02, 0x01, 0x2e\n};\n", true},
+	{"var data = []byte{\n\t0x30, 0x06, 0x02, 0x01, 0x7c, 0x02, 0x01, 0x2e,\n}\n", true},
+	{"const DATA: [u8; 8] = [\n    0x30, 0x06, 0x02, 0x01, 0x7c, 0x02, 0x01, 0x2e,\n];\n", true},
+	{"[48, 6, 2, 1, 124, 2, 1, 256]", false},
+	{"[48, 6, 2, 1, 124, 2, 1, x]", false},
+	{"48, 6]]", false},
+}
+
+func TestDecodeArray(t *testing.T) {
+	testDecodeInput(t, "decodeArray", decodeArray, decodeArrayTests)
+}
+
+var decodeBase64Tests = []decodeInputTest{
+	{"MAYCAXwCAS4=", true},
+	{"MAYCAXwCAS4", true},
+	{"MAYC\nAXwC\nAS4=\n", true},
+	{"MAYCAXwCAS4!", false},
+}
+
+func TestDecodeBase64(t *testing.T) {
+	testDecodeInput(t, "decodeBase64", decodeBase64, decodeBase64Tests)
+}
+
+var decodeXXDTests = []decodeInputTest{
+	// xxd.
+	{"00000000: 3006 0201 7c02 012e                      0...|...\n", true},
+	// xxd -g1.
+	{"00000000: 30 06 02 01 7c 02 01 2e                          0...|...\n", true},
+	// hexdump -C.
+	{"00000000  30 06 02 01 7c 02 01 2e                           |0...|...|\n00000008\n", true},
+	{"00000000  30 06 02 01  7c 02 01 2e                           |0...|...|\n00000008\n", true},
+	// OpenSSL.
+	{"0000 - 30 06 02 01 7c 02-01 2e                           0...|...\n", true},
+	// Multiple lines.
+	{"00000000: 3006 0201  0...\n00000004: 7c02 012e  |...\n", true},
+	{"3006 0201 7c02 012e", false},
+	{"00000000: 3006 02zz 7c02 012e  0...|...\n", false},
+	{"", false},
+}
+
+func TestDecodeXXD(t *testing.T) {
+	testDecodeInput(t, "decodeXXD", decodeXXD, decodeXXDTests)
+}
+
+var sniffInputFormatTests = []struct {
+	in  string
+	out string
+}{
+	{string(inputData), inputRaw},
+	{"-----BEGIN CERTIFICATE-----\nMAYCAXwCAS4=\n-----END CERTIFICATE-----\n", inputPEM},
+	{"300602017c02012e", inputHex},
+	{"30:06:02:01:7c:02:01:2e", inputHex},
+	{"[48, 6, 2, 1, 124, 2, 1, 46]", inputArray},
+	{"48, 6, 2, 1, 124, 2, 1, 46", inputArray},
+	{"0x30, 0x06, 0x02, 0x01, 0x7c, 0x02, 0x01, 0x2e", inputArray},
+	{"var data = []byte{0x30, 0x06, 0x02, 0x01, 0x7c, 0x02, 0x01, 0x2e}", inputArray},
+	{"MAYCAXwCAS4=", inputBase64},
+	{"00000000: 3006 0201 7c02 012e                      0...|...\n", inputXXD},
+	{"0000 - 30 06 02 01 7c 02-01 2e                           0...|...\n", inputXXD},
+	{"not any of the above!", inputRaw},
+}
+
+func TestSniffInputFormat(t *testing.T) {
+	for i, tt := range sniffInputFormatTests {
+		if out := sniffInputFormat([]byte(tt.in)); out != tt.out {
+			t.Errorf("%d. sniffInputFormat(%q) = %s, wanted %s.", i, tt.in, out, tt.out)
+		}
+	}
+}
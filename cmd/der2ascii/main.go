@@ -15,32 +15,70 @@
 package main
 
 import (
-	"bytes"
+	"bufio"
 	"crypto/x509"
-	"encoding/hex"
 	"encoding/pem"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
-	"strconv"
+	"sort"
 	"strings"
-	"unicode"
-)
 
-var (
-	inPath      = flag.String("i", "", "input file to use (defaults to stdin)")
-	outPath     = flag.String("o", "", "output file to use (defaults to stdout)")
-	isPEM       = flag.Bool("pem", false, "treat the input as PEM and decode the first PEM block")
-	isPEMAll    = flag.Bool("pem-all", false, "treat the input as PEM and decode all PEM blocks")
-	pemPassword = flag.String("pem-password", "", "password to use when decrypting PEM blocks")
-	isHex       = flag.Bool("hex", false, "treat the input as hex, ignoring punctuation and whitespace")
-	isArray     = flag.Bool("array", false, "treat the input as a array of comma-separated integers")
+	"github.com/google/der-ascii/internal"
+	"github.com/google/der-ascii/internal/ascii2der"
+	"github.com/google/der-ascii/internal/der2ascii"
 )
 
 type input struct {
 	comment string
 	bytes   []byte
+	// pemBlock, if not nil, is the PEM block the input was decoded from.
+	pemBlock *pem.Block
+}
+
+// A limitedWriter writes at most limit bytes to w. Once the limit is reached,
+// it ends the line, writes a comment, and discards the remaining output.
+type limitedWriter struct {
+	w     io.Writer
+	limit int64
+	// n is the number of bytes which may still be written.
+	n         int64
+	truncated bool
+	// atLineStart is true if the output written so far ends a line.
+	atLineStart bool
+}
+
+func newLimitedWriter(w io.Writer, n int64) *limitedWriter {
+	return &limitedWriter{w: w, limit: n, n: n, atLineStart: true}
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if l.truncated {
+		return len(p), nil
+	}
+	if int64(len(p)) <= l.n {
+		l.n -= int64(len(p))
+		if len(p) != 0 {
+			l.atLineStart = p[len(p)-1] == '\n'
+		}
+		return l.w.Write(p)
+	}
+	out := append([]byte{}, p[:l.n]...)
+	if len(out) != 0 {
+		l.atLineStart = out[len(out)-1] == '\n'
+	}
+	if !l.atLineStart {
+		out = append(out, '\n')
+	}
+	out = append(out, fmt.Sprintf("# Output truncated to %d bytes.\n", l.limit)...)
+	l.truncated = true
+	if _, err := l.w.Write(out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
 }
 
 func boolToInt(b bool) int {
@@ -51,41 +89,302 @@ func boolToInt(b bool) int {
 	}
 }
 
-func main() {
-	flag.Parse()
+// isRegularFile returns whether f is a regular file, which may be read at
+// random offsets.
+func isRegularFile(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode().IsRegular()
+}
 
-	if flag.NArg() > 0 {
-		fmt.Fprintf(os.Stderr, "Usage: %s [OPTION...]\n", os.Args[0])
-		flag.PrintDefaults()
-		os.Exit(1)
+// openStream returns a random-access view of f and its size. If f is not a
+// regular file, such as a pipe, its contents are first copied to a temporary
+// file, in the directory given by $TMPDIR, so this requires disk space equal to
+// the size of the input and no output is written until the copy is done. If
+// limit is not zero, at most limit+1 bytes are copied, so callers can detect
+// truncation. The caller must call the returned cleanup function when done.
+func openStream(f *os.File, limit int64) (r io.ReaderAt, size int64, cleanup func(), err error) {
+	if info, err := f.Stat(); err == nil && info.Mode().IsRegular() {
+		return f, info.Size(), func() {}, nil
+	}
+	tmp, err := ioutil.TempFile("", "der2ascii")
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	cleanup = func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
 	}
+	var in io.Reader = f
+	if limit > 0 {
+		in = io.LimitReader(f, limit+1)
+	}
+	size, err = io.Copy(tmp, in)
+	if err != nil {
+		cleanup()
+		return nil, 0, nil, err
+	}
+	return tmp, size, cleanup, nil
+}
+
+// loadVocabulary returns the tag aliases specified by spec, a comma-separated
+// list of built-in vocabulary names or paths to files of vocabulary and
+// tag-alias pragmas.
+func loadVocabulary(spec string) (*internal.Vocabulary, error) {
+	builtins := internal.VocabularyNames()
+	v := new(internal.Vocabulary)
+	for _, name := range strings.Split(spec, ",") {
+		if i := sort.SearchStrings(builtins, name); i < len(builtins) && builtins[i] == name {
+			if err := v.AddVocabulary(name); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		text, err := ioutil.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("%q is neither a vocabulary (%s) nor a readable file: %s", name, strings.Join(builtins, ", "), err)
+		}
+		fileVocabulary, err := ascii2der.ParseVocabulary(string(text))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", name, err)
+		}
+		for _, b := range fileVocabulary.Builtins() {
+			if err := v.AddVocabulary(b); err != nil {
+				return nil, fmt.Errorf("%s: %s", name, err)
+			}
+		}
+		for _, a := range fileVocabulary.Custom() {
+			if err := v.Add(a.Name, a.Tag); err != nil {
+				return nil, fmt.Errorf("%s: %s", name, err)
+			}
+		}
+	}
+	return v, nil
+}
 
-	if boolToInt(*isPEM)+boolToInt(*isPEMAll)+boolToInt(*isHex)+boolToInt(*isArray) > 1 {
-		fmt.Fprintf(os.Stderr, "At most one of -pem, -pem-all, -hex, and -array may be specified.\n")
-		flag.PrintDefaults()
+// decryptPEMBlock returns block with its contents decrypted with password, or
+// block itself if password is empty.
+func decryptPEMBlock(block *pem.Block, password string) (*pem.Block, error) {
+	if password == "" {
+		return block, nil
+	}
+	bytes, err := x509.DecryptPEMBlock(block, []byte(password))
+	if err != nil {
+		return nil, fmt.Errorf("Error decrypting PEM block: %s", err)
+	}
+	return &pem.Block{Type: block.Type, Bytes: bytes}, nil
+}
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
 		os.Exit(1)
 	}
+}
+
+// run runs der2ascii with the command-line arguments args. Errors are returned
+// with a message to print.
+func run(args []string) error {
+	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s [OPTION...]\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	inPath := fs.String("i", "", "input file to use (defaults to stdin)")
+	outPath := fs.String("o", "", "output file to use (defaults to stdout)")
+	isPEM := fs.Bool("pem", false, "treat the input as PEM and decode the first PEM block")
+	isPEMAll := fs.Bool("pem-all", false, "treat the input as PEM and decode all PEM blocks")
+	pemPassword := fs.String("pem-password", "", "password to use when decrypting PEM blocks")
+	isHex := fs.Bool("hex", false, "treat the input as hex, ignoring punctuation and whitespace")
+	isArray := fs.Bool("array", false, "treat the input as a array of comma-separated integers, optionally in an array literal")
+	isBase64 := fs.Bool("base64", false, "treat the input as base64, ignoring whitespace")
+	isXXD := fs.Bool("xxd", false, "treat the input as a hex dump from xxd, hexdump -C, or OpenSSL")
+	isJSON := fs.Bool("json", false, "output a JSON tree of elements instead of DER ASCII")
+	isAuto := fs.Bool("auto", false, "detect whether the input is raw, PEM, hex, an array, base64, or a hex dump")
+	isPEMBlocks := fs.Bool("pem-blocks", false, "with -pem or -pem-all, output each PEM block as a pem block, so the output assembles back into PEM")
+	schemaPath := fs.String("schema", "", "ASN.1 module file used to annotate the output with field names")
+	schemaRoot := fs.String("schema-type", "", "with -schema or -profile, the type of the input (defaults to the first type in the module, or detected by the profile)")
+	profileName := fs.String("profile", "", "built-in schema used to annotate the output with field names (cms, kerberos, ldap, pkcs12, snmp, or x509)")
+	password := fs.String("password", "", "password used to decrypt PKCS #8 and PKCS #12 contents and verify PKCS #12 MACs; the plaintext is added as comments")
+	maxDepth := fs.Int("max-depth", der2ascii.DefaultMaxDepth, "maximum nesting depth to disassemble; deeper contents are written as hex (0 for no limit)")
+	maxElements := fs.Int("max-elements", 0, "maximum number of elements to disassemble; the remaining input is written as hex (0 for no limit)")
+	maxInput := fs.Int64("max-input", 0, "maximum number of input bytes to read; the rest is ignored (0 for no limit)")
+	maxOutput := fs.Int64("max-output", 0, "maximum number of bytes of output to write; the rest is replaced with a comment (0 for no limit)")
+	verify := fs.Bool("verify", false, "check that the output assembles back to the input with ascii2der")
+	spool := fs.Bool("spool", false, "when the input is not a regular file, such as stdin or a pipe, copy it to a temporary file in $TMPDIR so it is disassembled without reading it into memory; this needs disk space equal to the input, and no output is written until it is copied (by default, such input is read into memory)")
+	vocabulary := fs.String("vocabulary", "", "comma-separated tag vocabularies (kerberos, ldap, or snmp), or files of vocabulary and tag-alias pragmas, used to name non-universal tags")
+	fs.Parse(args)
+
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return fmt.Errorf("Unexpected argument %q", fs.Arg(0))
+	}
+
+	if boolToInt(*isPEM)+boolToInt(*isPEMAll)+boolToInt(*isHex)+boolToInt(*isArray)+boolToInt(*isBase64)+boolToInt(*isXXD)+boolToInt(*isAuto) > 1 {
+		return errors.New("At most one of -pem, -pem-all, -hex, -array, -base64, -xxd, and -auto may be specified.")
+	}
 
 	inFile := os.Stdin
 	if *inPath != "" {
 		var err error
 		inFile, err = os.Open(*inPath)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error opening %s: %s\n", *inPath, err)
-			os.Exit(1)
+			return fmt.Errorf("Error opening %s: %s", *inPath, err)
 		}
 		defer inFile.Close()
 	}
 
-	inBytes, err := ioutil.ReadAll(inFile)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error reading input: %s\n", err)
-		os.Exit(1)
+	var hasPassword bool
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "password" {
+			hasPassword = true
+		}
+	})
+
+	// Raw input in a regular file is disassembled as it is read, so large
+	// inputs do not need to fit in memory. The other formats, -json, and
+	// -verify need the input in memory first. Streaming reads the input at
+	// random offsets, so other input is read into memory too, unless -spool
+	// copies it to a temporary file.
+	streaming := !*isPEM && !*isPEMAll && !*isHex && !*isArray && !*isBase64 && !*isXXD && !*isAuto && !*isJSON && !hasPassword && !*verify
+	if streaming && !*spool && !isRegularFile(inFile) {
+		streaming = false
+	}
+
+	if *maxDepth < 0 || *maxElements < 0 || *maxInput < 0 || *maxOutput < 0 {
+		return errors.New("-max-depth, -max-elements, -max-input, and -max-output may not be negative")
+	}
+
+	var inBytes []byte
+	var inputTruncated bool
+	var err error
+	if !streaming {
+		var r io.Reader = inFile
+		if *maxInput > 0 {
+			// Read one more byte to detect truncation.
+			r = io.LimitReader(inFile, *maxInput+1)
+		}
+		inBytes, err = ioutil.ReadAll(r)
+		if err != nil {
+			return fmt.Errorf("Error reading input: %s", err)
+		}
+		if *maxInput > 0 && int64(len(inBytes)) > *maxInput {
+			inBytes = inBytes[:*maxInput]
+			inputTruncated = true
+		}
+	}
+	truncatedNote := fmt.Sprintf("# Input truncated to %d bytes.\n", *maxInput)
+
+	if *isAuto {
+		switch sniffInputFormat(inBytes) {
+		case inputPEM:
+			*isPEMAll = true
+		case inputHex:
+			*isHex = true
+		case inputArray:
+			*isArray = true
+		case inputBase64:
+			*isBase64 = true
+		case inputXXD:
+			*isXXD = true
+		}
 	}
 
 	if *pemPassword != "" && !*isPEM && !*isPEMAll {
-		fmt.Fprintf(os.Stderr, "-pem-password provided, but neither -pem nor -pem-all provided\n")
-		os.Exit(1)
+		return errors.New("-pem-password provided, but neither -pem nor -pem-all provided")
+	}
+
+	if *isPEMBlocks && !*isPEM && !*isPEMAll {
+		return errors.New("-pem-blocks provided, but neither -pem nor -pem-all provided")
+	}
+
+	if *isPEMBlocks && *isJSON {
+		return errors.New("-pem-blocks and -json may not both be specified")
+	}
+
+	if *schemaRoot != "" && *schemaPath == "" && *profileName == "" {
+		return errors.New("-schema-type provided, but neither -schema nor -profile provided")
+	}
+
+	if *schemaPath != "" && *profileName != "" {
+		return errors.New("-schema and -profile may not both be specified")
+	}
+
+	if (*schemaPath != "" || *profileName != "") && *isJSON {
+		return errors.New("-schema and -profile may not be combined with -json")
+	}
+
+	if hasPassword && *isJSON {
+		return errors.New("-password may not be combined with -json")
+	}
+
+	if *vocabulary != "" && *isJSON {
+		return errors.New("-vocabulary may not be combined with -json")
+	}
+
+	opts := &der2ascii.Options{
+		MaxDepth:    *maxDepth,
+		MaxElements: *maxElements,
+		SchemaType:  *schemaRoot,
+		Decrypt:     hasPassword,
+		Password:    *password,
+	}
+
+	if *vocabulary != "" {
+		opts.Vocabulary, err = loadVocabulary(*vocabulary)
+		if err != nil {
+			return fmt.Errorf("Error loading vocabulary: %s", err)
+		}
+	}
+
+	if *profileName != "" {
+		names := der2ascii.ProfileNames()
+		if i := sort.SearchStrings(names, *profileName); i == len(names) || names[i] != *profileName {
+			return fmt.Errorf("Unknown profile %q. Available profiles: %s", *profileName, strings.Join(names, ", "))
+		}
+		opts.Schema, err = der2ascii.LoadProfile(*profileName)
+		if err != nil {
+			return fmt.Errorf("Error loading profile %s: %s", *profileName, err)
+		}
+		if *schemaRoot != "" && !opts.Schema.HasType(*schemaRoot) {
+			return fmt.Errorf("Type %s is not defined in profile %s", *schemaRoot, *profileName)
+		}
+		if name := opts.Schema.Vocabulary(); name != "" {
+			// Name the profile's tags in addition to any vocabularies
+			// from -vocabulary.
+			if opts.Vocabulary == nil {
+				opts.Vocabulary = new(internal.Vocabulary)
+			}
+			if err := opts.Vocabulary.AddVocabulary(name); err != nil {
+				return fmt.Errorf("Error loading profile %s: %s", *profileName, err)
+			}
+		}
+	} else if *schemaPath != "" {
+		schemaBytes, err := ioutil.ReadFile(*schemaPath)
+		if err != nil {
+			return fmt.Errorf("Error reading %s: %s", *schemaPath, err)
+		}
+		opts.Schema, err = der2ascii.ParseSchema(string(schemaBytes))
+		if err != nil {
+			return fmt.Errorf("Error parsing %s: %s", *schemaPath, err)
+		}
+		if *schemaRoot != "" && !opts.Schema.HasType(*schemaRoot) {
+			return fmt.Errorf("Type %s is not defined in %s", *schemaRoot, *schemaPath)
+		}
+	}
+
+	var r io.ReaderAt
+	var size int64
+	if streaming {
+		var cleanup func()
+		r, size, cleanup, err = openStream(inFile, *maxInput)
+		if err != nil {
+			return fmt.Errorf("Error reading input: %s", err)
+		}
+		defer cleanup()
+		if *maxInput > 0 && size > *maxInput {
+			size = *maxInput
+			inputTruncated = true
+		}
 	}
 
 	var inputs []input
@@ -96,68 +395,47 @@ func main() {
 			if pemBlock == nil {
 				break
 			}
-			if *pemPassword != "" {
-				bytes, err := x509.DecryptPEMBlock(pemBlock, []byte(*pemPassword))
-				if err != nil {
-					fmt.Fprintf(os.Stderr, "Error decrypting PEM block: %s\n", err)
-					os.Exit(1)
-				}
-				inputs = append(inputs, input{comment: pemBlock.Type, bytes: bytes})
-			} else {
-				inputs = append(inputs, input{comment: pemBlock.Type, bytes: pemBlock.Bytes})
+			if pemBlock, err = decryptPEMBlock(pemBlock, *pemPassword); err != nil {
+				return err
 			}
+			inputs = append(inputs, input{comment: pemBlock.Type, bytes: pemBlock.Bytes, pemBlock: pemBlock})
 		}
 		if len(inputs) == 0 {
-			fmt.Fprintf(os.Stderr, "-pem-all provided, but input could not be parsed as PEM\n")
-			os.Exit(1)
+			return errors.New("-pem-all provided, but input could not be parsed as PEM")
 		}
 	} else if *isPEM {
 		pemBlock, _ := pem.Decode(inBytes)
 		if pemBlock == nil {
-			fmt.Fprintf(os.Stderr, "-pem provided, but input could not be parsed as PEM\n")
-			os.Exit(1)
+			return errors.New("-pem provided, but input could not be parsed as PEM")
 		}
-		if *pemPassword != "" {
-			bytes, err := x509.DecryptPEMBlock(pemBlock, []byte(*pemPassword))
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error decrypting PEM block: %s\n", err)
-				os.Exit(1)
-			}
-			inputs = []input{{bytes: bytes}}
-		} else {
-			inputs = []input{{bytes: pemBlock.Bytes}}
+		if pemBlock, err = decryptPEMBlock(pemBlock, *pemPassword); err != nil {
+			return err
 		}
+		inputs = []input{{bytes: pemBlock.Bytes, pemBlock: pemBlock}}
 	} else if *isHex {
-		stripped := strings.Map(func(r rune) rune {
-			if unicode.IsSpace(r) || unicode.IsPunct(r) {
-				return -1
-			}
-			return r
-		}, string(inBytes))
-		inBytes, err = hex.DecodeString(stripped)
+		inBytes, err = decodeHex(inBytes)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "-hex provided, but input could not be parsed as hex: %s\n", err)
-			os.Exit(1)
+			return fmt.Errorf("-hex provided, but input could not be parsed as hex: %s", err)
 		}
 		inputs = []input{{bytes: inBytes}}
 	} else if *isArray {
-		// Trim whitespace and brackets.
-		trimmed := strings.TrimFunc(string(inBytes), func(r rune) bool {
-			return r == '[' || r == ']' || r == '{' || r == '}' || r == ',' || unicode.IsSpace(r)
-		})
-		nums := strings.Split(trimmed, ",")
-		var buf bytes.Buffer
-		buf.Grow(len(nums))
-		for _, num := range nums {
-			num = strings.TrimSpace(num)
-			v, err := strconv.ParseUint(num, 0, 8)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error decoding array: %s\n", err)
-				os.Exit(1)
-			}
-			buf.WriteByte(byte(v))
+		inBytes, err = decodeArray(inBytes)
+		if err != nil {
+			return fmt.Errorf("Error decoding array: %s", err)
+		}
+		inputs = []input{{bytes: inBytes}}
+	} else if *isBase64 {
+		inBytes, err = decodeBase64(inBytes)
+		if err != nil {
+			return fmt.Errorf("-base64 provided, but input could not be parsed as base64: %s", err)
+		}
+		inputs = []input{{bytes: inBytes}}
+	} else if *isXXD {
+		inBytes, err = decodeXXD(inBytes)
+		if err != nil {
+			return fmt.Errorf("-xxd provided, but input could not be parsed as a hex dump: %s", err)
 		}
-		inputs = []input{{bytes: buf.Bytes()}}
+		inputs = []input{{bytes: inBytes}}
 	} else {
 		inputs = []input{{bytes: inBytes}}
 	}
@@ -166,27 +444,91 @@ func main() {
 	if *outPath != "" {
 		outFile, err = os.Create(*outPath)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error opening %s: %s\n", *outPath, err)
-			os.Exit(1)
+			return fmt.Errorf("Error opening %s: %s", *outPath, err)
 		}
 		defer outFile.Close()
 	}
+	var w io.Writer = outFile
+	if *maxOutput > 0 && !*isJSON {
+		w = newLimitedWriter(outFile, *maxOutput)
+	}
+	// Write errors are sticky in a bufio.Writer, so they are checked once,
+	// when flushing.
+	out := bufio.NewWriter(w)
+	out.WriteString(der2ascii.VocabularyHeader(opts.Vocabulary))
+	if inputTruncated {
+		if *isJSON {
+			fmt.Fprintf(os.Stderr, "Warning: input truncated to %d bytes\n", *maxInput)
+		} else {
+			out.WriteString(truncatedNote)
+		}
+	}
+
+	if streaming {
+		if err := der2ascii.WriteASCII(out, r, size, opts); err != nil {
+			return fmt.Errorf("Error: %s", err)
+		}
+		if err := out.Flush(); err != nil {
+			return fmt.Errorf("Error writing output: %s", err)
+		}
+		return nil
+	}
+
+	var written int64
 	for i, inp := range inputs {
-		if len(inp.comment) > 0 {
+		if *isJSON {
+			doc, err := der2ascii.DERToJSON(inp.bytes, inp.comment, opts)
+			if err != nil {
+				return fmt.Errorf("Error encoding JSON: %s", err)
+			}
+			if *verify {
+				if err := der2ascii.VerifyJSON(inp.bytes, doc); err != nil {
+					return fmt.Errorf("Verification failed: %s", err)
+				}
+			}
+			// Truncated JSON would not parse, so fail instead.
+			written += int64(len(doc))
+			if *maxOutput > 0 && written > *maxOutput {
+				return fmt.Errorf("Error: JSON output exceeds %d bytes", *maxOutput)
+			}
+			out.Write(doc)
+			continue
+		}
+		if *isPEMBlocks {
 			if i > 0 {
-				if _, err := outFile.WriteString("\n"); err != nil {
-					fmt.Fprintf(os.Stderr, "Error writing output: %s\n", err)
-					os.Exit(1)
+				out.WriteString("\n")
+			}
+			text, err := der2ascii.PEMBlockToASCII(inp.pemBlock, opts)
+			if err != nil {
+				return fmt.Errorf("Error: %s", err)
+			}
+			if *verify {
+				if err := der2ascii.VerifyPEMBlock(inp.pemBlock, text, opts.Vocabulary); err != nil {
+					return fmt.Errorf("Verification failed: %s", err)
 				}
 			}
-			if _, err := fmt.Fprintf(outFile, "# %s\n", inp.comment); err != nil {
-				fmt.Fprintf(os.Stderr, "Error writing output: %s\n", err)
-				os.Exit(1)
+			out.WriteString(text)
+			continue
+		}
+		if len(inp.comment) > 0 {
+			if i > 0 {
+				out.WriteString("\n")
 			}
+			fmt.Fprintf(out, "# %s\n", inp.comment)
 		}
-		if _, err := outFile.WriteString(derToASCII(inp.bytes)); err != nil {
-			fmt.Fprintf(os.Stderr, "Error writing output: %s\n", err)
-			os.Exit(1)
+		text, err := der2ascii.DERToASCII(inp.bytes, opts)
+		if err != nil {
+			return fmt.Errorf("Error: %s", err)
+		}
+		if *verify {
+			if err := der2ascii.VerifyASCII(inp.bytes, text, opts.Vocabulary); err != nil {
+				return fmt.Errorf("Verification failed: %s", err)
+			}
 		}
+		out.WriteString(text)
+	}
+	if err := out.Flush(); err != nil {
+		return fmt.Errorf("Error writing output: %s", err)
 	}
+	return nil
 }
@@ -0,0 +1,57 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package der2ascii disassembles DER and BER into the DER ASCII language, as
+// the der2ascii command does. Programs may render the bodies of additional
+// types by registering renderers with Register, typically from an init
+// function, before calling Disassemble.
+package der2ascii
+
+import (
+	"io"
+
+	"github.com/google/der-ascii/internal/der2ascii"
+)
+
+// DefaultMaxDepth is the nesting depth disassembled if Options does not
+// specify one.
+const DefaultMaxDepth = der2ascii.DefaultMaxDepth
+
+// Options configures Disassemble.
+type Options struct {
+	// MaxDepth is the maximum nesting depth to disassemble. The contents
+	// of elements nested any deeper are written as hex. If zero,
+	// DefaultMaxDepth is used. If negative, there is no limit.
+	MaxDepth int
+	// MaxElements is the maximum number of elements to disassemble. The
+	// input after that is written as hex. If zero, there is no limit.
+	MaxElements int
+}
+
+// Disassemble writes the DER ASCII disassembly of in to w. The output always
+// assembles back to in.
+func Disassemble(w io.Writer, in []byte, opts Options) error {
+	maxDepth := opts.MaxDepth
+	if maxDepth == 0 {
+		maxDepth = DefaultMaxDepth
+	} else if maxDepth < 0 {
+		maxDepth = 0
+	}
+	text, err := der2ascii.DERToASCII(in, &der2ascii.Options{MaxDepth: maxDepth, MaxElements: opts.MaxElements})
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, text)
+	return err
+}
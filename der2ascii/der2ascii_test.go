@@ -0,0 +1,93 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package der2ascii
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+var disassembleTests = []struct {
+	in   []byte
+	opts Options
+	out  string
+}{
+	{
+		[]byte{0x30, 0x05, 0x30, 0x03, 0x02, 0x01, 0x01},
+		Options{},
+		"SEQUENCE {\n  SEQUENCE {\n    INTEGER { 1 }\n  }\n}\n",
+	},
+	{
+		[]byte{0x30, 0x05, 0x30, 0x03, 0x02, 0x01, 0x01},
+		Options{MaxDepth: 1},
+		"SEQUENCE {\n  # Depth limit of 1 reached.\n  `3003020101`\n}\n",
+	},
+	{
+		[]byte{0x30, 0x05, 0x30, 0x03, 0x02, 0x01, 0x01},
+		Options{MaxElements: 1},
+		"SEQUENCE {\n  # Element limit of 1 reached.\n  `3003020101`\n}\n",
+	},
+}
+
+func TestDisassemble(t *testing.T) {
+	for i, tt := range disassembleTests {
+		var out bytes.Buffer
+		if err := Disassemble(&out, tt.in, tt.opts); err != nil {
+			t.Errorf("%d. Disassemble(%x, %+v) failed: %s", i, tt.in, tt.opts, err)
+		} else if out.String() != tt.out {
+			t.Errorf("%d. Disassemble(%x, %+v) = %q, want %q.", i, tt.in, tt.opts, out.String(), tt.out)
+		}
+	}
+}
+
+// renderLength renders bodies as their length, which only assembles back to
+// the body if it is the single byte it renders.
+func renderLength(elem Element) (string, []string, bool) {
+	return fmt.Sprintf("%d", len(elem.Body)), []string{"length"}, true
+}
+
+// renderMultiLine renders one-byte bodies correctly, but with a comment which
+// spans several lines.
+func renderMultiLine(elem Element) (string, []string, bool) {
+	if len(elem.Body) != 1 {
+		return "", nil, false
+	}
+	return fmt.Sprintf("%d", elem.Body[0]), []string{"line 1\nSEQUENCE {"}, true
+}
+
+func TestRegister(t *testing.T) {
+	Register(Tag{Class: ClassPrivate, Number: 1}, "", Renderer{Render: renderLength})
+	Register(Tag{Class: ClassPrivate, Number: 2}, "", Renderer{Render: renderMultiLine})
+
+	tests := []struct {
+		in  []byte
+		out string
+	}{
+		{[]byte{0xc1, 0x01, 0x01}, "# length\n[PRIVATE 1 PRIMITIVE] { 1 }\n"},
+		// Output which does not assemble back to the body is discarded.
+		{[]byte{0xc1, 0x01, 0x05}, "[PRIVATE 1 PRIMITIVE] { `05` }\n"},
+		// So is output with comments which end their line early.
+		{[]byte{0xc2, 0x01, 0x01}, "[PRIVATE 2 PRIMITIVE] { `01` }\n"},
+	}
+	for i, tt := range tests {
+		var out bytes.Buffer
+		if err := Disassemble(&out, tt.in, Options{}); err != nil {
+			t.Errorf("%d. Disassemble(%x) failed: %s", i, tt.in, err)
+		} else if out.String() != tt.out {
+			t.Errorf("%d. Disassemble(%x) = %q, want %q.", i, tt.in, out.String(), tt.out)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package der2ascii
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"strings"
+
+	"github.com/google/der-ascii/internal"
+	"github.com/google/der-ascii/internal/ascii2der"
+	"github.com/google/der-ascii/internal/der2ascii"
+)
+
+// A Class is the class of a tag, as encoded in the two high bits of its first
+// byte.
+type Class byte
+
+const (
+	ClassUniversal       Class = 0x0
+	ClassApplication     Class = 0x40
+	ClassContextSpecific Class = 0x80
+	ClassPrivate         Class = 0xc0
+)
+
+// A Tag identifies the type of an element.
+type Tag struct {
+	Class  Class
+	Number uint64
+}
+
+// An Element is the primitive element passed to a Renderer.
+type Element struct {
+	Tag  Tag
+	Body []byte
+}
+
+// A Renderer renders the bodies of primitive elements of some type.
+type Renderer struct {
+	// Render, if not nil, returns the value to write between elem's curly
+	// braces, which must assemble to elem's body, and comments to write
+	// before the element. If ok is false, or the value does not assemble to
+	// the body, the body is written as bytes.
+	Render func(elem Element) (value string, comments []string, ok bool)
+	// Decoded is true if bodies are always rendered based on their type.
+	// Otherwise, Render is only used if the body does not look like ASN.1.
+	Decoded bool
+}
+
+func (t Tag) internal() internal.Tag {
+	return internal.Tag{Class: internal.Class(t.Class), Number: t.Number}
+}
+
+func newElement(elem der2ascii.Element) Element {
+	return Element{Tag: Tag{Class: Class(elem.Tag.Class), Number: elem.Tag.Number}, Body: elem.Body}
+}
+
+// checkRendered returns whether value and comments, rendered for body, may be
+// written. value must assemble to body, and comments may not end their line
+// early.
+func checkRendered(body []byte, value string, comments []string) bool {
+	for _, c := range comments {
+		if strings.ContainsAny(c, "\r\n") {
+			return false
+		}
+	}
+	want, err := asn1.Marshal(body)
+	if err != nil {
+		return false
+	}
+	got, _, err := ascii2der.Assemble("OCTET_STRING { "+value+" }", nil)
+	return err == nil && bytes.Equal(got, want)
+}
+
+// Register registers r for primitive elements with the specified tag. If context is not empty, r only applies to
+// elements in the context of that dotted OID, which is the value of the last
+// OBJECT IDENTIFIER preceding the element as a sibling of it or of one of its
+// ancestors, and takes precedence over a renderer registered without one. It
+// replaces any renderer previously registered with the same tag and context,
+// including the built-in ones. It may be called concurrently with Disassemble.
+func Register(tag Tag, context string, r Renderer) {
+	ir := der2ascii.Renderer{Decoded: r.Decoded}
+	if r.Render != nil {
+		ir.Render = func(elem der2ascii.Element) (string, []string, bool) {
+			value, comments, ok := r.Render(newElement(elem))
+			if !ok || !checkRendered(elem.Body, value, comments) {
+				return "", nil, false
+			}
+			return value, comments, true
+		}
+	}
+	der2ascii.Register(tag.internal(), context, ir)
+}
@@ -0,0 +1,89 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ascii2der assembles DER ASCII, and the JSON element trees output by
+// der2ascii -json, into bytes. It implements the ascii2der command and is also
+// used by der2ascii to check that its output assembles back to its input.
+package ascii2der
+
+import (
+	"fmt"
+
+	"github.com/google/der-ascii/internal"
+)
+
+// Variables contains variable values, keyed by name without the leading $.
+type Variables struct {
+	values map[string][]token
+}
+
+// NewVariables returns an empty set of variables.
+func NewVariables() *Variables {
+	return &Variables{values: make(map[string][]token)}
+}
+
+// ParseVariables parses text as a series of variable definitions and returns
+// the resulting values.
+func ParseVariables(text string) (*Variables, error) {
+	values, err := parseVariablesFile(text)
+	if err != nil {
+		return nil, err
+	}
+	return &Variables{values: values}, nil
+}
+
+// ParseVocabulary parses text as a series of vocabulary and tag-alias pragmas
+// and returns the resulting tag aliases.
+func ParseVocabulary(text string) (*internal.Vocabulary, error) {
+	return parseVocabularyFile(text)
+}
+
+// IsVariableName returns whether name, without the leading $, is a valid
+// variable name.
+func IsVariableName(name string) bool {
+	return isVariable("$" + name)
+}
+
+// Define sets the variable name to value, which must be a DER ASCII fragment
+// with balanced curly braces.
+func (v *Variables) Define(name, value string) error {
+	if !IsVariableName(name) {
+		return fmt.Errorf("invalid variable name %q", name)
+	}
+	tokens, err := parseVariableValue(value)
+	if err != nil {
+		return err
+	}
+	v.values[name] = tokens
+	return nil
+}
+
+// Assemble assembles input, a DER ASCII document. If vars is not nil, it
+// specifies variable values which take precedence over the definitions in
+// input. Assemble additionally returns whether input contained pem blocks, in
+// which case the output is already PEM-encoded.
+func Assemble(input string, vars *Variables) (out []byte, isPEM bool, err error) {
+	var values map[string][]token
+	if vars != nil {
+		values = vars.values
+	}
+	return asciiToDERWithVariables(input, values)
+}
+
+// AssembleJSON assembles input, a JSON element tree. It additionally returns
+// whether the tree specified a PEM type, in which case the output is already
+// PEM-encoded.
+func AssembleJSON(input []byte) (out []byte, isPEM bool, err error) {
+	return jsonToDER(input)
+}
@@ -0,0 +1,55 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ascii2der
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestVariables(t *testing.T) {
+	vars, err := ParseVariables("define $a { INTEGER { 1 } } define $b { NULL {} }")
+	if err != nil {
+		t.Fatalf("ParseVariables failed: %s", err)
+	}
+	if err := vars.Define("a", "INTEGER { 2 }"); err != nil {
+		t.Fatalf("Define failed: %s", err)
+	}
+	if err := vars.Define("1a", "NULL {}"); err == nil {
+		t.Errorf("Define unexpectedly accepted an invalid name.")
+	}
+	if err := vars.Define("c", "SEQUENCE {"); err == nil {
+		t.Errorf("Define unexpectedly accepted unbalanced curly braces.")
+	}
+	if err := vars.Define("c", "vocabulary kerberos"); err == nil {
+		t.Errorf("Define unexpectedly accepted a pragma.")
+	}
+
+	out, _, err := Assemble("define $a { INTEGER { 3 } } SEQUENCE { $a $b }", vars)
+	if err != nil {
+		t.Fatalf("Assemble failed: %s", err)
+	}
+	if want := []byte{0x30, 0x05, 0x02, 0x01, 0x02, 0x05, 0x00}; !bytes.Equal(out, want) {
+		t.Errorf("Assemble returned %x, wanted %x.", out, want)
+	}
+
+	out, _, err = Assemble("define $a { INTEGER { 3 } } $a", NewVariables())
+	if err != nil {
+		t.Fatalf("Assemble failed: %s", err)
+	}
+	if want := []byte{0x02, 0x01, 0x03}; !bytes.Equal(out, want) {
+		t.Errorf("Assemble returned %x, wanted %x.", out, want)
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ascii2der
+
+// An assembler accumulates output in a single buffer. The length of a
+// definite-length element is not known until the element is closed, so lengths
+// are encoded separately and spliced in when the output is complete. This
+// copies each byte of output a constant number of times, regardless of how
+// deeply it is nested.
+type assembler struct {
+	// data is the output, excluding the lengths.
+	data []byte
+	// lengths are the positions of the lengths in data, in order.
+	lengths []lengthSplice
+	// lengthData contains the encoded lengths, in the order they were
+	// closed.
+	lengthData []byte
+}
+
+// A lengthSplice is the position of a length in the output.
+type lengthSplice struct {
+	// offset is the offset in data to insert the length.
+	offset int
+	// start and end are the location of the encoded length in lengthData.
+	// They are unset until the element is closed.
+	start, end int
+}
+
+// An openLength is a length which has been opened with openLength but not yet
+// closed.
+type openLength struct {
+	index         int
+	dataStart     int
+	lengthDataLen int
+}
+
+func (a *assembler) write(b []byte) {
+	a.data = append(a.data, b...)
+}
+
+// openLength reserves space for the length of an element whose contents are
+// written next.
+func (a *assembler) openLength() openLength {
+	a.lengths = append(a.lengths, lengthSplice{offset: len(a.data)})
+	return openLength{index: len(a.lengths) - 1, dataStart: len(a.data), lengthDataLen: len(a.lengthData)}
+}
+
+// contentsLength returns the length of everything written since l was opened,
+// including the lengths of any nested elements. Those elements must already
+// be closed.
+func (a *assembler) contentsLength(l openLength) int {
+	return len(a.data) - l.dataStart + len(a.lengthData) - l.lengthDataLen
+}
+
+// closeLength encodes length as the length reserved by l. lengthLength is
+// interpreted as in appendLength.
+func (a *assembler) closeLength(l openLength, length, lengthLength int) error {
+	start := len(a.lengthData)
+	lengthData, err := appendLength(a.lengthData, length, lengthLength)
+	if err != nil {
+		return err
+	}
+	a.lengthData = lengthData
+	a.lengths[l.index].start = start
+	a.lengths[l.index].end = len(a.lengthData)
+	return nil
+}
+
+// bytes returns the assembled output. All lengths must be closed.
+func (a *assembler) bytes() []byte {
+	if len(a.lengths) == 0 {
+		return a.data
+	}
+	out := make([]byte, 0, len(a.data)+len(a.lengthData))
+	var prev int
+	for _, l := range a.lengths {
+		out = append(out, a.data[prev:l.offset]...)
+		out = append(out, a.lengthData[l.start:l.end]...)
+		prev = l.offset
+	}
+	return append(out, a.data[prev:]...)
+}
@@ -0,0 +1,88 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ascii2der
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/google/der-ascii/internal"
+)
+
+// decodeIterations decodes value, the contents of an INTEGER token, as an
+// iteration count.
+func decodeIterations(value []byte) (int, error) {
+	if len(value) == 0 || len(value) > 4 || value[0]&0x80 != 0 {
+		return 0, errors.New("invalid iteration count")
+	}
+	var n int64
+	for _, b := range value {
+		n = n<<8 | int64(b)
+	}
+	// Enforce a limit of int32, purely so that the limits are not
+	// target-specific.
+	if n < 1 || n > math.MaxInt32 {
+		return 0, fmt.Errorf("invalid iteration count %d", n)
+	}
+	return int(n), nil
+}
+
+// encryptAESCBC encrypts plaintext with AES-CBC and PKCS #7 padding. The size
+// of key selects AES-128, AES-192, or AES-256.
+func encryptAESCBC(key, iv, plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != block.BlockSize() {
+		return nil, fmt.Errorf("invalid AES-CBC IV length %d", len(iv))
+	}
+	pad := block.BlockSize() - len(plaintext)%block.BlockSize()
+	out := append(append([]byte{}, plaintext...), bytes.Repeat([]byte{byte(pad)}, pad)...)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(out, out)
+	return out, nil
+}
+
+// pbes2IV is the IV used by encryptPBES2. A fixed IV keeps the output
+// deterministic without another argument, and it must be written in the
+// AlgorithmIdentifier anyway.
+var pbes2IV = make([]byte, aes.BlockSize)
+
+// encryptPBES2 encrypts plaintext with PBES2, as specified in RFC 8018,
+// section 6.2, using PBKDF2 with hmacWithSHA256 and aes256-CBC, which match
+// OpenSSL's defaults, and an IV of zeros.
+func encryptPBES2(password, salt []byte, iterations int, plaintext []byte) ([]byte, error) {
+	key := internal.PBKDF2(sha256.New, password, salt, iterations, 32)
+	return encryptAESCBC(key, pbes2IV, plaintext)
+}
+
+// pkcs12MAC computes the PKCS #12 MAC of content, as specified in RFC 7292,
+// section 5, using HMAC-SHA256. password is interpreted as UTF-8 and encoded
+// as a BMPString.
+func pkcs12MAC(password, salt []byte, iterations int, content []byte) ([]byte, error) {
+	key, err := internal.PKCS12KDF(sha256.New, internal.PKCS12MACID, internal.PKCS12Password(string(password)), salt, iterations, sha256.Size)
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(content)
+	return mac.Sum(nil), nil
+}
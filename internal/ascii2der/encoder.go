@@ -0,0 +1,301 @@
+// Copyright 2015 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ascii2der
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/google/der-ascii/internal"
+)
+
+func appendBase128(dst []byte, value *big.Int) []byte {
+	dst, err := appendBase128WithLength(dst, value, 0)
+	if err != nil {
+		// Only a length override can fail.
+		panic(err)
+	}
+	return dst
+}
+
+// appendBase128WithLength appends value, which must be non-negative and may be
+// of any size, in base-128 to dst. If length is non-zero, the value is padded
+// to length bytes.
+func appendBase128WithLength(dst []byte, value *big.Int, length int) ([]byte, error) {
+	// Count how many bytes are needed.
+	l := (value.BitLen() + 6) / 7
+	// Special-case: zero is encoded with one, not zero bytes.
+	if l == 0 {
+		l = 1
+	}
+	// Apply the length override.
+	if length != 0 {
+		if length < l {
+			return nil, fmt.Errorf("length override of %d is too small, need at least %d bytes", length, l)
+		}
+		l = length
+	}
+	// Fill in the digits from the least significant, reading the value's
+	// bytes rather than shifting it, so this takes linear time.
+	in := value.Bytes()
+	start := len(dst)
+	dst = append(dst, make([]byte, l)...)
+	var acc, bits uint
+	for i := l - 1; i >= 0; i-- {
+		for bits < 7 && len(in) != 0 {
+			acc |= uint(in[len(in)-1]) << bits
+			bits += 8
+			in = in[:len(in)-1]
+		}
+		b := byte(acc) & 0x7f
+		acc >>= 7
+		if bits >= 7 {
+			bits -= 7
+		} else {
+			bits = 0
+		}
+		if i != l-1 {
+			b |= 0x80
+		}
+		dst[start+i] = b
+	}
+	return dst, nil
+}
+
+// appendTag marshals the given tag and appends the result to dst, returning the
+// updated slice.
+func appendTag(dst []byte, tag internal.Tag) ([]byte, error) {
+	b := byte(tag.Class)
+	if tag.Constructed {
+		b |= 0x20
+	}
+	if tag.Number < 0x1f && tag.BigNumber == "" && tag.LongFormOverride == 0 {
+		// Low-tag-number form.
+		b |= byte(tag.Number)
+		return append(dst, b), nil
+	}
+
+	// High-tag-number form.
+	b |= 0x1f
+	dst = append(dst, b)
+	number := new(big.Int).SetUint64(tag.Number)
+	if tag.BigNumber != "" {
+		if _, ok := number.SetString(tag.BigNumber, 10); !ok || number.Sign() < 0 {
+			return nil, fmt.Errorf("invalid tag number %q", tag.BigNumber)
+		}
+	}
+	return appendBase128WithLength(dst, number, tag.LongFormOverride)
+}
+
+// appendLength marshals the given length in DER and appends the result to dst,
+// returning the updated slice.
+func appendLength(dst []byte, length, lengthLength int) ([]byte, error) {
+	if length < 0x80 && lengthLength == 0 {
+		// Short-form length.
+		return append(dst, byte(length)), nil
+	}
+
+	// Long-form length. Count how many bytes are needed.
+	var l int
+	for n := length; n != 0; n >>= 8 {
+		l++
+	}
+	if lengthLength != 0 {
+		if lengthLength > 127 {
+			return nil, errors.New("length override too large")
+		}
+		if lengthLength < l {
+			return nil, fmt.Errorf("length override of %d too small, need at least %d bytes", lengthLength, l)
+		}
+		l = lengthLength
+	}
+	dst = append(dst, 0x80|byte(l))
+	for ; l > 0; l-- {
+		dst = append(dst, byte(length>>uint(8*(l-1))))
+	}
+	return dst, nil
+}
+
+// appendInteger marshals the given value as the contents of a DER INTEGER and
+// appends the result to dst, returning the updated slice.
+func appendInteger(dst []byte, value int64) []byte {
+	// Count how many bytes are needed.
+	l := 1
+	for n := value; n > 0x7f || n < (0x80-0x100); n >>= 8 {
+		l++
+	}
+
+	for ; l > 0; l-- {
+		dst = append(dst, byte(value>>uint(8*(l-1))))
+	}
+	return dst
+}
+
+// appendBigInteger marshals the given value as the contents of a DER INTEGER
+// and appends the result to dst, returning the updated slice.
+func appendBigInteger(dst []byte, value *big.Int) []byte {
+	if value.Sign() >= 0 {
+		b := value.Bytes()
+		if len(b) == 0 || b[0]&0x80 != 0 {
+			dst = append(dst, 0)
+		}
+		return append(dst, b...)
+	}
+
+	// The two's complement encoding of value is the bitwise inverse of
+	// -value - 1.
+	b := new(big.Int).Sub(new(big.Int).Neg(value), big.NewInt(1)).Bytes()
+	if len(b) == 0 || b[0]&0x80 != 0 {
+		dst = append(dst, 0xff)
+	}
+	for _, v := range b {
+		dst = append(dst, ^v)
+	}
+	return dst
+}
+
+// appendNamedBits marshals a BIT STRING with the bits at the given positions
+// set as the contents of its DER encoding, and appends the result to dst,
+// returning the updated slice. As in DER's encoding of named bit lists,
+// trailing zero bits are removed.
+func appendNamedBits(dst []byte, positions []int) []byte {
+	bitLen := 0
+	for _, p := range positions {
+		if p+1 > bitLen {
+			bitLen = p + 1
+		}
+	}
+	byteLen := (bitLen + 7) / 8
+	dst = append(dst, byte(byteLen*8-bitLen))
+	start := len(dst)
+	dst = append(dst, make([]byte, byteLen)...)
+	for _, p := range positions {
+		dst[start+p/8] |= 0x80 >> uint(p%8)
+	}
+	return dst
+}
+
+// appendReal marshals the given value as the contents of a DER REAL and
+// appends the result to dst, returning the updated slice.
+func appendReal(dst []byte, value float64) []byte {
+	switch {
+	case math.IsInf(value, 1):
+		return append(dst, 0x40)
+	case math.IsInf(value, -1):
+		return append(dst, 0x41)
+	case math.IsNaN(value):
+		return append(dst, 0x42)
+	case value == 0 && math.Signbit(value):
+		return append(dst, 0x43)
+	case value == 0:
+		return dst
+	}
+
+	// DER requires base 2, no scale factor, and an odd mantissa.
+	frac, exp := math.Frexp(math.Abs(value))
+	mantissa := uint64(math.Ldexp(frac, 53))
+	exp -= 53
+	for mantissa&1 == 0 {
+		mantissa >>= 1
+		exp++
+	}
+	dst, err := appendRealBinary(dst, value < 0, new(big.Int).SetUint64(mantissa), 2, 0, big.NewInt(int64(exp)), 0)
+	if err != nil {
+		// Only explicit parameters can fail.
+		panic(err)
+	}
+	return dst
+}
+
+// appendRealBinary marshals the value sign * mantissa * 2^scale *
+// base^exponent in the binary encoding of a REAL and appends the result to dst,
+// returning the updated slice. The mantissa must be non-negative. If expLength
+// is non-zero, the exponent is padded to expLength bytes.
+func appendRealBinary(dst []byte, negative bool, mantissa *big.Int, base, scale int, exponent *big.Int, expLength int) ([]byte, error) {
+	b := byte(0x80)
+	if negative {
+		b |= 0x40
+	}
+	switch base {
+	case 2:
+	case 8:
+		b |= 0x10
+	case 16:
+		b |= 0x20
+	default:
+		return nil, fmt.Errorf("invalid REAL base %d", base)
+	}
+	if scale < 0 || scale > 3 {
+		return nil, fmt.Errorf("invalid REAL scale factor %d", scale)
+	}
+	b |= byte(scale) << 2
+
+	exp := appendBigInteger(nil, exponent)
+	if expLength != 0 {
+		if expLength < len(exp) {
+			return nil, fmt.Errorf("exponent length of %d is too small, need at least %d bytes", expLength, len(exp))
+		}
+		pad := byte(0x00)
+		if exponent.Sign() < 0 {
+			pad = 0xff
+		}
+		for len(exp) < expLength {
+			exp = append([]byte{pad}, exp...)
+		}
+	}
+	if len(exp) <= 3 {
+		dst = append(dst, b|byte(len(exp)-1))
+	} else if len(exp) <= 0xff {
+		dst = append(dst, b|3, byte(len(exp)))
+	} else {
+		return nil, errors.New("REAL exponent too large")
+	}
+	dst = append(dst, exp...)
+	return append(dst, mantissa.Bytes()...), nil
+}
+
+// appendObjectIdentifier marshals the given OID, whose components may be of any
+// size, as the contents of a DER OBJECT IDENTIFIER and appends the result to
+// dst. It returns the updated slice and whether the OID was valid.
+func appendObjectIdentifier(dst []byte, value []*big.Int) ([]byte, bool) {
+	// Validate the input before anything is written.
+	if len(value) < 2 {
+		return dst, false
+	}
+	for _, v := range value {
+		if v.Sign() < 0 {
+			return dst, false
+		}
+	}
+	if value[0].Cmp(big.NewInt(2)) > 0 || (value[0].Cmp(big.NewInt(2)) < 0 && value[1].Cmp(big.NewInt(39)) > 0) {
+		return dst, false
+	}
+
+	first := new(big.Int).Mul(value[0], big.NewInt(40))
+	dst = appendBase128(dst, first.Add(first, value[1]))
+	for _, v := range value[2:] {
+		dst = appendBase128(dst, v)
+	}
+	return dst, true
+}
+
+func appendRelativeOID(dst []byte, value []*big.Int) []byte {
+	for _, v := range value {
+		dst = appendBase128(dst, v)
+	}
+	return dst
+}
@@ -0,0 +1,356 @@
+// Copyright 2015 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ascii2der
+
+import (
+	"bytes"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/google/der-ascii/internal"
+)
+
+var appendTagTests = []struct {
+	tag     internal.Tag
+	ok      bool
+	encoded []byte
+}{
+	{internal.Tag{Class: internal.ClassUniversal, Number: 16, Constructed: true}, true, []byte{0x30}},
+	{internal.Tag{Class: internal.ClassUniversal, Number: 16, Constructed: true, LongFormOverride: 1}, true, []byte{0x3f, 0x10}},
+	{internal.Tag{Class: internal.ClassUniversal, Number: 16, Constructed: true, LongFormOverride: 2}, true, []byte{0x3f, 0x80, 0x10}},
+	{internal.Tag{Class: internal.ClassUniversal, Number: 2}, true, []byte{0x02}},
+	{internal.Tag{Class: internal.ClassContextSpecific, Number: 1, Constructed: true}, true, []byte{0xa1}},
+	{internal.Tag{Class: internal.ClassApplication, Number: 1234, Constructed: true}, true, []byte{0x7f, 0x89, 0x52}},
+	// Override is too small.
+	{internal.Tag{Class: internal.ClassApplication, Number: 1234, Constructed: true, LongFormOverride: 1}, false, nil},
+	{internal.Tag{Class: internal.ClassApplication, Number: 1234, Constructed: true, LongFormOverride: 2}, true, []byte{0x7f, 0x89, 0x52}},
+	{internal.Tag{Class: internal.ClassApplication, Number: 1234, Constructed: true, LongFormOverride: 3}, true, []byte{0x7f, 0x80, 0x89, 0x52}},
+	// Large tag numbers.
+	{internal.Tag{Class: internal.ClassPrivate, Number: math.MaxUint64}, true, []byte{0xdf, 0x81, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f}},
+	{internal.Tag{Class: internal.ClassPrivate, BigNumber: "18446744073709551616"}, true, []byte{0xdf, 0x82, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x00}},
+	{internal.Tag{Class: internal.ClassPrivate, LongFormOverride: 11, BigNumber: "18446744073709551616"}, true, []byte{0xdf, 0x80, 0x82, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x00}},
+}
+
+func TestAppendTag(t *testing.T) {
+	for i, tt := range appendTagTests {
+		dst, err := appendTag(nil, tt.tag)
+		if err != nil {
+			if tt.ok {
+				t.Errorf("%d. appendTag(nil, %v) unexpectedly failed: %s.", i, tt.tag, err)
+			}
+		} else if !tt.ok {
+			t.Errorf("%d. appendTag(nil, %v) unexpectedly succeeded.", i, tt.tag)
+		} else {
+			if !bytes.Equal(dst, tt.encoded) {
+				t.Errorf("%d. appendTag(nil, %v) = %v, wanted %v.", i, tt.tag, dst, tt.encoded)
+			}
+
+			dst, err = appendTag(dst, tt.tag)
+			if err != nil {
+				t.Errorf("%d. appendTag(dst, %v) unexpected failed: %s.", i, tt.tag, err)
+			} else if l := len(tt.encoded); len(dst) != l*2 || !bytes.Equal(dst[:l], tt.encoded) || !bytes.Equal(dst[l:], tt.encoded) {
+				t.Errorf("%d. appendTag did not preserve existing contents.", i)
+			}
+		}
+	}
+}
+
+var appendLengthTests = []struct {
+	length       int
+	lengthLength int
+	ok           bool
+	encoded      []byte
+}{
+	{0, 0, true, []byte{0}},
+	{0, 1, true, []byte{0x81, 0x00}},
+	{5, 0, true, []byte{0x05}},
+	{5, 1, true, []byte{0x81, 0x05}},
+	{5, 2, true, []byte{0x82, 0x00, 0x05}},
+	{0x1f, 0, true, []byte{0x1f}},
+	{0x80, 0, true, []byte{0x81, 0x80}},
+	{0xff, 0, true, []byte{0x81, 0xff}},
+	{0x100, 0, true, []byte{0x82, 0x01, 0x00}},
+	{0xffffff, 0, true, []byte{0x83, 0xff, 0xff, 0xff}},
+	{0xffffff, 1, false, nil},
+	{0xffffff, 2, false, nil},
+	{0xffffff, 3, true, []byte{0x83, 0xff, 0xff, 0xff}},
+	{0xffffff, 4, true, []byte{0x84, 0x00, 0xff, 0xff, 0xff}},
+	{0xffffff, 128, false, nil},
+	{0, -1, false, nil},
+	// Longest possible long-form length, and test for a potential overflow.
+	{1, 127, true, append([]byte{0xff}, append(make([]byte, 126), 1)...)},
+}
+
+func TestAppendLength(t *testing.T) {
+	for i, tt := range appendLengthTests {
+		dst, err := appendLength(nil, tt.length, tt.lengthLength)
+		if err != nil {
+			if tt.ok {
+				t.Errorf("%d. appendLength(nil, %v, %v) unexpectedly failed: %s.", i, tt.length, tt.lengthLength, err)
+			}
+		} else if !tt.ok {
+			t.Errorf("%d. appendLength(nil, %v, %v) unexpectedly succeeded.", i, tt.length, tt.lengthLength)
+		} else {
+			if !bytes.Equal(dst, tt.encoded) {
+				t.Errorf("%d. appendLength(nil, %v, %v) = %v, wanted %v.", i, tt.length, tt.lengthLength, dst, tt.encoded)
+			}
+
+			dst, err = appendLength(dst, tt.length, tt.lengthLength)
+			if err != nil {
+				t.Errorf("%d. appendLength(dst, %v, %v) unexpected failed: %s.", i, tt.length, tt.lengthLength, err)
+			} else if l := len(tt.encoded); len(dst) != l*2 || !bytes.Equal(dst[:l], tt.encoded) || !bytes.Equal(dst[l:], tt.encoded) {
+				t.Errorf("%d. appendLength did not preserve existing contents.", i)
+			}
+		}
+	}
+}
+
+var appendIntegerTests = []struct {
+	value   int64
+	encoded []byte
+}{
+	{0, []byte{0}},
+	{1, []byte{1}},
+	{-1, []byte{0xff}},
+	{127, []byte{0x7f}},
+	{128, []byte{0x00, 0x80}},
+	{0x12345678, []byte{0x12, 0x34, 0x56, 0x78}},
+	{-127, []byte{0x81}},
+	{-128, []byte{0x80}},
+	{-129, []byte{0xff, 0x7f}},
+}
+
+func TestAppendInteger(t *testing.T) {
+	for i, tt := range appendIntegerTests {
+		dst := appendInteger(nil, tt.value)
+		if !bytes.Equal(dst, tt.encoded) {
+			t.Errorf("%d. appendInteger(nil, %v) = %v, wanted %v.", i, tt.value, dst, tt.encoded)
+		}
+
+		dst = appendInteger(dst, tt.value)
+		if l := len(tt.encoded); len(dst) != l*2 || !bytes.Equal(dst[:l], tt.encoded) || !bytes.Equal(dst[l:], tt.encoded) {
+			t.Errorf("%d. appendInteger did not preserve existing contents.", i)
+		}
+	}
+}
+
+var appendBigIntegerTests = []struct {
+	value string
+	out   []byte
+}{
+	{"0", []byte{0}},
+	{"1", []byte{1}},
+	{"-1", []byte{0xff}},
+	{"127", []byte{0x7f}},
+	{"128", []byte{0x00, 0x80}},
+	{"256", []byte{0x01, 0x00}},
+	{"-128", []byte{0x80}},
+	{"-129", []byte{0xff, 0x7f}},
+	{"18446744073709551615", []byte{0x00, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+	{"-18446744073709551616", []byte{0xff, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}},
+}
+
+func TestAppendBigInteger(t *testing.T) {
+	for i, tt := range appendBigIntegerTests {
+		value, ok := new(big.Int).SetString(tt.value, 10)
+		if !ok {
+			t.Fatalf("%d. could not parse %q", i, tt.value)
+		}
+		out := appendBigInteger(nil, value)
+		if !bytes.Equal(out, tt.out) {
+			t.Errorf("%d. appendBigInteger(nil, %v) = %v, wanted %v.", i, tt.value, out, tt.out)
+		}
+	}
+}
+
+var appendRealTests = []struct {
+	value   float64
+	encoded []byte
+}{
+	{0, nil},
+	{math.Copysign(0, -1), []byte{0x43}},
+	{math.Inf(1), []byte{0x40}},
+	{math.Inf(-1), []byte{0x41}},
+	{math.NaN(), []byte{0x42}},
+	{1, []byte{0x80, 0x00, 0x01}},
+	{1.5, []byte{0x80, 0xff, 0x03}},
+	{-0.375, []byte{0xc0, 0xfd, 0x03}},
+	{1024, []byte{0x80, 0x0a, 0x01}},
+	{0.1, []byte{0x80, 0xc9, 0x0c, 0xcc, 0xcc, 0xcc, 0xcc, 0xcc, 0xcd}},
+	{math.Ldexp(1, 200), []byte{0x81, 0x00, 0xc8, 0x01}},
+	{math.SmallestNonzeroFloat64, []byte{0x81, 0xfb, 0xce, 0x01}},
+}
+
+func TestAppendReal(t *testing.T) {
+	for i, tt := range appendRealTests {
+		dst := appendReal(nil, tt.value)
+		if !bytes.Equal(dst, tt.encoded) {
+			t.Errorf("%d. appendReal(nil, %v) = %x, wanted %x.", i, tt.value, dst, tt.encoded)
+		}
+	}
+}
+
+var appendRealBinaryTests = []struct {
+	negative  bool
+	mantissa  int64
+	base      int
+	scale     int
+	exponent  int64
+	expLength int
+	encoded   []byte
+}{
+	{false, 3, 2, 0, -1, 0, []byte{0x80, 0xff, 0x03}},
+	{true, 5, 16, 0, 3, 0, []byte{0xe0, 0x03, 0x05}},
+	{false, 3, 8, 1, -1, 0, []byte{0x94, 0xff, 0x03}},
+	// Mantissas are not normalized.
+	{false, 2, 2, 0, 0, 0, []byte{0x80, 0x00, 0x02}},
+	{false, 0, 2, 0, 0, 0, []byte{0x80, 0x00}},
+	// Exponents may be padded.
+	{false, 1, 2, 0, 0, 2, []byte{0x81, 0x00, 0x00, 0x01}},
+	{false, 1, 2, 0, -1, 3, []byte{0x82, 0xff, 0xff, 0xff, 0x01}},
+	{false, 1, 2, 0, 1, 4, []byte{0x83, 0x04, 0x00, 0x00, 0x00, 0x01, 0x01}},
+	{false, 1, 2, 0, 1 << 40, 0, []byte{0x83, 0x06, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01}},
+	// Invalid parameters.
+	{false, 1, 10, 0, 0, 0, nil},
+	{false, 1, 2, 4, 0, 0, nil},
+	{false, 1, 2, 0, 256, 1, nil},
+	{false, 1, 2, 0, 0, 256, nil},
+}
+
+func TestAppendRealBinary(t *testing.T) {
+	for i, tt := range appendRealBinaryTests {
+		dst, err := appendRealBinary(nil, tt.negative, big.NewInt(tt.mantissa), tt.base, tt.scale, big.NewInt(tt.exponent), tt.expLength)
+		if tt.encoded == nil {
+			if err == nil {
+				t.Errorf("%d. appendRealBinary unexpectedly succeeded.", i)
+			}
+		} else if err != nil {
+			t.Errorf("%d. appendRealBinary failed: %s", i, err)
+		} else if !bytes.Equal(dst, tt.encoded) {
+			t.Errorf("%d. appendRealBinary = %x, wanted %x.", i, dst, tt.encoded)
+		}
+	}
+}
+
+// splitOID returns the components of a dotted OID.
+func splitOID(s string) []*big.Int {
+	var oid []*big.Int
+	if s == "" {
+		return oid
+	}
+	for _, c := range strings.Split(s, ".") {
+		v, ok := new(big.Int).SetString(c, 10)
+		if !ok {
+			panic(c)
+		}
+		oid = append(oid, v)
+	}
+	return oid
+}
+
+var appendNamedBitsTests = []struct {
+	positions []int
+	encoded   []byte
+}{
+	{nil, []byte{0x00}},
+	{[]int{0}, []byte{0x07, 0x80}},
+	{[]int{7}, []byte{0x00, 0x01}},
+	{[]int{8}, []byte{0x07, 0x00, 0x80}},
+	{[]int{5, 0, 5}, []byte{0x02, 0x84}},
+}
+
+func TestAppendNamedBits(t *testing.T) {
+	for i, tt := range appendNamedBitsTests {
+		if dst := appendNamedBits(nil, tt.positions); !bytes.Equal(dst, tt.encoded) {
+			t.Errorf("%d. appendNamedBits(nil, %v) = %x, wanted %x.", i, tt.positions, dst, tt.encoded)
+		}
+	}
+}
+
+var appendObjectIdentifierTests = []struct {
+	value   string
+	encoded []byte
+	ok      bool
+}{
+	{"0.1", []byte{1}, true},
+	{"1.2.3.4.0.127.128.129", []byte{42, 3, 4, 0, 0x7f, 0x81, 0x00, 0x81, 0x01}, true},
+	{"2.1", []byte{81}, true},
+	{"2.4294967215", []byte{0x8f, 0xff, 0xff, 0xff, 0x7f}, true},
+	{"2.4294967216", []byte{0x90, 0x80, 0x80, 0x80, 0x00}, true},
+	// A UUID-based OID.
+	{"2.25.329800735698586629295641978511506172919", []byte{0x69, 0x83, 0xf0, 0x9d, 0xa7, 0xeb, 0xcf, 0xde, 0xe0, 0xc7, 0xa1, 0xa7, 0xb2, 0xc0, 0x94, 0x8c, 0xc8, 0xf9, 0xd7, 0x77}, true},
+	// Invalid OIDs.
+	{"", nil, false},
+	{"1", nil, false},
+	{"1.40", nil, false},
+	{"0.40", nil, false},
+	{"3.1", nil, false},
+	{"1.-1", nil, false},
+}
+
+func TestAppendObjectIdentifier(t *testing.T) {
+	for i, tt := range appendObjectIdentifierTests {
+		dst, ok := appendObjectIdentifier(nil, splitOID(tt.value))
+		if !tt.ok {
+			if ok {
+				t.Errorf("%d. appendObjectIdentifier(nil, %v) unexpectedly suceeded.", i, tt.value)
+			} else if len(dst) != 0 {
+				t.Errorf("%d. appendObjectIdentifier did not preserve input.", i)
+			}
+		} else if !bytes.Equal(dst, tt.encoded) {
+			t.Errorf("%d. appendObjectIdentifier(nil, %v) = %v, wanted %v.", i, tt.value, dst, tt.encoded)
+		}
+
+		dst = []byte{0}
+		dst, ok = appendObjectIdentifier(dst, splitOID(tt.value))
+		if !tt.ok {
+			if ok {
+				t.Errorf("%d. appendObjectIdentifier(nil, %v) unexpectedly suceeded.", i, tt.value)
+			} else if !bytes.Equal(dst, []byte{0}) {
+				t.Errorf("%d. appendObjectIdentifier did not preserve input.", i)
+			}
+		} else if l := len(tt.encoded); len(dst) != l+1 || dst[0] != 0 || !bytes.Equal(dst[1:], tt.encoded) {
+			t.Errorf("%d. appendObjectIdentifier did not preserve existing contents.", i)
+		}
+	}
+}
+
+var appendRelativeOIDTests = []struct {
+	value   string
+	encoded []byte
+}{
+	{"1", []byte{1}},
+	{"1.2.3.4.0.127.128.129", []byte{1, 2, 3, 4, 0, 0x7f, 0x81, 0x00, 0x81, 0x01}},
+	{"4294967295", []byte{0x8f, 0xff, 0xff, 0xff, 0x7f}},
+	{"18446744073709551616", []byte{0x82, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x00}},
+	// This is not actually valid, but the tokenizer will never try to serialize it.
+	{"", []byte{}},
+}
+
+func TestAppendRelativeOID(t *testing.T) {
+	for i, tt := range appendRelativeOIDTests {
+		dst := appendRelativeOID(nil, splitOID(tt.value))
+		if !bytes.Equal(dst, tt.encoded) {
+			t.Errorf("%d. appendRelativeOID(nil, %v) = %v, wanted %v.", i, tt.value, dst, tt.encoded)
+		}
+
+		dst = appendRelativeOID(dst, splitOID(tt.value))
+		if l := len(tt.encoded); len(dst) != l*2 || !bytes.Equal(dst[:l], tt.encoded) || !bytes.Equal(dst[l:], tt.encoded) {
+			t.Errorf("%d. appendRelativeOID did not preserve existing contents.", i)
+		}
+	}
+}
@@ -0,0 +1,342 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ascii2der
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/der-ascii/internal"
+)
+
+// A jsonTag is a tag in a JSON element tree. It may be written either as a
+// string containing a tag expression, with or without the square brackets, or
+// as an object in the form output by der2ascii -json.
+type jsonTag struct {
+	tag internal.Tag
+}
+
+func (t *jsonTag) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		s = strings.TrimSuffix(strings.TrimPrefix(s, "["), "]")
+		t.tag, err = decodeTagString(s, nil)
+		return err
+	}
+
+	var obj struct {
+		Class       *string      `json:"class"`
+		Number      *json.Number `json:"number"`
+		Constructed *bool        `json:"constructed"`
+		Alias       string       `json:"alias"`
+		LongForm    int          `json:"long_form"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&obj); err != nil {
+		return err
+	}
+
+	if obj.Alias != "" {
+		var ok bool
+		t.tag, ok = internal.TagByName(obj.Alias)
+		if !ok {
+			return fmt.Errorf("unknown tag alias %q", obj.Alias)
+		}
+	} else {
+		// As in tag expressions, tags default to constructed,
+		// context-specific.
+		if obj.Number == nil {
+			return errors.New("tag must have an alias or a number")
+		}
+		t.tag = internal.Tag{Class: internal.ClassContextSpecific, Constructed: true}
+	}
+	if obj.Class != nil {
+		switch *obj.Class {
+		case "UNIVERSAL":
+			t.tag.Class = internal.ClassUniversal
+		case "APPLICATION":
+			t.tag.Class = internal.ClassApplication
+		case "CONTEXT-SPECIFIC":
+			t.tag.Class = internal.ClassContextSpecific
+		case "PRIVATE":
+			t.tag.Class = internal.ClassPrivate
+		default:
+			return fmt.Errorf("unknown tag class %q", *obj.Class)
+		}
+	}
+	if obj.Number != nil {
+		var err error
+		t.tag.Number, t.tag.BigNumber, err = decodeTagNumber(string(*obj.Number))
+		if err != nil {
+			return err
+		}
+	}
+	if obj.Constructed != nil {
+		t.tag.Constructed = *obj.Constructed
+	}
+	if obj.LongForm < 0 {
+		return errors.New("invalid long-form override")
+	}
+	t.tag.LongFormOverride = obj.LongForm
+	return nil
+}
+
+// A jsonValue is a typed element body in a JSON element tree.
+type jsonValue struct {
+	Integer     json.Number `json:"integer"`
+	OID         string      `json:"oid"`
+	RelativeOID string      `json:"relative_oid"`
+	// Real is the value of a REAL token, without the "real:" prefix.
+	Real    string `json:"real"`
+	Boolean *bool  `json:"boolean"`
+	// Bits is the contents of a bit string literal.
+	Bits *string `json:"bits"`
+	// String is encoded as UTF-16 or UTF-32 for BMPString and
+	// UniversalString, respectively, and UTF-8 otherwise.
+	String *string `json:"string"`
+
+	// The following fields are output by der2ascii -json but ignored.
+	OIDName    json.RawMessage `json:"oid_name"`
+	UnusedBits json.RawMessage `json:"unused_bits"`
+}
+
+// A jsonNode is a node in a JSON element tree. If Raw is set, it is a string
+// of bytes. Otherwise, it is an element with the specified tag.
+type jsonNode struct {
+	// Raw is a hex-encoded string of bytes to emit as-is.
+	Raw *string `json:"raw"`
+
+	Tag *jsonTag `json:"tag"`
+	// HeaderOnly, if true, specifies that only the tag is emitted. The
+	// length and body, if any, are specified separately.
+	HeaderOnly bool `json:"header_only"`
+	// Indefinite, if true, specifies an indefinite length. If MissingEOC
+	// is also true, the end-of-contents marker is omitted.
+	Indefinite bool `json:"indefinite"`
+	MissingEOC bool `json:"missing_eoc"`
+	// LongForm, if non-zero, is the number of bytes to encode the length
+	// with in long form, excluding the initial byte.
+	LongForm int `json:"long_form"`
+	// AdjustLength is added to the length before encoding.
+	AdjustLength int `json:"adjust_length"`
+
+	// The body is the first of Children, Bytes, and Value which is set. If
+	// none are set, the body is empty.
+	Children []jsonNode `json:"children"`
+	Bytes    *string    `json:"bytes"`
+	Value    *jsonValue `json:"value"`
+
+	// The following fields are output by der2ascii -json but ignored.
+	Offset       json.RawMessage `json:"offset"`
+	HeaderLength json.RawMessage `json:"header_length"`
+	BodyOffset   json.RawMessage `json:"body_offset"`
+	BodyLength   json.RawMessage `json:"body_length"`
+	Note         json.RawMessage `json:"note"`
+}
+
+// A jsonDocument is a JSON element tree. It may alternatively be written as
+// an array of nodes.
+type jsonDocument struct {
+	// PEMType, if not empty, specifies the output is PEM-encoded with the
+	// specified type.
+	PEMType  string     `json:"pem_type"`
+	Elements []jsonNode `json:"elements"`
+}
+
+func (v *jsonValue) encode(tag internal.Tag) ([]byte, error) {
+	switch {
+	case v.Integer != "":
+		n, ok := new(big.Int).SetString(string(v.Integer), 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid integer %q", v.Integer)
+		}
+		return appendBigInteger(nil, n), nil
+	case v.OID != "":
+		return decodeObjectIdentifierString(v.OID)
+	case v.RelativeOID != "":
+		return decodeRelativeOIDString(v.RelativeOID)
+	case v.Real != "":
+		return decodeReal(realPrefix + v.Real)
+	case v.Boolean != nil:
+		if *v.Boolean {
+			return []byte{0xff}, nil
+		}
+		return []byte{0x00}, nil
+	case v.Bits != nil:
+		return decodeBitString(*v.Bits)
+	case v.String != nil:
+		var out []byte
+		name, _, _ := tag.GetAlias()
+		for _, r := range *v.String {
+			switch name {
+			case "BMPString":
+				out = appendUTF16(out, r)
+			case "UniversalString":
+				out = appendUTF32(out, r)
+			default:
+				out = utf8.AppendRune(out, r)
+			}
+		}
+		return out, nil
+	}
+	return nil, errors.New("value is empty")
+}
+
+// appendTokens appends DER ASCII tokens for n to tokens and returns the
+// result. path describes n's location in the tree for error messages.
+func (n *jsonNode) appendTokens(tokens []token, path string) ([]token, error) {
+	if n.Raw != nil {
+		if n.Tag != nil {
+			return nil, fmt.Errorf("%s: raw nodes may not have a tag", path)
+		}
+		b, err := hex.DecodeString(*n.Raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", path, err)
+		}
+		return append(tokens, token{Kind: tokenBytes, Value: b}), nil
+	}
+
+	if n.Tag == nil {
+		return nil, fmt.Errorf("%s: node must have a tag or be raw", path)
+	}
+	tag, err := appendTag(nil, n.Tag.tag)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+	tokens = append(tokens, token{Kind: tokenBytes, Value: tag})
+	if n.HeaderOnly {
+		return tokens, nil
+	}
+
+	if n.MissingEOC {
+		if !n.Indefinite {
+			return nil, fmt.Errorf("%s: missing_eoc requires indefinite", path)
+		}
+		tokens = append(tokens, token{Kind: tokenBytes, Value: []byte{0x80}})
+	} else {
+		if n.Indefinite {
+			tokens = append(tokens, token{Kind: tokenIndefinite})
+		}
+		if n.LongForm < 0 {
+			return nil, fmt.Errorf("%s: invalid long-form override", path)
+		}
+		if n.LongForm != 0 {
+			tokens = append(tokens, token{Kind: tokenLongForm, Length: n.LongForm})
+		}
+		if n.AdjustLength != 0 {
+			tokens = append(tokens, token{Kind: tokenAdjustLength, Length: n.AdjustLength})
+		}
+		tokens = append(tokens, token{Kind: tokenLeftCurly})
+	}
+
+	switch {
+	case n.Children != nil:
+		for i := range n.Children {
+			tokens, err = n.Children[i].appendTokens(tokens, fmt.Sprintf("%s.children[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+		}
+	case n.Bytes != nil:
+		b, err := hex.DecodeString(*n.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", path, err)
+		}
+		tokens = append(tokens, token{Kind: tokenBytes, Value: b})
+	case n.Value != nil:
+		b, err := n.Value.encode(n.Tag.tag)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", path, err)
+		}
+		tokens = append(tokens, token{Kind: tokenBytes, Value: b})
+	}
+
+	if !n.MissingEOC {
+		tokens = append(tokens, token{Kind: tokenRightCurly})
+	}
+	return tokens, nil
+}
+
+// jsonToDER assembles input, a series of JSON element trees. It additionally
+// returns whether any tree specified a PEM type, in which case the
+// corresponding output is PEM-encoded.
+func jsonToDER(input []byte) (out []byte, isPEM bool, err error) {
+	dec := json.NewDecoder(bytes.NewReader(input))
+	for i := 0; ; i++ {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, false, err
+		}
+
+		var doc jsonDocument
+		docDec := json.NewDecoder(bytes.NewReader(raw))
+		docDec.DisallowUnknownFields()
+		if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && trimmed[0] == '[' {
+			err = docDec.Decode(&doc.Elements)
+		} else {
+			err = docDec.Decode(&doc)
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("document %d: %s", i, err)
+		}
+
+		var tokens []token
+		for j := range doc.Elements {
+			tokens, err = doc.Elements[j].appendTokens(tokens, fmt.Sprintf("document %d: elements[%d]", i, j))
+			if err != nil {
+				return nil, false, err
+			}
+		}
+		scanner := newScanner("")
+		scanner.expansions = []*expansion{{tokens: tokens, remaining: 1}}
+		der, err := asciiToDERImpl(scanner, nil)
+		if err != nil {
+			// Positions are meaningless for tokens constructed from
+			// JSON, so omit them.
+			var pErr *parseError
+			if errors.As(err, &pErr) {
+				err = pErr.Err
+			}
+			return nil, false, fmt.Errorf("document %d: %s", i, err)
+		}
+
+		// PEM and non-PEM documents may not be mixed, as the non-PEM
+		// output would not be PEM-encoded.
+		if i != 0 && isPEM != (doc.PEMType != "") {
+			return nil, false, fmt.Errorf("document %d: PEM and non-PEM documents may not be mixed", i)
+		}
+		if doc.PEMType != "" {
+			var buf bytes.Buffer
+			if err := pem.Encode(&buf, &pem.Block{Type: doc.PEMType, Bytes: der}); err != nil {
+				return nil, false, fmt.Errorf("document %d: %s", i, err)
+			}
+			der = buf.Bytes()
+			isPEM = true
+		}
+		out = append(out, der...)
+	}
+	return out, isPEM, nil
+}
@@ -0,0 +1,108 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ascii2der
+
+import (
+	"bytes"
+	"testing"
+)
+
+var jsonToDERTests = []struct {
+	in    string
+	out   []byte
+	isPEM bool
+	ok    bool
+}{
+	{`[]`, nil, false, true},
+	{`{"elements": []}`, nil, false, true},
+	// Tags may be written as tag expressions.
+	{`[{"tag": "SEQUENCE", "children": [{"tag": "INTEGER", "bytes": "01"}]}]`, []byte{0x30, 0x03, 0x02, 0x01, 0x01}, false, true},
+	{`[{"tag": "[0 PRIMITIVE]"}, {"tag": "APPLICATION 1"}]`, []byte{0x80, 0x00, 0x61, 0x00}, false, true},
+	// Tags may be written as objects.
+	{`[{"tag": {"class": "UNIVERSAL", "number": 16, "constructed": true}}]`, []byte{0x30, 0x00}, false, true},
+	{`[{"tag": {"alias": "SEQUENCE", "constructed": false}}]`, []byte{0x10, 0x00}, false, true},
+	{`[{"tag": {"number": 1}}]`, []byte{0xa1, 0x00}, false, true},
+	{`[{"tag": {"alias": "SEQUENCE", "long_form": 2}}]`, []byte{0x3f, 0x80, 0x10, 0x00}, false, true},
+	// Values.
+	{`[{"tag": "INTEGER", "value": {"integer": "-129"}}]`, []byte{0x02, 0x02, 0xff, 0x7f}, false, true},
+	{`[{"tag": "INTEGER", "value": {"integer": 128}}]`, []byte{0x02, 0x02, 0x00, 0x80}, false, true},
+	{`[{"tag": "OBJECT_IDENTIFIER", "value": {"oid": "1.2.3", "oid_name": "ignored"}}]`, []byte{0x06, 0x02, 0x2a, 0x03}, false, true},
+	{`[{"tag": "RELATIVE_OID", "value": {"relative_oid": ".1.2"}}]`, []byte{0x0d, 0x02, 0x01, 0x02}, false, true},
+	{`[{"tag": "BOOLEAN", "value": {"boolean": true}}]`, []byte{0x01, 0x01, 0xff}, false, true},
+	{`[{"tag": "REAL", "value": {"real": "1.5"}}]`, []byte{0x09, 0x03, 0x80, 0xff, 0x03}, false, true},
+	{`[{"tag": "REAL", "value": {"real": "nr3:1.E-1"}}]`, []byte("\x09\x06\x031.E-1"), false, true},
+	{`[{"tag": "REAL", "value": {"real": "1.5.2"}}]`, nil, false, false},
+	{`[{"tag": "BIT_STRING", "value": {"bits": "1010", "unused_bits": 4}}]`, []byte{0x03, 0x02, 0x04, 0xa0}, false, true},
+	{`[{"tag": "UTF8String", "value": {"string": "é"}}]`, []byte{0x0c, 0x02, 0xc3, 0xa9}, false, true},
+	{`[{"tag": "BMPString", "value": {"string": "é"}}]`, []byte{0x1e, 0x02, 0x00, 0xe9}, false, true},
+	{`[{"tag": "UniversalString", "value": {"string": "é"}}]`, []byte{0x1c, 0x04, 0x00, 0x00, 0x00, 0xe9}, false, true},
+	// Bytes take precedence over values.
+	{`[{"tag": "INTEGER", "bytes": "00", "value": {"integer": "1"}}]`, []byte{0x02, 0x01, 0x00}, false, true},
+	// Length modifiers.
+	{`[{"tag": "SEQUENCE", "indefinite": true, "children": [{"tag": "NULL"}]}]`, []byte{0x30, 0x80, 0x05, 0x00, 0x00, 0x00}, false, true},
+	{`[{"tag": "SEQUENCE", "indefinite": true, "missing_eoc": true, "children": [{"tag": "NULL"}]}]`, []byte{0x30, 0x80, 0x05, 0x00}, false, true},
+	{`[{"tag": "SEQUENCE", "long_form": 2}]`, []byte{0x30, 0x82, 0x00, 0x00}, false, true},
+	{`[{"tag": "SEQUENCE", "adjust_length": 1}]`, []byte{0x30, 0x01}, false, true},
+	{`[{"tag": "SEQUENCE", "header_only": true}, {"raw": "aabbcc"}]`, []byte{0x30, 0xaa, 0xbb, 0xcc}, false, true},
+	// Informational fields from der2ascii -json are ignored.
+	{`[{"offset": 0, "tag": "NULL", "header_length": 2, "body_offset": 2, "body_length": 0}]`, []byte{0x05, 0x00}, false, true},
+	// Multiple documents are concatenated.
+	{`[{"tag": "NULL"}] [{"tag": "NULL"}]`, []byte{0x05, 0x00, 0x05, 0x00}, false, true},
+	// PEM types.
+	{`{"pem_type": "TEST", "elements": [{"tag": "NULL"}]}`, []byte("-----BEGIN TEST-----\nBQA=\n-----END TEST-----\n"), true, true},
+	{`{"pem_type": "A", "elements": []} {"pem_type": "B", "elements": []}`, []byte("-----BEGIN A-----\n-----END A-----\n-----BEGIN B-----\n-----END B-----\n"), true, true},
+	// PEM and non-PEM documents may not be mixed.
+	{`{"pem_type": "TEST", "elements": [{"tag": "NULL"}]} [{"tag": "NULL"}]`, nil, false, false},
+	{`[{"tag": "NULL"}] {"pem_type": "TEST", "elements": [{"tag": "NULL"}]}`, nil, false, false},
+	// Errors.
+	{`[{"tag": "BOGUS"}]`, nil, false, false},
+	{`[{"tag": {"alias": "BOGUS"}}]`, nil, false, false},
+	{`[{"tag": {"class": "BOGUS", "number": 1}}]`, nil, false, false},
+	{`[{"tag": {}}]`, nil, false, false},
+	{`[{"tag": {"number": 1, "typo": 1}}]`, nil, false, false},
+	{`[{"typo": 1}]`, nil, false, false},
+	{`[{}]`, nil, false, false},
+	{`[{"raw": "zz"}]`, nil, false, false},
+	{`[{"raw": "00", "tag": "NULL"}]`, nil, false, false},
+	{`[{"tag": "NULL", "bytes": "zz"}]`, nil, false, false},
+	{`[{"tag": "NULL", "value": {}}]`, nil, false, false},
+	{`[{"tag": "INTEGER", "value": {"integer": "1.5"}}]`, nil, false, false},
+	{`[{"tag": "OBJECT_IDENTIFIER", "value": {"oid": "1"}}]`, nil, false, false},
+	{`[{"tag": "BIT_STRING", "value": {"bits": "12"}}]`, nil, false, false},
+	{`[{"tag": "SEQUENCE", "missing_eoc": true}]`, nil, false, false},
+	{`[{"tag": "SEQUENCE", "long_form": -1}]`, nil, false, false},
+	{`[{"tag": "SEQUENCE", "adjust_length": -1}]`, nil, false, false},
+	{`[{"tag": "SEQUENCE", "indefinite": true, "long_form": 1}]`, nil, false, false},
+	{`[`, nil, false, false},
+	{`1`, nil, false, false},
+}
+
+func TestJSONToDER(t *testing.T) {
+	for i, tt := range jsonToDERTests {
+		out, isPEM, err := jsonToDER([]byte(tt.in))
+		ok := err == nil
+		if !tt.ok {
+			if ok {
+				t.Errorf("%d. jsonToDER(%v) unexpectedly succeeded.", i, tt.in)
+			}
+		} else {
+			if !ok {
+				t.Errorf("%d. jsonToDER(%v) unexpectedly failed: %s.", i, tt.in, err)
+			} else if !bytes.Equal(out, tt.out) || isPEM != tt.isPEM {
+				t.Errorf("%d. jsonToDER(%v) = %x, %v wanted %x, %v.", i, tt.in, out, isPEM, tt.out, tt.isPEM)
+			}
+		}
+	}
+}
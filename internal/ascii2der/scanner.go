@@ -0,0 +1,1263 @@
+// Copyright 2015 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ascii2der
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/google/der-ascii/internal"
+)
+
+// A position describes a location in the input stream.
+type position struct {
+	Offset int // offset, starting at 0
+	Line   int // line number, starting at 1
+	Column int // column number, starting at 1 (byte count)
+}
+
+// A tokenKind is a kind of token.
+type tokenKind int
+
+const (
+	tokenBytes tokenKind = iota
+	tokenLeftCurly
+	tokenRightCurly
+	tokenIndefinite
+	tokenLongForm
+	tokenAdjustLength
+	tokenVariable
+	tokenDefine
+	tokenRepeat
+	tokenNest
+	tokenPEM
+	tokenHeader
+	tokenAESCBC
+	tokenPBES2
+	tokenPKCS12MAC
+	tokenEOF
+)
+
+func (k tokenKind) String() string {
+	switch k {
+	case tokenBytes:
+		return "bytes"
+	case tokenLeftCurly:
+		return "'{'"
+	case tokenRightCurly:
+		return "'}'"
+	case tokenIndefinite:
+		return "indefinite"
+	case tokenLongForm:
+		return "long-form"
+	case tokenAdjustLength:
+		return "adjust-length"
+	case tokenVariable:
+		return "variable"
+	case tokenDefine:
+		return "define"
+	case tokenRepeat:
+		return "repeat"
+	case tokenNest:
+		return "nest"
+	case tokenPEM:
+		return "pem"
+	case tokenHeader:
+		return "header"
+	case tokenAESCBC:
+		return "aes-cbc"
+	case tokenPBES2:
+		return "pbes2"
+	case tokenPKCS12MAC:
+		return "pkcs12-mac"
+	case tokenEOF:
+		return "EOF"
+	}
+	panic(fmt.Sprintf("unknown token %d", k))
+}
+
+// A parseError is an error during parsing DER ASCII.
+type parseError struct {
+	Pos position
+	Err error
+}
+
+func (t *parseError) Error() string {
+	return fmt.Sprintf("line %d: %s", t.Pos.Line, t.Err)
+}
+
+// A token is a token in a DER ASCII file.
+type token struct {
+	// Kind is the kind of the token.
+	Kind tokenKind
+	// Value, for a tokenBytes token, is the decoded value of the token in
+	// bytes.
+	Value []byte
+	// Pos is the position of the first byte of the token.
+	Pos position
+	// Length, for a tokenLongForm token, is the number of bytes to use to
+	// encode the length, not including the initial one. For a tokenAdjustLength
+	// token, is the amount to adjust the total length by. For a tokenRepeat or
+	// tokenNest token, is the number of repetitions.
+	Length int
+	// Name, for a tokenVariable token, is the name of the variable, without
+	// the leading $.
+	Name string
+}
+
+// An expansion is a sequence of tokens which the scanner returns before
+// resuming the input text, such as the value of a variable or the body of a
+// repeat block.
+type expansion struct {
+	// Name is the name of the variable being expanded, or empty if this is
+	// not a variable.
+	Name   string
+	tokens []token
+	next   int
+	// remaining is the number of times to return tokens, including the
+	// current pass.
+	remaining int
+}
+
+type scanner struct {
+	text string
+	pos  position
+	// overrides contains variable values which take precedence over any
+	// definitions in the input.
+	overrides map[string][]token
+	// defaults contains variable values defined in the input.
+	defaults   map[string][]token
+	expansions []*expansion
+	// pemBlocks is the number of pem blocks assembled from the input.
+	pemBlocks int
+	// vocabulary contains the tag aliases selected by vocabulary and
+	// tag-alias pragmas so far.
+	vocabulary *internal.Vocabulary
+	// blockDepth is the number of nested blocks being read by parseBlock.
+	// Their tokens are expanded later, so pragmas may not appear in them.
+	blockDepth int
+}
+
+func newScanner(text string) *scanner {
+	return &scanner{text: text, pos: position{Line: 1}, defaults: make(map[string][]token), vocabulary: new(internal.Vocabulary)}
+}
+
+func (s *scanner) parseEscapeSequence() (rune, error) {
+	s.advance() // Skip the \. The caller is assumed to have validated it.
+	if s.isEOF() {
+		return 0, &parseError{s.pos, errors.New("expected escape character")}
+	}
+	switch c := s.text[s.pos.Offset]; c {
+	case 'n':
+		s.advance()
+		return '\n', nil
+	case '"', '\\':
+		s.advance()
+		return rune(c), nil
+	case 'x':
+		s.advance()
+		if s.pos.Offset+2 > len(s.text) {
+			return 0, &parseError{s.pos, errors.New("unfinished escape sequence")}
+		}
+		b, err := hex.DecodeString(s.text[s.pos.Offset : s.pos.Offset+2])
+		if err != nil {
+			return 0, &parseError{s.pos, err}
+		}
+		s.advanceBytes(2)
+		return rune(b[0]), nil
+	case 'u':
+		s.advance()
+		if s.pos.Offset+4 > len(s.text) {
+			return 0, &parseError{s.pos, errors.New("unfinished escape sequence")}
+		}
+		b, err := hex.DecodeString(s.text[s.pos.Offset : s.pos.Offset+4])
+		if err != nil {
+			return 0, &parseError{s.pos, err}
+		}
+		s.advanceBytes(4)
+		return rune(b[0])<<8 | rune(b[1]), nil
+	case 'U':
+		s.advance()
+		if s.pos.Offset+8 > len(s.text) {
+			return 0, &parseError{s.pos, errors.New("unfinished escape sequence")}
+		}
+		b, err := hex.DecodeString(s.text[s.pos.Offset : s.pos.Offset+8])
+		if err != nil {
+			return 0, &parseError{s.pos, err}
+		}
+		s.advanceBytes(8)
+		return rune(b[0])<<24 | rune(b[1])<<16 | rune(b[2])<<8 | rune(b[3]), nil
+	default:
+		return 0, &parseError{s.pos, fmt.Errorf("unknown escape sequence \\%c", c)}
+	}
+}
+
+func (s *scanner) parseQuotedString() (token, error) {
+	s.advance() // Skip the ". The caller is assumed to have validated it.
+	start := s.pos
+	var bytes []byte
+	for {
+		if s.isEOF() {
+			return token{}, &parseError{start, errors.New("unmatched \"")}
+		}
+		switch c := s.text[s.pos.Offset]; c {
+		case '"':
+			s.advance()
+			return token{Kind: tokenBytes, Value: bytes, Pos: start}, nil
+		case '\\':
+			escapeStart := s.pos
+			r, err := s.parseEscapeSequence()
+			if err != nil {
+				return token{}, err
+			}
+			if r > 0xff {
+				// Quoted strings emit one byte per escape, so larger
+				// values have no encoding. UTF-8 literals encode them
+				// as UTF-8.
+				return token{}, &parseError{escapeStart, errors.New("illegal escape for quoted string; use a u8\"\" literal for UTF-8")}
+			}
+			bytes = append(bytes, byte(r))
+		default:
+			s.advance()
+			bytes = append(bytes, c)
+		}
+	}
+}
+
+func (s *scanner) parseUTF8String() (token, error) {
+	s.advance() // Skip the u. The caller is assumed to have validated it.
+	s.advance() // Skip the 8. The caller is assumed to have validated it.
+	s.advance() // Skip the ". The caller is assumed to have validated it.
+	start := s.pos
+	var bytes []byte
+	for {
+		if s.isEOF() {
+			return token{}, &parseError{start, errors.New("unmatched \"")}
+		}
+		switch c := s.text[s.pos.Offset]; c {
+		case '"':
+			s.advance()
+			return token{Kind: tokenBytes, Value: bytes, Pos: start}, nil
+		case '\\':
+			escapeStart := s.pos
+			isByte := s.pos.Offset+1 < len(s.text) && s.text[s.pos.Offset+1] == 'x'
+			r, err := s.parseEscapeSequence()
+			if err != nil {
+				return token{}, err
+			}
+			if isByte {
+				// \x00 emits a byte, so invalid UTF-8 may be written.
+				bytes = append(bytes, byte(r))
+				break
+			}
+			if !utf8.ValidRune(r) {
+				return token{}, &parseError{escapeStart, errors.New("illegal escape for UTF-8 literal")}
+			}
+			var buf [utf8.UTFMax]byte
+			n := utf8.EncodeRune(buf[:], r)
+			bytes = append(bytes, buf[:n]...)
+		default:
+			r, n := utf8.DecodeRuneInString(s.text[s.pos.Offset:])
+			// Note DecodeRuneInString may return utf8.RuneError if there is a
+			// legitimate replacement charaacter in the input. The documentation
+			// says errors return (RuneError, 0) or (RuneError, 1).
+			if r == utf8.RuneError && n <= 1 {
+				return token{}, &parseError{s.pos, errors.New("invalid UTF-8")}
+			}
+			bytes = append(bytes, s.text[s.pos.Offset:s.pos.Offset+n]...)
+			s.advanceBytes(n)
+		}
+	}
+}
+
+func appendUTF16(b []byte, r rune) []byte {
+	if r <= 0xffff {
+		// Note this logic intentionally tolerates unpaired surrogates.
+		return append(b, byte(r>>8), byte(r))
+	}
+
+	r1, r2 := utf16.EncodeRune(r)
+	b = append(b, byte(r1>>8), byte(r1))
+	b = append(b, byte(r2>>8), byte(r2))
+	return b
+}
+
+func (s *scanner) parseUTF16String() (token, error) {
+	s.advance() // Skip the u. The caller is assumed to have validated it.
+	s.advance() // Skip the ". The caller is assumed to have validated it.
+	start := s.pos
+	var bytes []byte
+	for {
+		if s.isEOF() {
+			return token{}, &parseError{start, errors.New("unmatched \"")}
+		}
+		switch c := s.text[s.pos.Offset]; c {
+		case '"':
+			s.advance()
+			return token{Kind: tokenBytes, Value: bytes, Pos: start}, nil
+		case '\\':
+			r, err := s.parseEscapeSequence()
+			if err != nil {
+				return token{}, err
+			}
+			bytes = appendUTF16(bytes, r)
+		default:
+			r, n := utf8.DecodeRuneInString(s.text[s.pos.Offset:])
+			// Note DecodeRuneInString may return utf8.RuneError if there is a
+			// legitimate replacement charaacter in the input. The documentation
+			// says errors return (RuneError, 0) or (RuneError, 1).
+			if r == utf8.RuneError && n <= 1 {
+				return token{}, &parseError{s.pos, errors.New("invalid UTF-8")}
+			}
+			s.advanceBytes(n)
+			bytes = appendUTF16(bytes, r)
+		}
+	}
+}
+
+func appendUTF32(b []byte, r rune) []byte {
+	return append(b, byte(r>>24), byte(r>>16), byte(r>>8), byte(r))
+}
+
+func (s *scanner) parseUTF32String() (token, error) {
+	s.advance() // Skip the U. The caller is assumed to have validated it.
+	s.advance() // Skip the ". The caller is assumed to have validated it.
+	start := s.pos
+	var bytes []byte
+	for {
+		if s.isEOF() {
+			return token{}, &parseError{start, errors.New("unmatched \"")}
+		}
+		switch c := s.text[s.pos.Offset]; c {
+		case '"':
+			s.advance()
+			return token{Kind: tokenBytes, Value: bytes, Pos: start}, nil
+		case '\\':
+			r, err := s.parseEscapeSequence()
+			if err != nil {
+				return token{}, err
+			}
+			bytes = appendUTF32(bytes, r)
+		default:
+			r, n := utf8.DecodeRuneInString(s.text[s.pos.Offset:])
+			// Note DecodeRuneInString may return utf8.RuneError if there is a
+			// legitimate replacement charaacter in the input. The documentation
+			// says errors return (RuneError, 0) or (RuneError, 1).
+			if r == utf8.RuneError && n <= 1 {
+				return token{}, &parseError{s.pos, errors.New("invalid UTF-8")}
+			}
+			s.advanceBytes(n)
+			bytes = appendUTF32(bytes, r)
+		}
+	}
+}
+
+// Next returns the next token in the input, expanding variables and
+// processing definitions.
+func (s *scanner) Next() (token, error) {
+	for {
+		token, err := s.nextUnexpanded()
+		if err != nil {
+			return token, err
+		}
+		switch token.Kind {
+		case tokenDefine:
+			if err := s.parseDefine(token); err != nil {
+				return token, err
+			}
+		case tokenVariable:
+			if err := s.expandVariable(token); err != nil {
+				return token, err
+			}
+		case tokenRepeat:
+			if err := s.parseRepeat(token); err != nil {
+				return token, err
+			}
+		case tokenNest:
+			if err := s.parseNest(token); err != nil {
+				return token, err
+			}
+		default:
+			return token, nil
+		}
+	}
+}
+
+// nextUnexpanded returns the next token from the pending expansions or, if
+// there are none, the input text. Variables and definitions are returned
+// as-is.
+func (s *scanner) nextUnexpanded() (token, error) {
+	for len(s.expansions) > 0 {
+		e := s.expansions[len(s.expansions)-1]
+		if e.next == len(e.tokens) && e.remaining > 1 {
+			e.next = 0
+			e.remaining--
+		}
+		if e.next < len(e.tokens) {
+			e.next++
+			return e.tokens[e.next-1], nil
+		}
+		s.expansions = s.expansions[:len(s.expansions)-1]
+	}
+	return s.scan()
+}
+
+// parseBlock parses a curly-brace-delimited block following the keyword
+// token and returns the tokens inside, without expanding them.
+func (s *scanner) parseBlock(keyword token) ([]token, error) {
+	left, err := s.nextUnexpanded()
+	if err != nil {
+		return nil, err
+	}
+	if left.Kind != tokenLeftCurly {
+		return nil, &parseError{keyword.Pos, fmt.Errorf("expected '{' after %s but found %s", keyword.Kind, left.Kind)}
+	}
+	s.blockDepth++
+	defer func() { s.blockDepth-- }()
+	var tokens []token
+	depth := 1
+	for {
+		token, err := s.nextUnexpanded()
+		if err != nil {
+			return nil, err
+		}
+		switch token.Kind {
+		case tokenLeftCurly:
+			depth++
+		case tokenRightCurly:
+			depth--
+			if depth == 0 {
+				return tokens, nil
+			}
+		case tokenEOF:
+			return nil, &parseError{left.Pos, errors.New("unmatched '{'")}
+		}
+		tokens = append(tokens, token)
+	}
+}
+
+// parseDefine parses a variable definition following the define token.
+func (s *scanner) parseDefine(define token) error {
+	variable, err := s.nextUnexpanded()
+	if err != nil {
+		return err
+	}
+	if variable.Kind != tokenVariable {
+		return &parseError{define.Pos, fmt.Errorf("expected variable after define but found %s", variable.Kind)}
+	}
+	value, err := s.parseBlock(variable)
+	if err != nil {
+		return err
+	}
+	if _, ok := s.defaults[variable.Name]; ok {
+		return &parseError{variable.Pos, fmt.Errorf("duplicate definition of $%s", variable.Name)}
+	}
+	s.defaults[variable.Name] = value
+	return nil
+}
+
+// parsePragma parses the arguments of a vocabulary or tag-alias pragma, which
+// began at start, and updates the scanner's tag aliases.
+func (s *scanner) parsePragma(pragma string, start position) error {
+	if s.blockDepth != 0 {
+		return &parseError{start, fmt.Errorf("%s may not appear in a block which is expanded later, such as the body of define or repeat", pragma)}
+	}
+	s.skipWhitespace()
+	if s.isEOF() {
+		return &parseError{start, fmt.Errorf("expected name after %s", pragma)}
+	}
+	nameStart := s.pos
+	name := s.scanSymbol()
+	if pragma == "vocabulary" {
+		if err := s.vocabulary.AddVocabulary(name); err != nil {
+			return &parseError{nameStart, err}
+		}
+		return nil
+	}
+
+	s.skipWhitespace()
+	if s.isEOF() || s.text[s.pos.Offset] != '[' {
+		return &parseError{start, fmt.Errorf("expected tag expression after tag-alias %s", name)}
+	}
+	s.advance()
+	tagStr, ok := s.consumeUpTo(']')
+	if !ok {
+		return &parseError{s.pos, errors.New("unmatched [")}
+	}
+	tag, err := decodeTagString(tagStr, s.vocabulary)
+	if err != nil {
+		return &parseError{s.pos, err}
+	}
+	if err := s.vocabulary.Add(name, tag); err != nil {
+		return &parseError{nameStart, err}
+	}
+	return nil
+}
+
+// expandVariable looks up the value of the variable and queues it to be
+// returned from Next.
+func (s *scanner) expandVariable(variable token) error {
+	value, ok := s.overrides[variable.Name]
+	if !ok {
+		value, ok = s.defaults[variable.Name]
+	}
+	if !ok {
+		return &parseError{variable.Pos, fmt.Errorf("undefined variable $%s", variable.Name)}
+	}
+	for _, e := range s.expansions {
+		if e.Name == variable.Name {
+			return &parseError{variable.Pos, fmt.Errorf("recursive reference to $%s", variable.Name)}
+		}
+	}
+	s.expansions = append(s.expansions, &expansion{Name: variable.Name, tokens: value, remaining: 1})
+	return nil
+}
+
+// parseRepeat parses the body of a repeat token and queues it to be returned
+// from Next the specified number of times.
+func (s *scanner) parseRepeat(repeat token) error {
+	body, err := s.parseBlock(repeat)
+	if err != nil {
+		return err
+	}
+	if len(body) != 0 && repeat.Length != 0 {
+		s.expansions = append(s.expansions, &expansion{tokens: body, remaining: repeat.Length})
+	}
+	return nil
+}
+
+// parseNest parses the two blocks of a nest token. It queues the first block,
+// followed by '{', the specified number of times, followed by the second
+// block and the matching number of '}' tokens.
+func (s *scanner) parseNest(nest token) error {
+	prefix, err := s.parseBlock(nest)
+	if err != nil {
+		return err
+	}
+	inner, err := s.parseBlock(nest)
+	if err != nil {
+		return err
+	}
+	if nest.Length != 0 {
+		prefix = append(prefix, token{Kind: tokenLeftCurly, Pos: nest.Pos})
+		suffix := []token{{Kind: tokenRightCurly, Pos: nest.Pos}}
+		s.expansions = append(s.expansions, &expansion{tokens: suffix, remaining: nest.Length})
+	}
+	s.expansions = append(s.expansions, &expansion{tokens: inner, remaining: 1})
+	if nest.Length != 0 {
+		s.expansions = append(s.expansions, &expansion{tokens: prefix, remaining: nest.Length})
+	}
+	return nil
+}
+
+// scan returns the next token in the input text.
+func (s *scanner) scan() (token, error) {
+	s.skipWhitespace()
+	if s.isEOF() {
+		return token{Kind: tokenEOF, Pos: s.pos}, nil
+	}
+
+	switch s.text[s.pos.Offset] {
+	case '{':
+		s.advance()
+		return token{Kind: tokenLeftCurly, Pos: s.pos}, nil
+	case '}':
+		s.advance()
+		return token{Kind: tokenRightCurly, Pos: s.pos}, nil
+	case '"':
+		return s.parseQuotedString()
+	case 'u':
+		if s.pos.Offset+1 < len(s.text) && s.text[s.pos.Offset+1] == '"' {
+			return s.parseUTF16String()
+		}
+		if s.pos.Offset+2 < len(s.text) && s.text[s.pos.Offset+1:s.pos.Offset+3] == `8"` {
+			return s.parseUTF8String()
+		}
+	case 'U':
+		if s.pos.Offset+1 < len(s.text) && s.text[s.pos.Offset+1] == '"' {
+			return s.parseUTF32String()
+		}
+	case 'b':
+		if s.pos.Offset+1 < len(s.text) && s.text[s.pos.Offset+1] == '`' {
+			s.advance() // Skip the b.
+			s.advance() // Skip the `.
+			bitStr, ok := s.consumeUpTo('`')
+			if !ok {
+				return token{}, &parseError{s.pos, errors.New("unmatched `")}
+			}
+
+			value, err := decodeBitString(bitStr)
+			if err != nil {
+				return token{}, &parseError{s.pos, err}
+			}
+			return token{Kind: tokenBytes, Value: value, Pos: s.pos}, nil
+		}
+	case '`':
+		s.advance()
+		hexStr, ok := s.consumeUpTo('`')
+		if !ok {
+			return token{}, &parseError{s.pos, errors.New("unmatched `")}
+		}
+		bytes, err := hex.DecodeString(hexStr)
+		if err != nil {
+			return token{}, &parseError{s.pos, err}
+		}
+		return token{Kind: tokenBytes, Value: bytes, Pos: s.pos}, nil
+	case '[':
+		s.advance()
+		tagStr, ok := s.consumeUpTo(']')
+		if !ok {
+			return token{}, &parseError{s.pos, errors.New("unmatched [")}
+		}
+		tag, err := decodeTagString(tagStr, s.vocabulary)
+		if err != nil {
+			return token{}, &parseError{s.pos, err}
+		}
+		value, err := appendTag(nil, tag)
+		if err != nil {
+			return token{}, &parseError{s.pos, err}
+		}
+		return token{Kind: tokenBytes, Value: value, Pos: s.pos}, nil
+	}
+
+	// Normal token.
+	start := s.pos
+	symbol := s.scanSymbol()
+
+	// See if it is a tag.
+	tag, ok := internal.TagByName(symbol)
+	if ok {
+		value, err := appendTag(nil, tag)
+		if err != nil {
+			// This is impossible; built-in tags always encode.
+			return token{}, &parseError{s.pos, err}
+		}
+		return token{Kind: tokenBytes, Value: value, Pos: start}, nil
+	}
+
+	if isInteger(symbol) {
+		value, err := strconv.ParseInt(symbol, 10, 64)
+		if err != nil {
+			return token{}, &parseError{start, err}
+		}
+		return token{Kind: tokenBytes, Value: appendInteger(nil, value), Pos: s.pos}, nil
+	}
+
+	if isObjectIdentifier(symbol) {
+		der, err := decodeObjectIdentifierString(symbol)
+		if err != nil {
+			return token{}, &parseError{start, err}
+		}
+		return token{Kind: tokenBytes, Value: der, Pos: s.pos}, nil
+	}
+
+	if isRelativeOID(symbol) {
+		der, err := decodeRelativeOIDString(symbol)
+		if err != nil {
+			return token{}, &parseError{start, err}
+		}
+		return token{Kind: tokenBytes, Value: der, Pos: s.pos}, nil
+	}
+
+	if symbol == "TRUE" {
+		return token{Kind: tokenBytes, Value: []byte{0xff}, Pos: s.pos}, nil
+	}
+
+	if symbol == "FALSE" {
+		return token{Kind: tokenBytes, Value: []byte{0x00}, Pos: s.pos}, nil
+	}
+
+	if symbol == "indefinite" {
+		return token{Kind: tokenIndefinite}, nil
+	}
+
+	if symbol == "vocabulary" || symbol == "tag-alias" {
+		if err := s.parsePragma(symbol, start); err != nil {
+			return token{}, err
+		}
+		return s.scan()
+	}
+
+	if symbol == "repeat" || symbol == "nest" {
+		kind := tokenRepeat
+		if symbol == "nest" {
+			kind = tokenNest
+		}
+		s.skipWhitespace()
+		countStart := s.pos
+		count, err := strconv.Atoi(s.scanSymbol())
+		if err != nil {
+			return token{}, &parseError{countStart, fmt.Errorf("expected count after %s: %s", symbol, err)}
+		}
+		// Enforce a limit of int32, purely so that the limits are not
+		// target-specific.
+		if count < 0 || count > math.MaxInt32 {
+			return token{}, &parseError{countStart, fmt.Errorf("invalid %s count %d", symbol, count)}
+		}
+		return token{Kind: kind, Length: count, Pos: start}, nil
+	}
+
+	if isReal(symbol) {
+		der, err := decodeReal(symbol)
+		if err != nil {
+			return token{}, &parseError{start, err}
+		}
+		return token{Kind: tokenBytes, Value: der, Pos: s.pos}, nil
+	}
+
+	if isTypedString(symbol) {
+		der, err := decodeTypedString(symbol)
+		if err != nil {
+			return token{}, &parseError{start, err}
+		}
+		return token{Kind: tokenBytes, Value: der, Pos: s.pos}, nil
+	}
+
+	if symbol == bitsPrefix {
+		if s.isEOF() || s.text[s.pos.Offset] != '{' {
+			return token{}, &parseError{start, errors.New("expected named bits or { after bits:")}
+		}
+		s.advance()
+		list, ok := s.consumeUpTo('}')
+		if !ok {
+			return token{}, &parseError{s.pos, errors.New("unmatched {")}
+		}
+		der, err := decodeBitPositions(list)
+		if err != nil {
+			return token{}, &parseError{start, err}
+		}
+		return token{Kind: tokenBytes, Value: der, Pos: start}, nil
+	}
+
+	if isBits(symbol) {
+		der, err := decodeNamedBits(symbol)
+		if err != nil {
+			return token{}, &parseError{start, err}
+		}
+		return token{Kind: tokenBytes, Value: der, Pos: start}, nil
+	}
+
+	if isFill(symbol) {
+		count, err := decodeFill(symbol)
+		if err != nil {
+			return token{}, &parseError{start, err}
+		}
+		if s.isEOF() || s.text[s.pos.Offset] != '`' {
+			return token{}, &parseError{start, errors.New("expected hex literal after fill")}
+		}
+		s.advance()
+		hexStr, ok := s.consumeUpTo('`')
+		if !ok {
+			return token{}, &parseError{s.pos, errors.New("unmatched `")}
+		}
+		pattern, err := hex.DecodeString(hexStr)
+		if err != nil {
+			return token{}, &parseError{s.pos, err}
+		}
+		if len(pattern) != 0 && count > math.MaxInt32/len(pattern) {
+			return token{}, &parseError{start, errors.New("fill too large")}
+		}
+		return token{Kind: tokenBytes, Value: bytes.Repeat(pattern, count), Pos: start}, nil
+	}
+
+	if symbol == "pem" {
+		return token{Kind: tokenPEM, Pos: start}, nil
+	}
+
+	if symbol == "header" {
+		return token{Kind: tokenHeader, Pos: start}, nil
+	}
+
+	if symbol == "define" {
+		return token{Kind: tokenDefine, Pos: start}, nil
+	}
+
+	if symbol == "aes-cbc" {
+		return token{Kind: tokenAESCBC, Pos: start}, nil
+	}
+
+	if symbol == "pbes2" {
+		return token{Kind: tokenPBES2, Pos: start}, nil
+	}
+
+	if symbol == "pkcs12-mac" {
+		return token{Kind: tokenPKCS12MAC, Pos: start}, nil
+	}
+
+	if isVariable(symbol) {
+		return token{Kind: tokenVariable, Name: symbol[1:], Pos: start}, nil
+	}
+
+	if isAdjustLength(symbol) {
+		l, err := decodeAdjustLength(symbol)
+		if err != nil {
+			return token{}, &parseError{start, err}
+		}
+		return token{Kind: tokenAdjustLength, Length: l}, nil
+	}
+
+	if isLongFormOverride(symbol) {
+		l, err := decodeLongFormOverride(symbol)
+		if err != nil {
+			return token{}, &parseError{start, err}
+		}
+		return token{Kind: tokenLongForm, Length: l}, nil
+	}
+
+	return token{}, fmt.Errorf("unrecognized symbol %q", symbol)
+}
+
+// skipWhitespace advances past any whitespace and comments.
+func (s *scanner) skipWhitespace() {
+	for !s.isEOF() {
+		switch s.text[s.pos.Offset] {
+		case ' ', '\t', '\n', '\r':
+			s.advance()
+		case '#':
+			// Skip to the end of the comment.
+			if i := strings.IndexByte(s.text[s.pos.Offset:], '\n'); i >= 0 {
+				s.advanceBytes(i + 1)
+			} else {
+				s.advanceBytes(len(s.text) - s.pos.Offset)
+			}
+		default:
+			return
+		}
+	}
+}
+
+// scanSymbol consumes and returns a normal token, up to the next whitespace
+// character, symbol, or EOF.
+func (s *scanner) scanSymbol() string {
+	start := s.pos.Offset
+	end := start + 1
+loop:
+	for ; end < len(s.text); end++ {
+		switch s.text[end] {
+		case ' ', '\t', '\n', '\r', '{', '}', '[', ']', '`', '"', '#':
+			break loop
+		}
+	}
+	s.advanceBytes(end - start)
+	return s.text[start:s.pos.Offset]
+}
+
+func (s *scanner) isEOF() bool {
+	return s.pos.Offset >= len(s.text)
+}
+
+func (s *scanner) advance() {
+	if !s.isEOF() {
+		if s.text[s.pos.Offset] == '\n' {
+			s.pos.Line++
+			s.pos.Column = 0
+		} else {
+			s.pos.Column++
+		}
+		s.pos.Offset++
+	}
+}
+
+// advanceBytes behaves like calling advance n times.
+func (s *scanner) advanceBytes(n int) {
+	if n > len(s.text)-s.pos.Offset {
+		n = len(s.text) - s.pos.Offset
+	}
+	skipped := s.text[s.pos.Offset : s.pos.Offset+n]
+	if strings.IndexByte(skipped, '\n') >= 0 {
+		s.pos.Line += strings.Count(skipped, "\n")
+		s.pos.Column = n - strings.LastIndexByte(skipped, '\n') - 1
+	} else {
+		s.pos.Column += n
+	}
+	s.pos.Offset += n
+}
+
+func (s *scanner) consumeUpTo(b byte) (string, bool) {
+	i := strings.IndexByte(s.text[s.pos.Offset:], b)
+	if i < 0 {
+		s.advanceBytes(len(s.text) - s.pos.Offset)
+		return "", false
+	}
+	ret := s.text[s.pos.Offset : s.pos.Offset+i]
+	s.advanceBytes(i + 1)
+	return ret, true
+}
+
+// An openElement is a '{' token which has not yet been matched.
+type openElement struct {
+	leftCurly                    token
+	lengthModifier, adjustLength *token
+	// length, if lengthModifier is not an indefinite token, is the
+	// reserved length of the element.
+	length openLength
+}
+
+// asciiToDERImpl assembles tokens from scanner. If leftCurly is not nil, it
+// stops after the matching '}'. Otherwise, it stops at the end of the input.
+func asciiToDERImpl(scanner *scanner, leftCurly *token) ([]byte, error) {
+	var a assembler
+	// open contains the '{' tokens which have been seen, but not matched.
+	var open []openElement
+	var lengthModifier, adjustLength *token
+	// pem blocks may not be mixed with other top-level output, which would
+	// not be PEM-encoded. rawOutput is set once there is such output.
+	var rawOutput bool
+	checkRawOutput := func(t token) error {
+		if leftCurly != nil || len(open) != 0 || rawOutput {
+			return nil
+		}
+		if scanner.pemBlocks != 0 {
+			return &parseError{t.Pos, errors.New("output outside pem blocks may not follow a pem block")}
+		}
+		rawOutput = true
+		return nil
+	}
+	leftCurlyExpected := func() error {
+		if lengthModifier != nil {
+			return &parseError{lengthModifier.Pos, fmt.Errorf("%s token must modify '{'", lengthModifier.Kind)}
+		}
+		if adjustLength != nil {
+			return &parseError{adjustLength.Pos, fmt.Errorf("%s token must modify '{'", adjustLength.Kind)}
+		}
+		return nil
+	}
+	for {
+		token, err := scanner.Next()
+		if err != nil {
+			return nil, err
+		}
+		switch token.Kind {
+		case tokenBytes:
+			if err := leftCurlyExpected(); err != nil {
+				return nil, err
+			}
+			if len(token.Value) != 0 {
+				if err := checkRawOutput(token); err != nil {
+					return nil, err
+				}
+			}
+			a.write(token.Value)
+		case tokenLeftCurly:
+			if err := checkRawOutput(token); err != nil {
+				return nil, err
+			}
+			elem := openElement{leftCurly: token, lengthModifier: lengthModifier, adjustLength: adjustLength}
+			if lengthModifier != nil && lengthModifier.Kind == tokenIndefinite {
+				a.write([]byte{0x80})
+			} else {
+				elem.length = a.openLength()
+			}
+			open = append(open, elem)
+			lengthModifier = nil
+			adjustLength = nil
+		case tokenPEM:
+			if err := leftCurlyExpected(); err != nil {
+				return nil, err
+			}
+			if leftCurly != nil || len(open) != 0 {
+				return nil, &parseError{token.Pos, errors.New("pem blocks may only appear at the top level")}
+			}
+			if rawOutput {
+				return nil, &parseError{token.Pos, errors.New("pem blocks may not follow output outside pem blocks")}
+			}
+			block, err := parsePEMBlock(scanner, token)
+			if err != nil {
+				return nil, err
+			}
+			var buf bytes.Buffer
+			if err := pem.Encode(&buf, block); err != nil {
+				return nil, &parseError{token.Pos, err}
+			}
+			a.write(buf.Bytes())
+			scanner.pemBlocks++
+		case tokenAESCBC, tokenPBES2, tokenPKCS12MAC:
+			if err := leftCurlyExpected(); err != nil {
+				return nil, err
+			}
+			if err := checkRawOutput(token); err != nil {
+				return nil, err
+			}
+			value, err := parseCryptoBlock(scanner, token)
+			if err != nil {
+				return nil, err
+			}
+			a.write(value)
+		case tokenHeader:
+			return nil, &parseError{token.Pos, errors.New("header token must follow a pem token")}
+		case tokenRightCurly:
+			if len(open) == 0 {
+				if leftCurly != nil {
+					return a.bytes(), nil
+				}
+				return nil, &parseError{token.Pos, errors.New("unmatched '}'")}
+			}
+			elem := open[len(open)-1]
+			open = open[:len(open)-1]
+			// Any length modifiers in the element's contents are
+			// discarded.
+			lengthModifier = nil
+			adjustLength = nil
+			if elem.lengthModifier != nil && elem.lengthModifier.Kind == tokenIndefinite {
+				a.write([]byte{0x00, 0x00})
+				break
+			}
+			length := a.contentsLength(elem.length)
+			if elem.adjustLength != nil {
+				length += elem.adjustLength.Length
+				// Enforce a limit of int32, purely so that the limits are not
+				// target-specific.
+				if length < 0 || length > math.MaxInt32 {
+					if elem.adjustLength.Length < 0 {
+						return nil, &parseError{elem.leftCurly.Pos, errors.New("length adjustment underflowed")}
+					}
+					return nil, &parseError{elem.leftCurly.Pos, errors.New("length adjustment overflowed")}
+				}
+			}
+			var lengthOverride int
+			if elem.lengthModifier != nil && elem.lengthModifier.Kind == tokenLongForm {
+				lengthOverride = elem.lengthModifier.Length
+			}
+			if err := a.closeLength(elem.length, length, lengthOverride); err != nil {
+				// appendLength may fail if the lengthModifier was incompatible.
+				return nil, &parseError{elem.lengthModifier.Pos, err}
+			}
+		case tokenLongForm, tokenIndefinite:
+			if lengthModifier != nil {
+				return nil, &parseError{token.Pos, fmt.Errorf("found %s token but already seen %s token", token.Kind, lengthModifier.Kind)}
+			}
+			lengthModifier = &token
+		case tokenAdjustLength:
+			if adjustLength != nil {
+				return nil, &parseError{token.Pos, errors.New("duplicate adjust-length token")}
+			}
+			adjustLength = &token
+		case tokenEOF:
+			if err := leftCurlyExpected(); err != nil {
+				return nil, err
+			}
+			if len(open) != 0 {
+				return nil, &parseError{open[len(open)-1].leftCurly.Pos, errors.New("unmatched '{'")}
+			}
+			if leftCurly != nil {
+				return nil, &parseError{leftCurly.Pos, errors.New("unmatched '{'")}
+			}
+			return a.bytes(), nil
+		default:
+			panic(token)
+		}
+	}
+}
+
+// parsePEMBlock parses the type, headers, and contents of a pem block
+// following the pem token.
+func parsePEMBlock(scanner *scanner, pemToken token) (*pem.Block, error) {
+	typeToken, err := scanner.Next()
+	if err != nil {
+		return nil, err
+	}
+	if typeToken.Kind != tokenBytes {
+		return nil, &parseError{pemToken.Pos, fmt.Errorf("expected PEM type after pem but found %s", typeToken.Kind)}
+	}
+	block := &pem.Block{Type: string(typeToken.Value)}
+	for {
+		token, err := scanner.Next()
+		if err != nil {
+			return nil, err
+		}
+		switch token.Kind {
+		case tokenHeader:
+			var kv [2]string
+			for i := range kv {
+				t, err := scanner.Next()
+				if err != nil {
+					return nil, err
+				}
+				if t.Kind != tokenBytes {
+					return nil, &parseError{token.Pos, fmt.Errorf("expected header name and value but found %s", t.Kind)}
+				}
+				kv[i] = string(t.Value)
+			}
+			if block.Headers == nil {
+				block.Headers = make(map[string]string)
+			}
+			if _, ok := block.Headers[kv[0]]; ok {
+				return nil, &parseError{token.Pos, fmt.Errorf("duplicate header %q", kv[0])}
+			}
+			block.Headers[kv[0]] = kv[1]
+		case tokenLeftCurly:
+			block.Bytes, err = asciiToDERImpl(scanner, &token)
+			if err != nil {
+				return nil, err
+			}
+			return block, nil
+		default:
+			return nil, &parseError{pemToken.Pos, fmt.Errorf("expected '{' after pem type but found %s", token.Kind)}
+		}
+	}
+}
+
+// parseCryptoBlock parses the arguments and block following an aes-cbc, pbes2,
+// or pkcs12-mac token and returns the resulting ciphertext or MAC.
+func parseCryptoBlock(scanner *scanner, keyword token) ([]byte, error) {
+	var numArgs int
+	switch keyword.Kind {
+	case tokenAESCBC:
+		numArgs = 2 // key, iv
+	case tokenPBES2:
+		numArgs = 3 // password, salt, iterations
+	case tokenPKCS12MAC:
+		numArgs = 3 // password, salt, iterations
+	}
+	args := make([][]byte, numArgs)
+	for i := range args {
+		t, err := scanner.Next()
+		if err != nil {
+			return nil, err
+		}
+		if t.Kind != tokenBytes {
+			return nil, &parseError{keyword.Pos, fmt.Errorf("expected %d arguments after %s but found %s", numArgs, keyword.Kind, t.Kind)}
+		}
+		args[i] = t.Value
+	}
+	left, err := scanner.Next()
+	if err != nil {
+		return nil, err
+	}
+	if left.Kind != tokenLeftCurly {
+		return nil, &parseError{keyword.Pos, fmt.Errorf("expected '{' after %s arguments but found %s", keyword.Kind, left.Kind)}
+	}
+	body, err := asciiToDERImpl(scanner, &left)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []byte
+	switch keyword.Kind {
+	case tokenAESCBC:
+		out, err = encryptAESCBC(args[0], args[1], body)
+	case tokenPBES2:
+		var iterations int
+		iterations, err = decodeIterations(args[2])
+		if err == nil {
+			out, err = encryptPBES2(args[0], args[1], iterations, body)
+		}
+	case tokenPKCS12MAC:
+		var iterations int
+		iterations, err = decodeIterations(args[2])
+		if err == nil {
+			out, err = pkcs12MAC(args[0], args[1], iterations, body)
+		}
+	}
+	if err != nil {
+		return nil, &parseError{keyword.Pos, err}
+	}
+	return out, nil
+}
+
+// parseVariableValue scans text as the value of a variable. The value must be
+// a DER ASCII fragment with balanced curly braces. Variables in the value are
+// expanded when it is used.
+func parseVariableValue(text string) ([]token, error) {
+	scanner := newScanner(text)
+	// The value is expanded later, like the body of define.
+	scanner.blockDepth = 1
+	var tokens []token
+	var depth int
+	for {
+		token, err := scanner.nextUnexpanded()
+		if err != nil {
+			return nil, err
+		}
+		switch token.Kind {
+		case tokenLeftCurly:
+			depth++
+		case tokenRightCurly:
+			depth--
+			if depth < 0 {
+				return nil, &parseError{token.Pos, errors.New("unmatched '}'")}
+			}
+		case tokenEOF:
+			if depth != 0 {
+				return nil, &parseError{token.Pos, errors.New("unmatched '{'")}
+			}
+			return tokens, nil
+		}
+		tokens = append(tokens, token)
+	}
+}
+
+// parseVariablesFile parses text as a series of variable definitions and
+// returns the resulting values.
+func parseVariablesFile(text string) (map[string][]token, error) {
+	scanner := newScanner(text)
+	for {
+		token, err := scanner.nextUnexpanded()
+		if err != nil {
+			return nil, err
+		}
+		switch token.Kind {
+		case tokenDefine:
+			if err := scanner.parseDefine(token); err != nil {
+				return nil, err
+			}
+		case tokenEOF:
+			return scanner.defaults, nil
+		default:
+			return nil, &parseError{token.Pos, fmt.Errorf("expected define but found %s", token.Kind)}
+		}
+	}
+}
+
+// parseVocabularyFile parses text as a series of vocabulary and tag-alias
+// pragmas and returns the resulting tag aliases.
+func parseVocabularyFile(text string) (*internal.Vocabulary, error) {
+	scanner := newScanner(text)
+	token, err := scanner.nextUnexpanded()
+	if err != nil {
+		return nil, err
+	}
+	if token.Kind != tokenEOF {
+		return nil, &parseError{token.Pos, fmt.Errorf("expected vocabulary or tag-alias but found %s", token.Kind)}
+	}
+	return scanner.vocabulary, nil
+}
+
+func asciiToDER(input string) ([]byte, error) {
+	out, _, err := asciiToDERWithVariables(input, nil)
+	return out, err
+}
+
+// asciiToDERWithVariables behaves like asciiToDER, but vars, if non-nil,
+// specifies variable values which take precedence over the definitions in
+// input. It additionally returns whether input contained pem blocks, in which
+// case the output is already PEM-encoded.
+func asciiToDERWithVariables(input string, vars map[string][]token) (out []byte, isPEM bool, err error) {
+	scanner := newScanner(input)
+	scanner.overrides = vars
+	out, err = asciiToDERImpl(scanner, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	return out, scanner.pemBlocks != 0, nil
+}
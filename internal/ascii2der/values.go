@@ -0,0 +1,599 @@
+// Copyright 2015 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ascii2der
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/google/der-ascii/internal"
+)
+
+const (
+	adjustLengthPrefix = "adjust-length:"
+	longFormPrefix     = "long-form:"
+	fillPrefix         = "fill:"
+	realPrefix         = "real:"
+	bitsPrefix         = "bits:"
+)
+
+// isDigits returns whether s is a non-empty string of decimal digits.
+func isDigits(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// isDottedDigits returns whether s is a series of one or more non-empty strings
+// of decimal digits, separated by dots.
+func isDottedDigits(s string) bool {
+	for {
+		i := strings.IndexByte(s, '.')
+		if i < 0 {
+			return isDigits(s)
+		}
+		if !isDigits(s[:i]) {
+			return false
+		}
+		s = s[i+1:]
+	}
+}
+
+// isInteger returns whether s is an integer token, such as "-5".
+func isInteger(s string) bool {
+	return isDigits(strings.TrimPrefix(s, "-"))
+}
+
+// isObjectIdentifier returns whether s is an OID token, such as "1.2.3".
+func isObjectIdentifier(s string) bool {
+	return strings.IndexByte(s, '.') >= 0 && isDottedDigits(s)
+}
+
+// isRelativeOID returns whether s is a relative OID token, such as ".1.2".
+func isRelativeOID(s string) bool {
+	return strings.HasPrefix(s, ".") && isDottedDigits(s[1:])
+}
+
+// isVariable returns whether s is a variable token, such as "$name".
+func isVariable(s string) bool {
+	if len(s) < 2 || s[0] != '$' {
+		return false
+	}
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		if c != '_' && (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (i == 1 || c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func isAdjustLength(s string) bool {
+	return strings.HasPrefix(s, adjustLengthPrefix)
+}
+
+func decodeAdjustLength(s string) (int, error) {
+	s, ok := strings.CutPrefix(s, adjustLengthPrefix)
+	if !ok {
+		return 0, errors.New("not an adjust-length token")
+	}
+
+	l, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err
+	}
+	return l, nil
+}
+
+func isLongFormOverride(s string) bool {
+	return strings.HasPrefix(s, longFormPrefix)
+}
+
+func decodeLongFormOverride(s string) (int, error) {
+	s, ok := strings.CutPrefix(s, longFormPrefix)
+	if !ok {
+		return 0, errors.New("not a long-form override")
+	}
+
+	l, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err
+	}
+	if l <= 0 {
+		return 0, errors.New("invalid long-form override")
+	}
+	return l, nil
+}
+
+func isFill(s string) bool {
+	return strings.HasPrefix(s, fillPrefix)
+}
+
+// decodeFill decodes s as the "fill:N:" prefix of a fill token and returns N.
+func decodeFill(s string) (int, error) {
+	s, ok := strings.CutPrefix(s, fillPrefix)
+	if !ok {
+		return 0, errors.New("not a fill token")
+	}
+	s, ok = strings.CutSuffix(s, ":")
+	if !ok {
+		return 0, errors.New("expected : after fill count")
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		return 0, errors.New("invalid fill count")
+	}
+	return n, nil
+}
+
+func isReal(s string) bool {
+	return strings.HasPrefix(s, realPrefix)
+}
+
+// isSignedDigits returns whether s is a non-empty string of decimal digits,
+// optionally preceded by a minus sign.
+func isSignedDigits(s string) bool {
+	return isDigits(strings.TrimPrefix(s, "-"))
+}
+
+// isDecimal returns whether s is a decimal number, with optional fraction and
+// exponent parts.
+func isDecimal(s string) bool {
+	s = strings.TrimPrefix(s, "-")
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		exp := s[i+1:]
+		if len(exp) > 0 && (exp[0] == '+' || exp[0] == '-') {
+			exp = exp[1:]
+		}
+		if !isDigits(exp) {
+			return false
+		}
+		s = s[:i]
+	}
+	whole, frac, ok := strings.Cut(s, ".")
+	return isDigits(whole) && (!ok || isDigits(frac))
+}
+
+// decodeReal decodes s as a REAL token and returns the contents of the REAL's
+// encoding.
+func decodeReal(s string) ([]byte, error) {
+	s, ok := strings.CutPrefix(s, realPrefix)
+	if !ok {
+		return nil, errors.New("not a REAL token")
+	}
+
+	switch s {
+	case "PLUS-INFINITY":
+		return []byte{0x40}, nil
+	case "MINUS-INFINITY":
+		return []byte{0x41}, nil
+	case "NaN":
+		return []byte{0x42}, nil
+	}
+
+	// Decimal encodings are written as ISO 6093 strings, which are emitted
+	// as-is.
+	for form := byte(1); form <= 3; form++ {
+		if text, ok := strings.CutPrefix(s, fmt.Sprintf("nr%d:", form)); ok {
+			return append([]byte{form}, text...), nil
+		}
+	}
+
+	if strings.Contains(s, "*") {
+		return decodeRealBinary(s)
+	}
+
+	if !isDecimal(s) {
+		return nil, fmt.Errorf("invalid REAL value %q", s)
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, fmt.Errorf("REAL value %q out of range", s)
+	}
+	return appendReal(nil, v), nil
+}
+
+// decodeRealBinary decodes s as the value of an explicit binary REAL token, of
+// the form "N*B^E" or "N*2^F*B^E", optionally followed by ":exp-len:L", and
+// returns the contents of the REAL's encoding.
+func decodeRealBinary(s string) ([]byte, error) {
+	var expLength int
+	s, expLengthStr, ok := strings.Cut(s, ":exp-len:")
+	if ok {
+		var err error
+		expLength, err = strconv.Atoi(expLengthStr)
+		if err != nil {
+			return nil, err
+		}
+		if expLength <= 0 {
+			return nil, fmt.Errorf("invalid exponent length %d", expLength)
+		}
+	}
+
+	parts := strings.Split(s, "*")
+	if len(parts) != 2 && len(parts) != 3 {
+		return nil, fmt.Errorf("invalid REAL value %q", s)
+	}
+
+	mantissaStr := parts[0]
+	negative := strings.HasPrefix(mantissaStr, "-")
+	mantissaStr = strings.TrimPrefix(mantissaStr, "-")
+	if !isDigits(mantissaStr) {
+		return nil, fmt.Errorf("invalid REAL mantissa %q", parts[0])
+	}
+	mantissa, _ := new(big.Int).SetString(mantissaStr, 10)
+
+	var scale int
+	if len(parts) == 3 {
+		scaleStr, ok := strings.CutPrefix(parts[1], "2^")
+		if !ok || !isDigits(scaleStr) {
+			return nil, fmt.Errorf("invalid REAL scale factor %q", parts[1])
+		}
+		var err error
+		scale, err = strconv.Atoi(scaleStr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	baseStr, exponentStr, ok := strings.Cut(parts[len(parts)-1], "^")
+	if !ok || !isSignedDigits(exponentStr) {
+		return nil, fmt.Errorf("invalid REAL exponent %q", parts[len(parts)-1])
+	}
+	base, err := strconv.Atoi(baseStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid REAL base %q", baseStr)
+	}
+	exponent, _ := new(big.Int).SetString(exponentStr, 10)
+	return appendRealBinary(nil, negative, mantissa, base, scale, exponent, expLength)
+}
+
+// typedStringTypes are the aliases of the types with typed string tokens. Each
+// token is the lowercase alias, a colon, and the value, such as
+// "date:2024-02-29".
+var typedStringTypes = []string{"TIME", "DATE", "TIME-OF-DAY", "DATE-TIME", "DURATION", "OID-IRI", "RELATIVE-OID-IRI"}
+
+// cutTypedString splits s, a typed string token, into the alias of its type and
+// its value. If s is not a typed string token, it returns ok = false.
+func cutTypedString(s string) (name, value string, ok bool) {
+	prefix, value, ok := strings.Cut(s, ":")
+	if !ok {
+		return "", "", false
+	}
+	for _, t := range typedStringTypes {
+		if prefix == strings.ToLower(t) {
+			return t, value, true
+		}
+	}
+	return "", "", false
+}
+
+func isTypedString(s string) bool {
+	_, _, ok := cutTypedString(s)
+	return ok
+}
+
+// decodeTypedString checks that s, a typed string token, is a valid value of
+// its type and returns the value's encoding. An OID-IRI may also be written as
+// a dotted OID, which is converted to an OID-IRI.
+func decodeTypedString(s string) ([]byte, error) {
+	name, value, ok := cutTypedString(s)
+	if !ok {
+		return nil, errors.New("not a typed string token")
+	}
+	var err error
+	switch name {
+	case "OID-IRI":
+		if isObjectIdentifier(value) {
+			if value, err = internal.DottedToOIDIRI(value); err != nil {
+				return nil, err
+			}
+		}
+		_, err = internal.ParseOIDIRI(value, false)
+	case "RELATIVE-OID-IRI":
+		_, err = internal.ParseOIDIRI(value, true)
+	default:
+		err = internal.ValidateTime(name, value)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q: %s", name, value, err)
+	}
+	return []byte(value), nil
+}
+
+func isBits(s string) bool {
+	return strings.HasPrefix(s, bitsPrefix)
+}
+
+// decodeBitPosition decodes s as the position of a bit in a BIT STRING.
+func decodeBitPosition(s string) (int, error) {
+	if !isDigits(s) {
+		return 0, fmt.Errorf("invalid bit position %q", s)
+	}
+	p, err := strconv.Atoi(s)
+	// Enforce a limit of int32, purely so that the limits are not
+	// target-specific.
+	if err != nil || p > math.MaxInt32-7 {
+		return 0, fmt.Errorf("bit position %s too large", s)
+	}
+	return p, nil
+}
+
+// decodeBitPositions decodes s as the comma-separated bit positions of a
+// "bits:{...}" token, without the curly braces, and returns the contents of
+// the BIT STRING's DER encoding.
+func decodeBitPositions(s string) ([]byte, error) {
+	var positions []int
+	if strings.TrimSpace(s) != "" {
+		for _, p := range strings.Split(s, ",") {
+			pos, err := decodeBitPosition(strings.TrimSpace(p))
+			if err != nil {
+				return nil, err
+			}
+			positions = append(positions, pos)
+		}
+	}
+	return appendNamedBits(nil, positions), nil
+}
+
+// decodeNamedBits decodes s as a named bits token, such as
+// "bits:keyUsage(digitalSignature,keyCertSign)", and returns the contents of
+// the BIT STRING's DER encoding.
+func decodeNamedBits(s string) ([]byte, error) {
+	s, ok := strings.CutPrefix(s, bitsPrefix)
+	if !ok {
+		return nil, errors.New("not a bits token")
+	}
+	typ, names, ok := strings.Cut(s, "(")
+	if !ok {
+		return nil, errors.New("expected ( or { after bits:")
+	}
+	if names, ok = strings.CutSuffix(names, ")"); !ok {
+		return nil, errors.New("expected ) at end of named bits")
+	}
+	if !internal.IsNamedBitType(typ) {
+		return nil, fmt.Errorf("unknown named bit type %q", typ)
+	}
+	var positions []int
+	if names != "" {
+		for _, name := range strings.Split(names, ",") {
+			pos, err := internal.LookupNamedBit(typ, name)
+			if err != nil {
+				return nil, err
+			}
+			positions = append(positions, pos)
+		}
+	}
+	return appendNamedBits(nil, positions), nil
+}
+
+// decodeBitString decodes s as the contents of a bit string literal and
+// returns the contents of the BIT STRING's DER encoding.
+func decodeBitString(s string) ([]byte, error) {
+	// The leading byte is the number of "extra" bits at the end.
+	var bitCount int
+	var sawPipe bool
+	value := []byte{0}
+	for i, r := range s {
+		switch r {
+		case '0', '1':
+			if bitCount%8 == 0 {
+				value = append(value, 0)
+			}
+			if r == '1' {
+				value[bitCount/8+1] |= 1 << uint(7-bitCount%8)
+			}
+			bitCount++
+		case '|':
+			if sawPipe {
+				return nil, errors.New("duplicate |")
+			}
+
+			// bitsRemaining is the number of bits remaining in the output that haven't
+			// been used yet. There cannot be more than that many bits past the |.
+			bitsRemaining := (len(value)-1)*8 - bitCount
+			inputRemaining := len(s) - i - 1
+			if inputRemaining > bitsRemaining {
+				return nil, fmt.Errorf("expected at most %v explicit padding bits; found %v", bitsRemaining, inputRemaining)
+			}
+
+			sawPipe = true
+			value[0] = byte(bitsRemaining)
+		default:
+			return nil, fmt.Errorf("unexpected rune %q", r)
+		}
+	}
+	if !sawPipe {
+		value[0] = byte((len(value)-1)*8 - bitCount)
+	}
+	return value, nil
+}
+
+// maxBase128Digits is the number of decimal digits in 2^(7*n), where n is
+// internal.MaxBase128Length. Values with more digits do not fit in a base-128
+// encoding of that length.
+const maxBase128Digits = 2158
+
+// decodeBase128Decimal decodes s, a string of decimal digits, as a tag number
+// or OID component. It returns false if the value's base-128 encoding would be
+// longer than internal.MaxBase128Length bytes.
+func decodeBase128Decimal(s string) (*big.Int, bool) {
+	s = strings.TrimLeft(s, "0")
+	if len(s) > maxBase128Digits {
+		return nil, false
+	}
+	v, _ := new(big.Int).SetString("0"+s, 10)
+	if (v.BitLen()+6)/7 > internal.MaxBase128Length {
+		return nil, false
+	}
+	return v, true
+}
+
+// decodeOIDComponents decodes s as a series of dot-separated decimal
+// components of any size, up to internal.MaxBase128Length bytes when encoded.
+func decodeOIDComponents(s string) ([]*big.Int, error) {
+	var oid []*big.Int
+	for _, c := range strings.Split(s, ".") {
+		if !isDigits(c) {
+			return nil, fmt.Errorf("invalid OID component %q", c)
+		}
+		v, ok := decodeBase128Decimal(c)
+		if !ok {
+			return nil, fmt.Errorf("OID component of %d digits is too large", len(c))
+		}
+		oid = append(oid, v)
+	}
+	return oid, nil
+}
+
+// decodeObjectIdentifierString decodes s as a dotted OID and returns the
+// contents of the OBJECT IDENTIFIER's DER encoding.
+func decodeObjectIdentifierString(s string) ([]byte, error) {
+	oid, err := decodeOIDComponents(s)
+	if err != nil {
+		return nil, err
+	}
+	der, ok := appendObjectIdentifier(nil, oid)
+	if !ok {
+		return nil, errors.New("invalid OID")
+	}
+	return der, nil
+}
+
+// decodeRelativeOIDString decodes s as a relative OID, with a leading dot,
+// and returns the contents of the RELATIVE-OID's DER encoding.
+func decodeRelativeOIDString(s string) ([]byte, error) {
+	s, ok := strings.CutPrefix(s, ".")
+	if !ok {
+		return nil, errors.New("relative OID must begin with .")
+	}
+	oid, err := decodeOIDComponents(s)
+	if err != nil {
+		return nil, err
+	}
+	return appendRelativeOID(nil, oid), nil
+}
+
+// decodeTagNumber decodes s as a decimal tag number of any size, up to
+// internal.MaxBase128Length bytes when encoded. It returns the number as in the
+// Number and BigNumber fields of internal.Tag.
+func decodeTagNumber(s string) (number uint64, bigNumber string, err error) {
+	if !isDigits(s) {
+		return 0, "", fmt.Errorf("invalid tag number %q", s)
+	}
+	if n, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return n, "", nil
+	}
+	// Normalize the number, so equal tags compare equal.
+	n, ok := decodeBase128Decimal(s)
+	if !ok {
+		return 0, "", fmt.Errorf("tag number of %d digits is too large", len(s))
+	}
+	return 0, n.String(), nil
+}
+
+// decodeTagString decodes s as a tag descriptor and returns the decoded tag or
+// an error. The first component may be an alias in v, in addition to the
+// universal tag names.
+func decodeTagString(s string, v *internal.Vocabulary) (internal.Tag, error) {
+	ss := strings.Split(s, " ")
+
+	// Tags may begin with a long-form override.
+	var longFormOverride int
+	if isLongFormOverride(ss[0]) {
+		var err error
+		longFormOverride, err = decodeLongFormOverride(ss[0])
+		if err != nil {
+			return internal.Tag{}, err
+		}
+		ss = ss[1:]
+	}
+
+	if len(ss) == 0 {
+		return internal.Tag{}, errors.New("expected tag component")
+	}
+
+	// Tag aliases may only be in the first component.
+	tag, ok := internal.TagByName(ss[0])
+	if !ok {
+		tag, ok = v.TagByName(ss[0])
+	}
+	if ok {
+		ss = ss[1:]
+	} else {
+		// Tags default to constructed, context-specific.
+		tag.Class = internal.ClassContextSpecific
+		tag.Constructed = true
+
+		// Otherwise, the first component is an optional class.
+		switch ss[0] {
+		case "APPLICATION":
+			tag.Class = internal.ClassApplication
+			ss = ss[1:]
+		case "PRIVATE":
+			tag.Class = internal.ClassPrivate
+			ss = ss[1:]
+		case "UNIVERSAL":
+			tag.Class = internal.ClassUniversal
+			ss = ss[1:]
+		}
+
+		// The next (or first) component must be the tag number.
+		if len(ss) == 0 {
+			return internal.Tag{}, errors.New("expected tag number")
+		}
+		var err error
+		tag.Number, tag.BigNumber, err = decodeTagNumber(ss[0])
+		if err != nil {
+			return internal.Tag{}, err
+		}
+		ss = ss[1:]
+	}
+
+	tag.LongFormOverride = longFormOverride
+
+	// The final token, if any, may be CONSTRUCTED or PRIMITIVE.
+	if len(ss) > 0 {
+		switch ss[0] {
+		case "CONSTRUCTED":
+			tag.Constructed = true
+		case "PRIMITIVE":
+			tag.Constructed = false
+		default:
+			return internal.Tag{}, fmt.Errorf("unexpected tag component %q", ss[0])
+		}
+		ss = ss[1:]
+	}
+
+	if len(ss) != 0 {
+		return internal.Tag{}, fmt.Errorf("excess tag component %q", ss[0])
+	}
+
+	return tag, nil
+}
@@ -0,0 +1,216 @@
+// Copyright 2015 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ascii2der
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/google/der-ascii/internal"
+)
+
+var decodeTagStringTests = []struct {
+	input string
+	tag   internal.Tag
+	ok    bool
+}{
+	{"SEQUENCE", internal.Tag{Class: internal.ClassUniversal, Number: 16, Constructed: true}, true},
+	{"long-form:5 SEQUENCE", internal.Tag{Class: internal.ClassUniversal, Number: 16, Constructed: true, LongFormOverride: 5}, true},
+	{"SEQUENCE CONSTRUCTED", internal.Tag{Class: internal.ClassUniversal, Number: 16, Constructed: true}, true},
+	{"SEQUENCE PRIMITIVE", internal.Tag{Class: internal.ClassUniversal, Number: 16}, true},
+	{"INTEGER", internal.Tag{Class: internal.ClassUniversal, Number: 2}, true},
+	{"INTEGER CONSTRUCTED", internal.Tag{Class: internal.ClassUniversal, Number: 2, Constructed: true}, true},
+	{"INTEGER PRIMITIVE", internal.Tag{Class: internal.ClassUniversal, Number: 2}, true},
+	{"long-form:5 2", internal.Tag{Class: internal.ClassContextSpecific, Number: 2, Constructed: true, LongFormOverride: 5}, true},
+	{"2 PRIMITIVE", internal.Tag{Class: internal.ClassContextSpecific, Number: 2}, true},
+	{"APPLICATION 2", internal.Tag{Class: internal.ClassApplication, Number: 2, Constructed: true}, true},
+	{"PRIVATE 2", internal.Tag{Class: internal.ClassPrivate, Number: 2, Constructed: true}, true},
+	{"long-form:5 PRIVATE 2", internal.Tag{Class: internal.ClassPrivate, Number: 2, Constructed: true, LongFormOverride: 5}, true},
+	{"UNIVERSAL 2", internal.Tag{Class: internal.ClassUniversal, Number: 2, Constructed: true}, true},
+	{"UNIVERSAL 2", internal.Tag{Class: internal.ClassUniversal, Number: 2, Constructed: true}, true},
+	{"UNIVERSAL 2 CONSTRUCTED", internal.Tag{Class: internal.ClassUniversal, Number: 2, Constructed: true}, true},
+	{"UNIVERSAL 2 PRIMITIVE", internal.Tag{Class: internal.ClassUniversal, Number: 2}, true},
+	{"UNIVERSAL 2 CONSTRUCTED EXTRA", internal.Tag{}, false},
+	{"UNIVERSAL 2 EXTRA", internal.Tag{}, false},
+	{"UNIVERSAL NOT_A_NUMBER", internal.Tag{}, false},
+	{"UNIVERSAL SEQUENCE", internal.Tag{}, false},
+	{"UNIVERSAL", internal.Tag{}, false},
+	{"SEQUENCE 2", internal.Tag{}, false},
+	{"", internal.Tag{}, false},
+	{" SEQUENCE", internal.Tag{}, false},
+	{"SEQUENCE ", internal.Tag{}, false},
+	{"SEQUENCE  CONSTRUCTED", internal.Tag{}, false},
+	{"long-form:2", internal.Tag{}, false},
+	{"long-form:0 SEQUENCE", internal.Tag{}, false},
+	{"long-form:-1 SEQUENCE", internal.Tag{}, false},
+	{"long-form:garbage SEQUENCE", internal.Tag{}, false},
+}
+
+func TestDecodeTagString(t *testing.T) {
+	for i, tt := range decodeTagStringTests {
+		tag, err := decodeTagString(tt.input, nil)
+		if tag != tt.tag || (err == nil) != tt.ok {
+			t.Errorf("%d. decodeTagString(%v) = %v, err=%s, wanted %v, success=%v", i, tt.input, tag, err, tt.tag, tt.ok)
+		}
+	}
+}
+
+var symbolKindTests = []struct {
+	input                                            string
+	integer, objectIdentifier, relativeOID, variable bool
+}{
+	{"0", true, false, false, false},
+	{"-123", true, false, false, false},
+	{"-", false, false, false, false},
+	{"--1", false, false, false, false},
+	{"1-", false, false, false, false},
+	{"1.2", false, true, false, false},
+	{"1.2.840.113549", false, true, false, false},
+	{"1.", false, false, false, false},
+	{"1..2", false, false, false, false},
+	{".1", false, false, true, false},
+	{".1.2", false, false, true, false},
+	{".", false, false, false, false},
+	{".1.", false, false, false, false},
+	{"$a", false, false, false, true},
+	{"$_a1", false, false, false, true},
+	{"$1a", false, false, false, false},
+	{"$", false, false, false, false},
+	{"$a-b", false, false, false, false},
+	{"", false, false, false, false},
+}
+
+func TestSymbolKinds(t *testing.T) {
+	for i, tt := range symbolKindTests {
+		if got := isInteger(tt.input); got != tt.integer {
+			t.Errorf("%d. isInteger(%q) = %v, wanted %v", i, tt.input, got, tt.integer)
+		}
+		if got := isObjectIdentifier(tt.input); got != tt.objectIdentifier {
+			t.Errorf("%d. isObjectIdentifier(%q) = %v, wanted %v", i, tt.input, got, tt.objectIdentifier)
+		}
+		if got := isRelativeOID(tt.input); got != tt.relativeOID {
+			t.Errorf("%d. isRelativeOID(%q) = %v, wanted %v", i, tt.input, got, tt.relativeOID)
+		}
+		if got := isVariable(tt.input); got != tt.variable {
+			t.Errorf("%d. isVariable(%q) = %v, wanted %v", i, tt.input, got, tt.variable)
+		}
+	}
+}
+
+var decodeRealTests = []struct {
+	input string
+	out   []byte
+	ok    bool
+}{
+	{"real:0", nil, true},
+	{"real:-0", []byte{0x43}, true},
+	{"real:-0.0e5", []byte{0x43}, true},
+	{"real:PLUS-INFINITY", []byte{0x40}, true},
+	{"real:MINUS-INFINITY", []byte{0x41}, true},
+	{"real:NaN", []byte{0x42}, true},
+	{"real:1.5", []byte{0x80, 0xff, 0x03}, true},
+	{"real:-3e2", []byte{0xc0, 0x02, 0x4b}, true},
+	{"real:1e+21", []byte{0x80, 0x15, 0x01, 0xb1, 0xae, 0x4d, 0x6e, 0x2e, 0xf5}, true},
+	{"real:nr3:1.E-1", []byte("\x031.E-1"), true},
+	{"real:nr1:042", []byte("\x01042"), true},
+	{"real:3*2^-1", []byte{0x80, 0xff, 0x03}, true},
+	{"real:-5*16^3", []byte{0xe0, 0x03, 0x05}, true},
+	{"real:3*2^1*8^-1", []byte{0x94, 0xff, 0x03}, true},
+	{"real:1*2^0:exp-len:2", []byte{0x81, 0x00, 0x00, 0x01}, true},
+	{"real:-0*2^0", []byte{0xc0, 0x00}, true},
+	{"real:1e400", nil, false},
+	{"real:1.", nil, false},
+	{"real:.5", nil, false},
+	{"real:inf", nil, false},
+	{"real:0x10", nil, false},
+	{"real:", nil, false},
+	{"real:nr4:1", nil, false},
+	{"real:3*3^1", nil, false},
+	{"real:3*2^4*2^1", nil, false},
+	{"real:3*8^1*2^1", nil, false},
+	{"real:3*2", nil, false},
+	{"real:3*2^1:exp-len:0", nil, false},
+	{"real:3*2^256:exp-len:1", nil, false},
+}
+
+func TestDecodeReal(t *testing.T) {
+	for i, tt := range decodeRealTests {
+		out, err := decodeReal(tt.input)
+		if (err == nil) != tt.ok || !bytes.Equal(out, tt.out) {
+			t.Errorf("%d. decodeReal(%q) = %x, err=%v, wanted %x, success=%v", i, tt.input, out, err, tt.out, tt.ok)
+		}
+	}
+}
+
+var decodeTypedStringTests = []struct {
+	input string
+	out   []byte
+	ok    bool
+}{
+	{"date:2024-02-29", []byte("2024-02-29"), true},
+	{"date:2023-02-29", nil, false},
+	{"time-of-day:12:30:00", []byte("12:30:00"), true},
+	{"date-time:2024-02-29T12:30:00", []byte("2024-02-29T12:30:00"), true},
+	{"duration:P1DT12H", []byte("P1DT12H"), true},
+	{"time:R/2024-02-29/P1D", []byte("R/2024-02-29/P1D"), true},
+	{"time:", nil, false},
+	{"oid-iri:/ISO/Member-Body/840", []byte("/ISO/Member-Body/840"), true},
+	{"oid-iri:1.2.840", []byte("/ISO/Member-Body/840"), true},
+	{"oid-iri:ISO/Member-Body", nil, false},
+	{"relative-oid-iri:Member-Body/840", []byte("Member-Body/840"), true},
+	{"relative-oid-iri:/840", nil, false},
+	{"DATE:2024-02-29", nil, false},
+	{"utctime:2024-02-29", nil, false},
+}
+
+func TestDecodeTypedString(t *testing.T) {
+	for i, tt := range decodeTypedStringTests {
+		out, err := decodeTypedString(tt.input)
+		if (err == nil) != tt.ok || !bytes.Equal(out, tt.out) {
+			t.Errorf("%d. decodeTypedString(%q) = %q, err=%v, wanted %q, success=%v", i, tt.input, out, err, tt.out, tt.ok)
+		}
+	}
+}
+
+func TestDecodeBase128Decimal(t *testing.T) {
+	// The largest value that fits in internal.MaxBase128Length bytes.
+	max := new(big.Int).Lsh(big.NewInt(1), 7*internal.MaxBase128Length)
+	max.Sub(max, big.NewInt(1))
+	tooLarge := new(big.Int).Add(max, big.NewInt(1))
+
+	for _, s := range []string{"0", "000", "1234", "00" + max.String()} {
+		v, ok := decodeBase128Decimal(s)
+		if want, _ := new(big.Int).SetString(s, 10); !ok || v.Cmp(want) != 0 {
+			t.Errorf("decodeBase128Decimal(%q) = %v, %v, want %v, true", s, v, ok, want)
+		}
+	}
+	for _, s := range []string{tooLarge.String(), strings.Repeat("9", maxBase128Digits+1)} {
+		if _, ok := decodeBase128Decimal(s); ok {
+			t.Errorf("decodeBase128Decimal(%q) unexpectedly succeeded", s)
+		}
+	}
+
+	if _, err := decodeObjectIdentifierString("1.2." + max.String()); err != nil {
+		t.Errorf("decodeObjectIdentifierString failed on a component at the limit: %s", err)
+	}
+	if _, err := decodeObjectIdentifierString("1.2." + tooLarge.String()); err == nil {
+		t.Errorf("decodeObjectIdentifierString unexpectedly accepted a component over the limit")
+	}
+	if _, err := decodeTagString("PRIVATE "+tooLarge.String(), nil); err == nil {
+		t.Errorf("decodeTagString unexpectedly accepted a tag number over the limit")
+	}
+}
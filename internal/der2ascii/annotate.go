@@ -0,0 +1,416 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package der2ascii
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/der-ascii/internal"
+)
+
+// A structure describes the expected contents of a series of sibling
+// elements, such as the body of a SEQUENCE. The disassembler consults it to
+// annotate the output with comments.
+type structure interface {
+	// next is called with each element in order. It returns comments to
+	// write before the element and the structure of the element's body,
+	// or nil if unknown.
+	next(elem element) (comments []string, body structure)
+	// end is called after the last element and returns comments to write
+	// after it.
+	end() []string
+}
+
+// maxSchemaDepth bounds recursion through CHOICE types in case of cycles.
+const maxSchemaDepth = 64
+
+// typeName returns a short description of t for use in comments.
+func (s *schema) typeName(t *schemaType) string {
+	switch t.kind {
+	case schemaUniversal:
+		if t.inner != nil && t.inner.kind != schemaAny {
+			return t.name + " containing " + s.typeName(t.inner)
+		}
+		return t.name
+	case schemaReference:
+		return t.name
+	case schemaSequence:
+		return "SEQUENCE"
+	case schemaSet:
+		return "SET"
+	case schemaSequenceOf:
+		return "SEQUENCE OF " + s.typeName(t.inner)
+	case schemaSetOf:
+		return "SET OF " + s.typeName(t.inner)
+	case schemaChoice:
+		return "CHOICE"
+	case schemaAny:
+		return "ANY"
+	case schemaTagged:
+		return s.typeName(t.inner)
+	default:
+		panic(t.kind)
+	}
+}
+
+// matchesDepth returns whether an element with tag may be an encoding of t.
+func (s *schema) matchesDepth(t *schemaType, tag internal.Tag, depth int) bool {
+	t = s.resolve(t)
+	if t == nil || depth > maxSchemaDepth {
+		return false
+	}
+	universal := func(number uint64) bool {
+		return tag.Class == internal.ClassUniversal && tag.Number == number
+	}
+	switch t.kind {
+	case schemaUniversal, schemaTagged:
+		return tag.Class == t.tag.Class && tag.Number == t.tag.Number && tag.BigNumber == t.tag.BigNumber
+	case schemaSequence, schemaSequenceOf:
+		return universal(16)
+	case schemaSet, schemaSetOf:
+		return universal(17)
+	case schemaChoice:
+		for _, c := range t.components {
+			if s.matchesDepth(c.typ, tag, depth+1) {
+				return true
+			}
+		}
+		return false
+	case schemaAny:
+		return true
+	default:
+		panic(t.kind)
+	}
+}
+
+func (s *schema) matches(t *schemaType, tag internal.Tag) bool {
+	return s.matchesDepth(t, tag, 0)
+}
+
+// annotate returns comments for elem, which is an encoding of t, and the
+// structure of its body. name is the name of the component, if any. siblings
+// is the enclosing SEQUENCE or SET, if any, and is used to resolve ANY DEFINED
+// BY. If quiet is true, no comment is returned unless it describes the value
+// or CHOICE alternative.
+func (s *schema) annotate(name string, t *schemaType, elem element, siblings *componentStructure, quiet bool) ([]string, structure) {
+	label := s.typeName(t)
+	var body structure
+	var value string
+	// described is true if the comment carries more information than the
+	// type in the output.
+	var described bool
+	// typeName is the name of the most recently followed reference.
+	var typeName string
+loop:
+	for depth := 0; depth <= maxSchemaDepth; depth++ {
+		if f, ok := s.describers[t.name]; ok && value == "" && (t.kind == schemaReference || t.kind == schemaUniversal) {
+			value = f(elem)
+		}
+		if t.kind == schemaReference {
+			typeName = t.name
+		}
+		t = s.resolve(t)
+		if t == nil {
+			break
+		}
+		switch t.kind {
+		case schemaTagged:
+			if s.isImplicit(t) {
+				t = t.inner
+				continue
+			}
+			body = &elementStructure{schema: s, typ: t.inner, siblings: siblings, quiet: true}
+			if defined := s.resolveDefinedBy(t.inner, siblings); defined != nil {
+				label = s.typeName(defined)
+			}
+		case schemaChoice:
+			var alt *schemaComponent
+			for _, c := range t.components {
+				if s.matches(c.typ, elem.tag) {
+					alt = c
+					break
+				}
+			}
+			if alt == nil {
+				break loop
+			}
+			label += ": " + alt.name
+			described = true
+			t = alt.typ
+			continue
+		case schemaAny:
+			if defined := s.resolveDefinedBy(t, siblings); defined != nil {
+				if !s.matches(defined, elem.tag) {
+					return []string{mismatch("expected %s, found %s", s.typeName(defined), s.tagName(elem.tag))}, nil
+				}
+				label = s.typeName(defined)
+				described = true
+				t = defined
+				continue
+			}
+		case schemaSequence, schemaSet:
+			body = &componentStructure{schema: s, typ: t, name: typeName, values: make(map[string]string)}
+		case schemaSequenceOf, schemaSetOf:
+			body = &elementStructure{schema: s, typ: t.inner, siblings: siblings, repeated: true, quiet: !s.isNamed(t.inner)}
+		case schemaUniversal:
+			if t.inner != nil {
+				if elem.tag.Constructed {
+					// The contents are split across a
+					// constructed string's segments.
+					body = &elementStructure{schema: s, typ: t, siblings: siblings, repeated: true, quiet: true}
+				} else {
+					body = &elementStructure{schema: s, typ: t.inner, siblings: siblings}
+				}
+				if defined := s.resolveDefinedBy(t.inner, siblings); defined != nil && label == t.name {
+					label += " containing " + s.typeName(defined)
+				}
+			}
+			if value == "" {
+				value = s.describeValue(t, elem)
+			}
+		}
+		break
+	}
+
+	if quiet && value == "" && !described {
+		return nil, body
+	}
+	var comment string
+	if name != "" {
+		comment = fmt.Sprintf("%s (%s)", name, label)
+	} else {
+		comment = label
+	}
+	if value != "" {
+		comment += ": " + value
+	}
+	return []string{comment}, body
+}
+
+// resolveDefinedBy returns the type of t, an ANY DEFINED BY, or nil if t is not
+// an ANY DEFINED BY or its type is unknown. siblings is the enclosing SEQUENCE
+// or SET.
+func (s *schema) resolveDefinedBy(t *schemaType, siblings *componentStructure) *schemaType {
+	if t.kind != schemaAny || t.definedBy == "" || siblings == nil {
+		return nil
+	}
+	key, ok := siblings.values[t.definedBy]
+	if !ok {
+		return nil
+	}
+	if siblings.name != "" {
+		if defined, ok := s.definedBy[siblings.name+" "+key]; ok {
+			return defined
+		}
+	}
+	return s.definedBy[key]
+}
+
+// isAny returns whether t is an ANY.
+func (s *schema) isAny(t *schemaType) bool {
+	t = s.resolve(t)
+	return t != nil && t.kind == schemaAny
+}
+
+// isNamed returns whether t is a reference to a named type.
+func (s *schema) isNamed(t *schemaType) bool {
+	for t.kind == schemaTagged {
+		t = t.inner
+	}
+	return t.kind == schemaReference
+}
+
+// describeValue returns a description of the value of elem, an encoding of t,
+// using t's named numbers or bits. It returns the empty string if there is
+// nothing to describe.
+func (s *schema) describeValue(t *schemaType, elem element) string {
+	if len(t.namedValues) == 0 {
+		return ""
+	}
+	if t.name == "BIT STRING" {
+		positions, _ := setBits(elem.body)
+		var names []string
+		for _, p := range positions {
+			if name, ok := t.namedValues[int64(p)]; ok {
+				names = append(names, name)
+			} else {
+				names = append(names, fmt.Sprintf("bit %d", p))
+			}
+		}
+		return strings.Join(names, ", ")
+	}
+	v, ok := decodeInteger(elem.body)
+	if !ok {
+		return ""
+	}
+	return t.namedValues[v]
+}
+
+// mismatchPrefix is the prefix of comments describing deviations from the
+// schema.
+const mismatchPrefix = "schema mismatch: "
+
+// mismatch formats a comment describing a deviation from the schema.
+func mismatch(format string, args ...interface{}) string {
+	return mismatchPrefix + fmt.Sprintf(format, args...)
+}
+
+// describeComponent formats c for use in comments.
+func (s *schema) describeComponent(c *schemaComponent) string {
+	return fmt.Sprintf("%s (%s)", c.name, s.typeName(c.typ))
+}
+
+// An elementStructure expects a single element of a given type or, if
+// repeated is true, any number of them.
+type elementStructure struct {
+	schema *schema
+	typ    *schemaType
+	// siblings, if not nil, is used to resolve ANY DEFINED BY.
+	siblings *componentStructure
+	repeated bool
+	// quiet, if true, suppresses comments naming the type of each element.
+	quiet bool
+	seen  bool
+}
+
+func (e *elementStructure) next(elem element) ([]string, structure) {
+	if e.seen && !e.repeated {
+		return []string{mismatch("unexpected %s", e.schema.tagName(elem.tag))}, nil
+	}
+	e.seen = true
+	if !e.schema.matches(e.typ, elem.tag) {
+		return []string{mismatch("expected %s, found %s", e.schema.typeName(e.typ), e.schema.tagName(elem.tag))}, nil
+	}
+	return e.schema.annotate("", e.typ, elem, e.siblings, e.quiet)
+}
+
+func (e *elementStructure) end() []string {
+	if !e.seen && !e.repeated {
+		return []string{mismatch("missing %s", e.schema.typeName(e.typ))}
+	}
+	return nil
+}
+
+// A componentStructure expects the components of a SEQUENCE or SET.
+type componentStructure struct {
+	schema *schema
+	typ    *schemaType
+	// name is the name of the type, if known.
+	name string
+	// pos, for a SEQUENCE, is the index of the next expected component.
+	pos int
+	// seen, for a SET, records which components have been seen.
+	seen map[int]bool
+	// values maps the names of components seen so far to their values, for
+	// use with ANY DEFINED BY. OIDs are stored in dotted form and other
+	// values in hex.
+	values map[string]string
+}
+
+func (c *componentStructure) next(elem element) ([]string, structure) {
+	components := c.typ.components
+	var comments []string
+	match := -1
+	if c.typ.kind == schemaSet {
+		if c.seen == nil {
+			c.seen = make(map[int]bool)
+		}
+		for i, comp := range components {
+			if !c.seen[i] && c.schema.matches(comp.typ, elem.tag) {
+				match = i
+				c.seen[i] = true
+				break
+			}
+		}
+	} else {
+		skippedRequired := false
+		for i := c.pos; i < len(components); i++ {
+			if c.schema.matches(components[i].typ, elem.tag) {
+				// Only skip a required component if the
+				// element matches something more specific
+				// than ANY.
+				if !skippedRequired || !c.schema.isAny(components[i].typ) {
+					match = i
+				}
+				break
+			}
+			if !components[i].optional {
+				skippedRequired = true
+			}
+		}
+		if match >= 0 {
+			for _, comp := range components[c.pos:match] {
+				if !comp.optional {
+					comments = append(comments, mismatch("missing %s", c.schema.describeComponent(comp)))
+				}
+			}
+			c.pos = match + 1
+		}
+	}
+
+	if match < 0 {
+		if c.typ.kind == schemaSequence {
+			for _, comp := range components[c.pos:] {
+				if !comp.optional {
+					return []string{mismatch("expected %s, found %s", c.schema.describeComponent(comp), c.schema.tagName(elem.tag))}, nil
+				}
+			}
+		}
+		// Unknown elements are allowed after the known components of an
+		// extensible type.
+		if c.typ.extensible {
+			return nil, nil
+		}
+		return []string{mismatch("unexpected %s", c.schema.tagName(elem.tag))}, nil
+	}
+
+	comp := components[match]
+	if elem.tag.Class == internal.ClassUniversal && elem.tag.Number == 6 {
+		c.values[comp.name] = objectIdentifierToString(elem.body)
+	} else {
+		c.values[comp.name] = fmt.Sprintf("%x", elem.body)
+	}
+	more, body := c.schema.annotate(comp.name, comp.typ, elem, c, false)
+	return append(comments, more...), body
+}
+
+func (c *componentStructure) end() []string {
+	var missing []int
+	for i, comp := range c.typ.components {
+		if comp.optional {
+			continue
+		}
+		if c.typ.kind == schemaSet && !c.seen[i] || c.typ.kind == schemaSequence && i >= c.pos {
+			missing = append(missing, i)
+		}
+	}
+	sort.Ints(missing)
+	var comments []string
+	for _, i := range missing {
+		comments = append(comments, mismatch("missing %s", c.schema.describeComponent(c.typ.components[i])))
+	}
+	return comments
+}
+
+// newSchemaStructure returns a structure which expects a single element of
+// the named type or, if repeated is true, any number of them.
+func newSchemaStructure(s *schema, name string, repeated bool) (structure, error) {
+	if s.lookup(name) == nil {
+		return nil, fmt.Errorf("type %s is not defined", name)
+	}
+	return &elementStructure{schema: s, typ: &schemaType{kind: schemaReference, name: name}, repeated: repeated}, nil
+}
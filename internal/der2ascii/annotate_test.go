@@ -0,0 +1,219 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package der2ascii
+
+import "testing"
+
+var annotateTests = []struct {
+	typ string
+	in  []byte
+	out string
+}{
+	{
+		"Outer",
+		[]byte{0x30, 0x1a, 0xa0, 0x03, 0x02, 0x01, 0x01, 0x06, 0x03, 0x2a, 0x86, 0x48, 0x05, 0x00, 0x81, 0x02, 0x05, 0xa0, 0x30, 0x03, 0x0c, 0x01, 0x78, 0xa2, 0x03, 0x02, 0x01, 0x05},
+		`# Outer
+SEQUENCE {
+  # version (Version)
+  [0] {
+    # Version: v2
+    INTEGER { 1 }
+  }
+  # id (OBJECT IDENTIFIER)
+  OBJECT_IDENTIFIER { 1.2.840 }
+  # schema mismatch: expected Name, found NULL
+  NULL {}
+  # flags (Flags): a, c
+  [1 PRIMITIVE] { ` + "`05a0`" + ` }
+  # names (SEQUENCE OF Name)
+  SEQUENCE {
+    # Name
+    UTF8String { "x" }
+  }
+  # choice (Choice)
+  [2] {
+    # Choice: number
+    INTEGER { 5 }
+  }
+}
+`,
+	},
+	// ANY DEFINED BY is resolved, and indefinite-length elements are
+	// annotated.
+	{
+		"Outer",
+		[]byte{0x30, 0x80, 0xa0, 0x80, 0x02, 0x01, 0x01, 0x00, 0x00, 0x06, 0x03, 0x2a, 0x86, 0x48, 0x0c, 0x01, 0x78, 0x30, 0x03, 0x0c, 0x01, 0x78, 0xa2, 0x03, 0x16, 0x01, 0x61, 0x00, 0x00},
+		`# Outer
+SEQUENCE indefinite {
+  # version (Version)
+  [0] indefinite {
+    # Version: v2
+    INTEGER { 1 }
+  }
+  # id (OBJECT IDENTIFIER)
+  OBJECT_IDENTIFIER { 1.2.840 }
+  # params (Name)
+  UTF8String { "x" }
+  # names (SEQUENCE OF Name)
+  SEQUENCE {
+    # Name
+    UTF8String { "x" }
+  }
+  # choice (Choice)
+  [2] {
+    # Choice: text
+    IA5String { "a" }
+  }
+}
+`,
+	},
+	// Deviations from the schema are marked.
+	{
+		"Outer",
+		[]byte{0x30, 0x03, 0x02, 0x01, 0x01, 0x05, 0x00},
+		`# Outer
+SEQUENCE {
+  # schema mismatch: expected id (OBJECT IDENTIFIER), found INTEGER
+  INTEGER { 1 }
+  # schema mismatch: missing id (OBJECT IDENTIFIER)
+  # schema mismatch: missing names (SEQUENCE OF Name)
+  # schema mismatch: missing choice (Choice)
+}
+# schema mismatch: unexpected NULL
+NULL {}
+`,
+	},
+	{
+		"Outer",
+		[]byte{0x02, 0x01, 0x01},
+		`# schema mismatch: expected Outer, found INTEGER
+INTEGER { 1 }
+`,
+	},
+	{
+		"Outer",
+		[]byte{0x30, 0x00},
+		`# Outer
+SEQUENCE {
+  # schema mismatch: missing id (OBJECT IDENTIFIER)
+  # schema mismatch: missing names (SEQUENCE OF Name)
+  # schema mismatch: missing choice (Choice)
+}
+`,
+	},
+	{
+		"Outer",
+		[]byte{},
+		`# schema mismatch: missing Outer
+`,
+	},
+	// CONTAINING constraints are followed.
+	{
+		"Wrapped",
+		[]byte{0x04, 0x05, 0x30, 0x03, 0x02, 0x01, 0x0a},
+		`# Wrapped
+OCTET_STRING {
+  # Outer
+  SEQUENCE {
+    # schema mismatch: expected id (OBJECT IDENTIFIER), found INTEGER
+    INTEGER { 10 }
+    # schema mismatch: missing id (OBJECT IDENTIFIER)
+    # schema mismatch: missing names (SEQUENCE OF Name)
+    # schema mismatch: missing choice (Choice)
+  }
+}
+`,
+	},
+	// Automatic tagging.
+	{
+		"AutoSet",
+		[]byte{0x31, 0x06, 0x81, 0x01, 0xff, 0x80, 0x01, 0x05},
+		`# AutoSet
+SET {
+  # y (BOOLEAN)
+  [1 PRIMITIVE] { ` + "`ff`" + ` }
+  # x (INTEGER)
+  [0 PRIMITIVE] { ` + "`05`" + ` }
+}
+`,
+	},
+	{
+		"AutoSeq",
+		[]byte{0x30, 0x03, 0x80, 0x01, 0xff},
+		`# AutoSeq
+SEQUENCE {
+  # a (INTEGER)
+  [0 PRIMITIVE] { ` + "`ff`" + ` }
+  # schema mismatch: missing c (Choice)
+}
+`,
+	},
+}
+
+func TestAnnotate(t *testing.T) {
+	s, err := parseSchema(testSchema)
+	if err != nil {
+		t.Fatalf("parseSchema failed: %s", err)
+	}
+	s.definedBy["1.2.840"] = &schemaType{kind: schemaReference, name: "Name"}
+	for i, tt := range annotateTests {
+		st, err := newSchemaStructure(s, tt.typ, false)
+		if err != nil {
+			t.Fatalf("%d. newSchemaStructure failed: %s", i, err)
+		}
+		if out := derToASCIIWithStructure(tt.in, st); out != tt.out {
+			t.Errorf("%d. derToASCIIWithStructure(%x, %s) = %q, want %q.", i, tt.in, tt.typ, out, tt.out)
+		}
+	}
+}
+
+func TestAnnotateScopedDefinedBy(t *testing.T) {
+	s, err := parseSchema(testSchema)
+	if err != nil {
+		t.Fatalf("parseSchema failed: %s", err)
+	}
+	// Keys scoped to a type take precedence.
+	s.definedBy["1.2.840"] = &schemaType{kind: schemaReference, name: "Name"}
+	s.definedBy["Outer 1.2.840"], err = s.parseType("OCTET STRING (CONTAINING Name)")
+	if err != nil {
+		t.Fatalf("parseType failed: %s", err)
+	}
+	st, err := newSchemaStructure(s, "Outer", false)
+	if err != nil {
+		t.Fatalf("newSchemaStructure failed: %s", err)
+	}
+	in := []byte{0x30, 0x11, 0x06, 0x03, 0x2a, 0x86, 0x48, 0x04, 0x03, 0x0c, 0x01, 0x78, 0x30, 0x00, 0xa2, 0x03, 0x02, 0x01, 0x05}
+	want := `# Outer
+SEQUENCE {
+  # id (OBJECT IDENTIFIER)
+  OBJECT_IDENTIFIER { 1.2.840 }
+  # params (OCTET STRING containing Name)
+  OCTET_STRING {
+    # Name
+    UTF8String { "x" }
+  }
+  # names (SEQUENCE OF Name)
+  SEQUENCE {}
+  # choice (Choice)
+  [2] {
+    # Choice: number
+    INTEGER { 5 }
+  }
+}
+`
+	if out := derToASCIIWithStructure(in, st); out != want {
+		t.Errorf("derToASCIIWithStructure(%x) = %q, want %q.", in, out, want)
+	}
+}
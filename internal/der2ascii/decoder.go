@@ -0,0 +1,439 @@
+// Copyright 2015 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package der2ascii
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/google/der-ascii/internal"
+)
+
+// parseBase128 parses a minimally-encoded base-128 integer from bytes. It fails
+// if the value does not fit in a uint64.
+func parseBase128(bytes []byte) (ret uint64, rest []byte, ok bool) {
+	rest = bytes
+	// There must be at least one byte, and the value must be minimally-encoded.
+	if len(rest) == 0 || rest[0] == 0x80 {
+		return
+	}
+	for {
+		if len(rest) == 0 || (ret<<7)>>7 != ret {
+			// Input too small or would overflow.
+			return
+		}
+		b := rest[0]
+		ret = (ret << 7) | uint64(b&0x7f)
+		rest = rest[1:]
+		if b&0x80 == 0 {
+			ok = true
+			return
+		}
+	}
+}
+
+// parseBigBase128 behaves like parseBase128, but parses values of any size
+// whose encoding is at most internal.MaxBase128Length bytes.
+func parseBigBase128(bytes []byte) (ret *big.Int, rest []byte, ok bool) {
+	if v, rest, ok := parseBase128(bytes); ok {
+		return new(big.Int).SetUint64(v), rest, true
+	}
+	rest = bytes
+	if len(rest) == 0 || rest[0] == 0x80 {
+		return
+	}
+	// Find the end of the value.
+	var n int
+	for n < len(rest) && n < internal.MaxBase128Length && rest[n]&0x80 != 0 {
+		n++
+	}
+	if n == len(rest) || n == internal.MaxBase128Length {
+		return // Input too small or value too large.
+	}
+	// Pack the digits into big-endian bytes, starting from the least
+	// significant, so the conversion takes linear time.
+	digits := rest[:n+1]
+	out := make([]byte, (7*len(digits)+7)/8)
+	i := len(out)
+	var acc, bits uint
+	for j := len(digits) - 1; j >= 0; j-- {
+		acc |= uint(digits[j]&0x7f) << bits
+		bits += 7
+		for bits >= 8 {
+			i--
+			out[i] = byte(acc)
+			acc >>= 8
+			bits -= 8
+		}
+	}
+	if bits != 0 {
+		i--
+		out[i] = byte(acc)
+	}
+	return new(big.Int).SetBytes(out[i:]), rest[n+1:], true
+}
+
+// parseBase128Lax parses a base-128 integer of any size from bytes, tolerating
+// non-minimal encodings. If the value fits in a uint64, it is returned in ret
+// and bigRet is nil. Otherwise, it is returned in bigRet.
+func parseBase128Lax(bytes []byte) (ret uint64, bigRet *big.Int, lengthOverride int, rest []byte, ok bool) {
+	rest = bytes
+	// Tolerate non-minimal inputs.
+	isMinimal := true
+	for len(rest) != 0 && rest[0] == 0x80 {
+		isMinimal = false
+		rest = rest[1:]
+	}
+	digits := rest
+	ret, rest, ok = parseBase128(digits)
+	if !ok {
+		// The value may be too large for a uint64.
+		ret = 0
+		bigRet, rest, ok = parseBigBase128(digits)
+	}
+	if ok && !isMinimal {
+		// Tell the caller how to reconstruct the non-minimal input.
+		lengthOverride = len(bytes) - len(rest)
+	}
+	return
+}
+
+// parseTag parses a tag from b, returning the resulting tag and the remainder
+// of the slice. On parse failure, ok is returned as false and rest is
+// unchanged.
+func parseTag(bytes []byte) (tag internal.Tag, rest []byte, ok bool) {
+	rest = bytes
+
+	// Consume the first byte.
+	if len(rest) == 0 {
+		return
+	}
+	b := rest[0]
+	rest = rest[1:]
+
+	class := internal.Class(b & 0xc0)
+	number := uint64(b & 0x1f)
+	constructed := b&0x20 != 0
+	if number < 0x1f {
+		// Low-tag-number form.
+		tag = internal.Tag{Class: class, Number: number, Constructed: constructed}
+		ok = true
+		return
+	}
+
+	n, bigN, lengthOverride, rest, base128Ok := parseBase128Lax(rest)
+	if !base128Ok {
+		// Parse error.
+		rest = bytes
+		return
+	}
+	var bigNumber string
+	if bigN != nil {
+		bigNumber = bigN.String()
+	} else if n < 0x1f {
+		// Non-minimal encoding.
+		lengthOverride = len(bytes) - len(rest) - 1
+	}
+	number = n
+
+	tag = internal.Tag{Class: class, Number: number, Constructed: constructed, LongFormOverride: lengthOverride, BigNumber: bigNumber}
+	ok = true
+	return
+}
+
+type element struct {
+	tag              internal.Tag
+	body             []byte
+	indefinite       bool
+	longFormOverride int
+}
+
+// parseTagAndLength parses a tag and length pair from bytes. It is split out
+// of parseElement so tests can distinguish failing to parse a length from the
+// rest of the body.
+func parseTagAndLength(bytes []byte) (elem element, length int64, rest []byte, ok bool) {
+	rest = bytes
+
+	// Parse the tag.
+	var tagOk bool
+	elem.tag, bytes, tagOk = parseTag(bytes)
+	if !tagOk {
+		return
+	}
+
+	// Parse the length.
+	if len(bytes) == 0 {
+		return
+	}
+	b := bytes[0]
+	bytes = bytes[1:]
+	if b < 0x80 {
+		// Short form length.
+		length = int64(b)
+	} else if b == 0x80 {
+		if !elem.tag.Constructed {
+			return // Indefinite-length elements must be constructed.
+		}
+		elem.indefinite = true
+	} else {
+		// Long form length.
+		b &= 0x7f
+		if int(b) > len(bytes) {
+			return // Not enough room.
+		}
+		for i := 0; i < int(b); i++ {
+			if length >= 1<<55 {
+				return // Overflow.
+			}
+			length <<= 8
+			length |= int64(bytes[i])
+		}
+		if bytes[0] == 0 || length < 0x80 {
+			elem.longFormOverride = int(b) // Non-minimal length.
+		}
+		bytes = bytes[b:]
+	}
+	ok = true
+	rest = bytes
+	return
+}
+
+// parseElement parses an element from bytes. If the element is
+// indefinite-length body is left as nil and instead indefinite is set to true.
+// Note this function will treat a BER EOC as an empty element with tag number
+// zero. EOC detection must be handled externally.
+func parseElement(bytes []byte) (elem element, rest []byte, ok bool) {
+	rest = bytes
+	var length int64
+	elem, length, bytes, ok = parseTagAndLength(bytes)
+	if !ok {
+		return
+	}
+
+	if !elem.indefinite {
+		if length > int64(len(bytes)) {
+			ok = false
+			return
+		}
+		elem.body = bytes[:length]
+		bytes = bytes[length:]
+	}
+	rest = bytes
+	return
+}
+
+// decodeInteger decodes bytes as the contents of a DER INTEGER. It returns the
+// value on success and false otherwise.
+func decodeInteger(bytes []byte) (int64, bool) {
+	if len(bytes) == 0 {
+		return 0, false
+	}
+
+	// Reject non-minimal encodings.
+	if len(bytes) > 1 && (bytes[0] == 0 || bytes[0] == 0xff) && bytes[0]&0x80 == bytes[1]&0x80 {
+		return 0, false
+	}
+
+	val := int64(bytes[0])
+	if val&0x80 != 0 {
+		val -= 256
+	}
+	for _, v := range bytes[1:] {
+		if (val<<8)>>8 != val {
+			return 0, false
+		}
+		val <<= 8
+		val |= int64(v)
+	}
+	return val, true
+}
+
+// decodeBigInteger decodes bytes as the contents of a DER INTEGER of any
+// size. It returns the value on success and false otherwise.
+func decodeBigInteger(bytes []byte) (*big.Int, bool) {
+	if len(bytes) == 0 {
+		return nil, false
+	}
+
+	// Reject non-minimal encodings.
+	if len(bytes) > 1 && (bytes[0] == 0 || bytes[0] == 0xff) && bytes[0]&0x80 == bytes[1]&0x80 {
+		return nil, false
+	}
+
+	val := new(big.Int).SetBytes(bytes)
+	if bytes[0]&0x80 != 0 {
+		// The value is negative, so subtract 2^(8*len(bytes)).
+		val.Sub(val, new(big.Int).Lsh(big.NewInt(1), uint(8*len(bytes))))
+	}
+	return val, true
+}
+
+// decodeObjectIdentifier decodes bytes as the contents of a DER OBJECT IDENTIFIER. It
+// returns the value on success and false otherwise. Components may be of any
+// size.
+func decodeObjectIdentifier(bytes []byte) (oid []*big.Int, ok bool) {
+	// Reserve a space as the first component is split.
+	oid = []*big.Int{new(big.Int)}
+
+	// Decode each component.
+	for len(bytes) != 0 {
+		var c *big.Int
+		c, bytes, ok = parseBigBase128(bytes)
+		if !ok {
+			return nil, false
+		}
+		oid = append(oid, c)
+	}
+
+	// OIDs must have at least two components.
+	if len(oid) < 2 {
+		return nil, false
+	}
+
+	// Adjust the first component.
+	if oid[1].Cmp(big.NewInt(80)) >= 0 {
+		oid[0].SetInt64(2)
+		oid[1].Sub(oid[1], big.NewInt(80))
+	} else if oid[1].Cmp(big.NewInt(40)) >= 0 {
+		oid[0].SetInt64(1)
+		oid[1].Sub(oid[1], big.NewInt(40))
+	}
+
+	return oid, true
+}
+
+// decodeRelativeOID decodes bytes as the contents of a DER RELATIVE-OID. It
+// returns the value on success and false otherwise.
+func decodeRelativeOID(bytes []byte) (oid []*big.Int, ok bool) {
+	// Decode each component.
+	for len(bytes) != 0 {
+		var c *big.Int
+		c, bytes, ok = parseBigBase128(bytes)
+		if !ok {
+			return nil, false
+		}
+		oid = append(oid, c)
+	}
+
+	// Relative OIDs must have at least one component.
+	if len(oid) < 1 {
+		return nil, false
+	}
+
+	return oid, true
+}
+
+// decodeReal decodes bytes as the contents of a DER REAL. On success, it returns
+// the value as written in a REAL token, without the "real:" prefix, and true.
+// Binary values which are exact doubles are written in decimal. It returns false
+// if bytes is not a valid DER encoding.
+func decodeReal(bytes []byte) (string, bool) {
+	if len(bytes) == 0 {
+		return "0", true
+	}
+
+	switch b := bytes[0]; {
+	case b&0x80 != 0:
+		// DER requires base 2 and no scale factor.
+		if b&0x3c != 0 {
+			return "", false
+		}
+		rest := bytes[1:]
+		expLen := int(b&3) + 1
+		if expLen == 4 {
+			if len(rest) == 0 || rest[0] < 4 {
+				return "", false
+			}
+			expLen, rest = int(rest[0]), rest[1:]
+		}
+		if len(rest) <= expLen {
+			return "", false
+		}
+		exp, ok := decodeBigInteger(rest[:expLen])
+		if !ok {
+			return "", false
+		}
+		// The mantissa must be odd and minimally-encoded.
+		mantissa := rest[expLen:]
+		if mantissa[0] == 0 || mantissa[len(mantissa)-1]&1 == 0 {
+			return "", false
+		}
+		m := new(big.Int).SetBytes(mantissa)
+		sign := ""
+		if b&0x40 != 0 {
+			sign = "-"
+		}
+		if exp.IsInt64() && m.BitLen() <= 53 {
+			if e := exp.Int64(); -1100 < e && e < 1100 {
+				f, acc := new(big.Float).SetMantExp(new(big.Float).SetInt(m), int(e)).Float64()
+				if acc == big.Exact {
+					return sign + strconv.FormatFloat(f, 'g', -1, 64), true
+				}
+			}
+		}
+		return fmt.Sprintf("%s%s*2^%s", sign, m, exp), true
+	case len(bytes) != 1 && b&0xc0 == 0x40:
+		return "", false
+	case b == 0x40:
+		return "PLUS-INFINITY", true
+	case b == 0x41:
+		return "MINUS-INFINITY", true
+	case b == 0x42:
+		return "NaN", true
+	case b == 0x43:
+		return "-0", true
+	case b == 0x03:
+		// DER requires the canonical NR3 form.
+		if s := string(bytes[1:]); isCanonicalNR3(s) {
+			return "nr3:" + s, true
+		}
+	}
+	return "", false
+}
+
+// isCanonicalNR3 returns whether s is a decimal REAL value in the NR3 form
+// required by DER: an integer mantissa with no leading or trailing zeros,
+// followed by ".E" and an exponent with no leading zeros, which is "+0" if
+// zero.
+func isCanonicalNR3(s string) bool {
+	mantissa, exp, ok := strings.Cut(s, ".E")
+	if !ok {
+		return false
+	}
+	mantissa = strings.TrimPrefix(mantissa, "-")
+	if !isDigits(mantissa) || mantissa[0] == '0' || mantissa[len(mantissa)-1] == '0' {
+		return false
+	}
+	if exp == "+0" {
+		return true
+	}
+	exp = strings.TrimPrefix(exp, "-")
+	return isDigits(exp) && exp[0] != '0'
+}
+
+// isDigits returns whether s is a non-empty string of decimal digits.
+func isDigits(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
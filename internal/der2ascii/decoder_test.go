@@ -12,12 +12,14 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package main
+package der2ascii
 
 import (
 	"bytes"
 	"math"
+	"math/big"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/google/der-ascii/internal"
@@ -41,9 +43,14 @@ var parseTagTests = []struct {
 	// Non-minimal encoding.
 	{[]byte{0x7f, 0x00}, internal.Tag{Class: internal.ClassApplication, Number: 0, Constructed: true, LongFormOverride: 1}, true},
 	{[]byte{0x7f, 0x80, 0x01}, internal.Tag{Class: internal.ClassApplication, Number: 1, Constructed: true, LongFormOverride: 2}, true},
-	// Overflow.
+	// Large tag numbers.
 	{[]byte{0xff, 0x8f, 0xff, 0xff, 0xff, 0x7f}, internal.Tag{Class: internal.ClassPrivate, Number: (1 << 32) - 1, Constructed: true}, true},
-	{[]byte{0xff, 0x9f, 0xff, 0xff, 0xff, 0x7f}, internal.Tag{}, false},
+	{[]byte{0xff, 0x9f, 0xff, 0xff, 0xff, 0x7f}, internal.Tag{Class: internal.ClassPrivate, Number: (1 << 33) - 1, Constructed: true}, true},
+	{[]byte{0xff, 0x81, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f}, internal.Tag{Class: internal.ClassPrivate, Number: math.MaxUint64, Constructed: true}, true},
+	// Tag numbers too large for a uint64.
+	{[]byte{0xff, 0x82, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x00}, internal.Tag{Class: internal.ClassPrivate, Constructed: true, BigNumber: "18446744073709551616"}, true},
+	{[]byte{0xff, 0x80, 0x82, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x00}, internal.Tag{Class: internal.ClassPrivate, Constructed: true, LongFormOverride: 11, BigNumber: "18446744073709551616"}, true},
+	{[]byte{0xff, 0x82, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80}, internal.Tag{}, false},
 	// Universal tag zero is reserved for EOC, but we parse it here because
 	// DER and BER parsers sometimes accept such elements in ANY.
 	{[]byte{0x00}, internal.Tag{Number: 0}, true},
@@ -79,13 +86,63 @@ func TestParseTag(t *testing.T) {
 	}
 }
 
+// longBase128 returns the base-128 encoding of 2^(7*n)-1, which is n bytes
+// long.
+func longBase128(n int) []byte {
+	return append(bytes.Repeat([]byte{0xff}, n-1), 0x7f)
+}
+
+func TestParseBigBase128(t *testing.T) {
+	for _, in := range [][]byte{
+		{0x01},
+		{0x81, 0x00},
+		{0x82, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x00},
+		{0x83, 0xf0, 0x9d, 0xa7, 0xeb, 0xcf, 0xde, 0xe0, 0xc7, 0xa1, 0xa7, 0xb2, 0xc0, 0x94, 0x8c, 0xc8, 0xf9, 0xd7, 0x77},
+		longBase128(internal.MaxBase128Length),
+	} {
+		want := new(big.Int)
+		for _, b := range in {
+			want.Lsh(want, 7)
+			want.Or(want, big.NewInt(int64(b&0x7f)))
+		}
+		out, rest, ok := parseBigBase128(append(in, 0x01))
+		if !ok {
+			t.Errorf("parseBigBase128(%x) unexpectedly failed.", in)
+		} else if out.Cmp(want) != 0 || !bytes.Equal(rest, []byte{0x01}) {
+			t.Errorf("parseBigBase128(%x) = %v, %x, wanted %v, 01.", in, out, rest, want)
+		}
+	}
+
+	// Longer values are rejected.
+	if _, _, ok := parseBigBase128(longBase128(internal.MaxBase128Length + 1)); ok {
+		t.Errorf("parseBigBase128 unexpectedly accepted a value of %d bytes.", internal.MaxBase128Length+1)
+	}
+}
+
+func TestLongBase128(t *testing.T) {
+	// Tags and OID components longer than internal.MaxBase128Length
+	// bytes are written as hex.
+	tag := append([]byte{0xdf}, longBase128(internal.MaxBase128Length+1)...)
+	if out, want := derToASCII(append(tag, 0x00)), bytesToString(append(tag, 0x00))+"\n"; out != want {
+		t.Errorf("derToASCII of a long tag = %q, wanted %q.", out, want)
+	}
+	oid := append([]byte{0x2a}, longBase128(internal.MaxBase128Length+1)...)
+	if out := objectIdentifierToString(oid); out != bytesToHexString(oid) {
+		t.Errorf("objectIdentifierToString of a long OID = %q, wanted hex.", out)
+	}
+	oid = append([]byte{0x2a}, longBase128(internal.MaxBase128Length)...)
+	if out := objectIdentifierToString(oid); !strings.HasPrefix(out, "1.2.") {
+		t.Errorf("objectIdentifierToString of a long OID = %q, wanted decimal.", out)
+	}
+}
+
 var sequenceTag = internal.Tag{Class: internal.ClassUniversal, Number: 16, Constructed: true}
 var zeroTag = internal.Tag{Class: internal.ClassUniversal, Number: 0, Constructed: false}
 
 var parseTagAndLengthTests = []struct {
 	in     []byte
 	elem   element
-	length int
+	length int64
 	ok     bool
 }{
 	// Short-form length.
@@ -103,8 +160,12 @@ var parseTagAndLengthTests = []struct {
 	// Non-minimal form length.
 	{[]byte{0x30, 0x82, 0x00, 0xff}, element{tag: sequenceTag, longFormOverride: 2}, 255, true},
 	{[]byte{0x30, 0x81, 0x1f}, element{tag: sequenceTag, longFormOverride: 1}, 31, true},
+	// 64-bit lengths.
+	{[]byte{0x30, 0x85, 0xff, 0xff, 0xff, 0xff, 0xff}, element{tag: sequenceTag}, 1<<40 - 1, true},
+	{[]byte{0x30, 0x88, 0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, element{tag: sequenceTag}, math.MaxInt64, true},
+	{[]byte{0x30, 0x89, 0x00, 0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, element{tag: sequenceTag, longFormOverride: 9}, math.MaxInt64, true},
 	// Overflow.
-	{[]byte{0x30, 0x85, 0xff, 0xff, 0xff, 0xff, 0xff}, element{}, 0, false},
+	{[]byte{0x30, 0x88, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, element{}, 0, false},
 	// Empty.
 	{[]byte{}, element{}, 0, false},
 	// Primitive + indefinite length is illegal.
@@ -231,33 +292,129 @@ func TestDecodeInteger(t *testing.T) {
 	}
 }
 
-func eqUint32s(a, b []uint32) bool {
-	if len(a) != len(b) {
-		return false
+var decodeRealTests = []struct {
+	in  []byte
+	out string
+	ok  bool
+}{
+	// Special values.
+	{[]byte{}, "0", true},
+	{[]byte{0x40}, "PLUS-INFINITY", true},
+	{[]byte{0x41}, "MINUS-INFINITY", true},
+	{[]byte{0x42}, "NaN", true},
+	{[]byte{0x43}, "-0", true},
+	{[]byte{0x40, 0x00}, "", false},
+	{[]byte{0x44}, "", false},
+	// Binary values which are exact doubles are written in decimal.
+	{[]byte{0x80, 0x00, 0x01}, "1", true},
+	{[]byte{0x80, 0xff, 0x03}, "1.5", true},
+	{[]byte{0xc0, 0xfd, 0x03}, "-0.375", true},
+	{[]byte{0x80, 0xc9, 0x0c, 0xcc, 0xcc, 0xcc, 0xcc, 0xcc, 0xcd}, "0.1", true},
+	{[]byte{0x81, 0xfb, 0xce, 0x01}, "5e-324", true},
+	// Other binary values are written explicitly.
+	{[]byte{0x80, 0x00, 0x20, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01}, "9007199254740993*2^0", true},
+	{[]byte{0x81, 0xfb, 0xcd, 0x01}, "1*2^-1075", true},
+	{[]byte{0xc3, 0x04, 0x01, 0x00, 0x00, 0x00, 0x01}, "-1*2^16777216", true},
+	// Non-canonical binary values.
+	{[]byte{0x80, 0x00}, "", false},
+	{[]byte{0x80, 0x00, 0x02}, "", false},
+	{[]byte{0x80, 0x00, 0x00, 0x01}, "", false},
+	{[]byte{0x81, 0x00, 0x01, 0x01}, "", false},
+	{[]byte{0x83, 0x01, 0x01, 0x01}, "", false},
+	{[]byte{0x83, 0x04, 0x00, 0x00, 0x00, 0x01, 0x01}, "", false},
+	{[]byte{0x90, 0x00, 0x01}, "", false},
+	{[]byte{0xa0, 0x00, 0x01}, "", false},
+	{[]byte{0x84, 0x00, 0x01}, "", false},
+	{[]byte{0x81, 0x00}, "", false},
+	// Decimal values must use canonical NR3 form.
+	{[]byte("\x031.E-1"), "nr3:1.E-1", true},
+	{[]byte("\x03-123.E+0"), "nr3:-123.E+0", true},
+	{[]byte("\x0310.E+0"), "", false},
+	{[]byte("\x0301.E+0"), "", false},
+	{[]byte("\x031.E0"), "", false},
+	{[]byte("\x031.E-01"), "", false},
+	{[]byte("\x031.e1"), "", false},
+	{[]byte("\x03+1.E1"), "", false},
+	{[]byte("\x021.5"), "", false},
+	{[]byte("\x0142"), "", false},
+}
+
+func TestDecodeReal(t *testing.T) {
+	for i, tt := range decodeRealTests {
+		out, ok := decodeReal(tt.in)
+		if !tt.ok {
+			if ok {
+				t.Errorf("%d. decodeReal(%x) unexpectedly succeeded.", i, tt.in)
+			}
+		} else if !ok {
+			t.Errorf("%d. decodeReal(%x) unexpectedly failed.", i, tt.in)
+		} else if out != tt.out {
+			t.Errorf("%d. decodeReal(%x) = %q wanted %q.", i, tt.in, out, tt.out)
+		}
 	}
-	for i := range a {
-		if a[i] != b[i] {
-			return false
+}
+
+var decodeBigIntegerTests = []struct {
+	in  []byte
+	out string
+	ok  bool
+}{
+	{[]byte{0x00}, "0", true},
+	{[]byte{0xff}, "-1", true},
+	{[]byte{0x00, 0x80}, "128", true},
+	{[]byte{0x80}, "-128", true},
+	{[]byte{0x00, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, "18446744073709551615", true},
+	{[]byte{0xff, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, "-18446744073709551616", true},
+	// Empty encoding.
+	{[]byte{}, "", false},
+	// Non-minimal encodings.
+	{[]byte{0x00, 0x01}, "", false},
+	{[]byte{0xff, 0xff}, "", false},
+}
+
+func TestDecodeBigInteger(t *testing.T) {
+	for i, tt := range decodeBigIntegerTests {
+		out, ok := decodeBigInteger(tt.in)
+		if !tt.ok {
+			if ok {
+				t.Errorf("%d. decodeBigInteger(%v) unexpectedly succeeded.", i, tt.in)
+			}
+		} else if !ok {
+			t.Errorf("%d. decodeBigInteger(%v) unexpectedly failed.", i, tt.in)
+		} else if out.String() != tt.out {
+			t.Errorf("%d. decodeBigInteger(%v) = %v wanted %v.", i, tt.in, out, tt.out)
 		}
 	}
-	return true
+}
+
+// joinBigInts returns the components of an OID joined with dots.
+func joinBigInts(oid []*big.Int) string {
+	var s []string
+	for _, v := range oid {
+		s = append(s, v.String())
+	}
+	return strings.Join(s, ".")
 }
 
 var decodeObjectIdentifierTests = []struct {
 	in  []byte
-	out []uint32
+	out string
 	ok  bool
 }{
-	{[]byte{1}, []uint32{0, 1}, true},
-	{[]byte{42, 3, 4, 0x7f, 0x81, 0x00, 0x81, 0x01}, []uint32{1, 2, 3, 4, 127, 128, 129}, true},
-	{[]byte{81}, []uint32{2, 1}, true},
-	{[]byte{0x8f, 0xff, 0xff, 0xff, 0x7f}, []uint32{2, math.MaxUint32 - 80}, true},
+	{[]byte{1}, "0.1", true},
+	{[]byte{42, 3, 4, 0x7f, 0x81, 0x00, 0x81, 0x01}, "1.2.3.4.127.128.129", true},
+	{[]byte{81}, "2.1", true},
+	{[]byte{0x8f, 0xff, 0xff, 0xff, 0x7f}, "2.4294967215", true},
+	{[]byte{0x9f, 0xff, 0xff, 0xff, 0x7f}, "2.8589934511", true},
+	// A UUID-based OID.
+	{[]byte{0x69, 0x83, 0xf0, 0x9d, 0xa7, 0xeb, 0xcf, 0xde, 0xe0, 0xc7, 0xa1, 0xa7, 0xb2, 0xc0, 0x94, 0x8c, 0xc8, 0xf9, 0xd7, 0x77}, "2.25.329800735698586629295641978511506172919", true},
 	// Empty.
-	{[]byte{}, nil, false},
+	{[]byte{}, "", false},
 	// Incomplete component.
-	{[]byte{0xff}, nil, false},
-	// Overflow.
-	{[]byte{0x9f, 0xff, 0xff, 0xff, 0x7f}, nil, false},
+	{[]byte{0xff}, "", false},
+	{[]byte{0x2a, 0x86, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80}, "", false},
+	// Non-minimal component.
+	{[]byte{0x2a, 0x80, 0x01}, "", false},
 }
 
 func TestDecodeObjectIdentifier(t *testing.T) {
@@ -269,26 +426,25 @@ func TestDecodeObjectIdentifier(t *testing.T) {
 			}
 		} else if !ok {
 			t.Errorf("%d. decodeObjectIdentifier(%v) unexpectedly failed.", i, tt.in)
-		} else if !eqUint32s(out, tt.out) {
-			t.Errorf("%d. decodeObjectIdentifier(%v) = %v wanted %v.", i, tt.in, out, tt.out)
+		} else if joinBigInts(out) != tt.out {
+			t.Errorf("%d. decodeObjectIdentifier(%v) = %v wanted %v.", i, tt.in, joinBigInts(out), tt.out)
 		}
 	}
 }
 
 var decodeRelativeOIDTests = []struct {
 	in  []byte
-	out []uint32
+	out string
 	ok  bool
 }{
-	{[]byte{1}, []uint32{1}, true},
-	{[]byte{1, 2, 3, 4, 0x7f, 0x81, 0x00, 0x81, 0x01}, []uint32{1, 2, 3, 4, 127, 128, 129}, true},
-	{[]byte{0x8f, 0xff, 0xff, 0xff, 0x7f}, []uint32{math.MaxUint32}, true},
+	{[]byte{1}, "1", true},
+	{[]byte{1, 2, 3, 4, 0x7f, 0x81, 0x00, 0x81, 0x01}, "1.2.3.4.127.128.129", true},
+	{[]byte{0x8f, 0xff, 0xff, 0xff, 0x7f}, "4294967295", true},
+	{[]byte{0x82, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x00}, "18446744073709551616", true},
 	// Empty.
-	{[]byte{}, nil, false},
+	{[]byte{}, "", false},
 	// Incomplete component.
-	{[]byte{0xff}, nil, false},
-	// Overflow.
-	{[]byte{0x9f, 0xff, 0xff, 0xff, 0x7f}, nil, false},
+	{[]byte{0xff}, "", false},
 }
 
 func TestDecodeRelativeOID(t *testing.T) {
@@ -300,8 +456,8 @@ func TestDecodeRelativeOID(t *testing.T) {
 			}
 		} else if !ok {
 			t.Errorf("%d. decodeRelativeOID(%v) unexpectedly failed.", i, tt.in)
-		} else if !eqUint32s(out, tt.out) {
-			t.Errorf("%d. decodeRelativeOID(%v) = %v wanted %v.", i, tt.in, out, tt.out)
+		} else if joinBigInts(out) != tt.out {
+			t.Errorf("%d. decodeRelativeOID(%v) = %v wanted %v.", i, tt.in, joinBigInts(out), tt.out)
 		}
 	}
 }
@@ -0,0 +1,591 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package der2ascii
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/hmac"
+	"crypto/rc4"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"hash"
+	"strings"
+
+	"github.com/google/der-ascii/internal"
+)
+
+var (
+	tagInteger     = internal.Tag{Class: internal.ClassUniversal, Number: 2}
+	tagOctetString = internal.Tag{Class: internal.ClassUniversal, Number: 4}
+	tagOID         = internal.Tag{Class: internal.ClassUniversal, Number: 6}
+	tagSequence    = internal.Tag{Class: internal.ClassUniversal, Number: 16, Constructed: true}
+)
+
+// maxPBEIterations is the largest iteration count der2ascii will run. The
+// count comes from the input, and an input may contain several encrypted
+// values, so this bounds the time spent on each.
+const maxPBEIterations = 1 << 20
+
+// An iterationLimitError is returned for iteration counts above
+// maxPBEIterations.
+type iterationLimitError struct {
+	iterations int64
+}
+
+func (e iterationLimitError) Error() string {
+	return fmt.Sprintf("iteration count %d exceeds the limit of %d", e.iterations, maxPBEIterations)
+}
+
+// berElement is a parsed BER element. Unlike element, the contents of
+// constructed elements, including indefinite-length ones, are parsed.
+type berElement struct {
+	tag internal.Tag
+	// body is the contents of a primitive element.
+	body []byte
+	// children are the elements of a constructed element.
+	children []berElement
+}
+
+// parseBERElement parses a BER element from in, nested at most depth levels.
+func parseBERElement(in []byte, depth int) (berElement, []byte, bool) {
+	elem, rest, ok := parseElement(in)
+	if !ok || depth <= 0 {
+		return berElement{}, nil, false
+	}
+	ret := berElement{tag: elem.tag}
+	if !elem.tag.Constructed {
+		ret.body = elem.body
+		return ret, rest, true
+	}
+	contents := elem.body
+	if elem.indefinite {
+		contents = rest
+	}
+	for {
+		if elem.indefinite && startsWithEOC(contents) {
+			return ret, contents[2:], true
+		}
+		if len(contents) == 0 {
+			if elem.indefinite {
+				return berElement{}, nil, false
+			}
+			return ret, rest, true
+		}
+		var child berElement
+		child, contents, ok = parseBERElement(contents, depth-1)
+		if !ok {
+			return berElement{}, nil, false
+		}
+		ret.children = append(ret.children, child)
+	}
+}
+
+// parseBERChildren parses in, the contents of a definite-length constructed
+// element, as a list of BER elements.
+func parseBERChildren(in []byte) ([]berElement, bool) {
+	var children []berElement
+	for len(in) != 0 {
+		child, rest, ok := parseBERElement(in, maxSchemaDepth)
+		if !ok {
+			return nil, false
+		}
+		children = append(children, child)
+		in = rest
+	}
+	return children, true
+}
+
+// stringContents returns the contents of e, which may be a constructed
+// string.
+func (e berElement) stringContents() []byte {
+	if !e.tag.Constructed {
+		return e.body
+	}
+	var out []byte
+	for _, child := range e.children {
+		out = append(out, child.stringContents()...)
+	}
+	return out
+}
+
+// isOID returns whether e is an OBJECT IDENTIFIER with the given dotted value.
+func (e berElement) isOID(oid string) bool {
+	return e.tag == tagOID && objectIdentifierToString(e.body) == oid
+}
+
+// parseIterations parses e as a PBE iteration count.
+func parseIterations(e berElement) (int, error) {
+	n, ok := decodeInteger(e.body)
+	if e.tag != tagInteger || !ok {
+		return 0, errors.New("invalid iteration count")
+	}
+	if n < 1 {
+		return 0, fmt.Errorf("unsupported iteration count %d", n)
+	}
+	if n > maxPBEIterations {
+		return 0, iterationLimitError{n}
+	}
+	return int(n), nil
+}
+
+// digestsByOID maps digest algorithm OIDs to their hash functions.
+var digestsByOID = map[string]func() hash.Hash{
+	"1.3.14.3.2.26":          sha1.New,
+	"2.16.840.1.101.3.4.2.1": sha256.New,
+	"2.16.840.1.101.3.4.2.2": sha512.New384,
+	"2.16.840.1.101.3.4.2.3": sha512.New,
+	"2.16.840.1.101.3.4.2.4": sha256.New224,
+}
+
+// hmacsByOID maps PBKDF2 PRF OIDs to their hash functions.
+var hmacsByOID = map[string]func() hash.Hash{
+	"1.2.840.113549.2.7":  sha1.New,
+	"1.2.840.113549.2.8":  sha256.New224,
+	"1.2.840.113549.2.9":  sha256.New,
+	"1.2.840.113549.2.10": sha512.New384,
+	"1.2.840.113549.2.11": sha512.New,
+}
+
+// pbeCipher describes a block or stream cipher used in password-based
+// encryption.
+type pbeCipher struct {
+	name   string
+	keyLen int
+	// ivLen is the length of the CBC IV, or zero for a stream cipher.
+	ivLen int
+	// newCipher returns a block cipher for key if ivLen is non-zero, and a
+	// stream cipher otherwise.
+	newCipher func(key []byte) (interface{}, error)
+}
+
+func newAESCipher(key []byte) (interface{}, error) { return aes.NewCipher(key) }
+
+func newDESCipher(key []byte) (interface{}, error) { return des.NewCipher(key) }
+
+func newTripleDESCipher(key []byte) (interface{}, error) {
+	if len(key) == 16 {
+		// Two-key triple DES reuses the first key.
+		key = append(append([]byte{}, key...), key[:8]...)
+	}
+	return des.NewTripleDESCipher(key)
+}
+
+func newRC2Cipher(bits int) func([]byte) (interface{}, error) {
+	return func(key []byte) (interface{}, error) { return internal.NewRC2Cipher(key, bits) }
+}
+
+func newRC4Cipher(key []byte) (interface{}, error) { return rc4.NewCipher(key) }
+
+// pkcs12Ciphers maps PKCS #12 PBE OIDs, from RFC 7292, appendix C, to their
+// ciphers.
+var pkcs12Ciphers = map[string]*pbeCipher{
+	"1.2.840.113549.1.12.1.1": {"pbeWithSHAAnd128BitRC4", 16, 0, newRC4Cipher},
+	"1.2.840.113549.1.12.1.2": {"pbeWithSHAAnd40BitRC4", 5, 0, newRC4Cipher},
+	"1.2.840.113549.1.12.1.3": {"pbeWithSHAAnd3-KeyTripleDES-CBC", 24, 8, newTripleDESCipher},
+	"1.2.840.113549.1.12.1.4": {"pbeWithSHAAnd2-KeyTripleDES-CBC", 16, 8, newTripleDESCipher},
+	"1.2.840.113549.1.12.1.5": {"pbeWithSHAAnd128BitRC2-CBC", 16, 8, newRC2Cipher(128)},
+	"1.2.840.113549.1.12.1.6": {"pbewithSHAAnd40BitRC2-CBC", 5, 8, newRC2Cipher(40)},
+}
+
+// pbes2Ciphers maps PBES2 encryption scheme OIDs to their ciphers.
+var pbes2Ciphers = map[string]*pbeCipher{
+	"1.3.14.3.2.7":            {"desCBC", 8, 8, newDESCipher},
+	"1.2.840.113549.3.7":      {"des-ede3-cbc", 24, 8, newTripleDESCipher},
+	"2.16.840.1.101.3.4.1.2":  {"aes128-CBC", 16, 16, newAESCipher},
+	"2.16.840.1.101.3.4.1.22": {"aes192-CBC", 24, 16, newAESCipher},
+	"2.16.840.1.101.3.4.1.42": {"aes256-CBC", 32, 16, newAESCipher},
+}
+
+const pbes2OID = "1.2.840.113549.1.5.13"
+const pbkdf2OID = "1.2.840.113549.1.5.12"
+
+// decrypt decrypts ciphertext with key and, for block ciphers, iv, and
+// removes the padding.
+func (c *pbeCipher) decrypt(key, iv, ciphertext []byte) ([]byte, error) {
+	impl, err := c.newCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if stream, ok := impl.(cipher.Stream); ok {
+		plaintext := make([]byte, len(ciphertext))
+		stream.XORKeyStream(plaintext, ciphertext)
+		return plaintext, nil
+	}
+	block := impl.(cipher.Block)
+	if len(iv) != block.BlockSize() {
+		return nil, errors.New("invalid IV length")
+	}
+	if len(ciphertext) == 0 || len(ciphertext)%block.BlockSize() != 0 {
+		return nil, errors.New("ciphertext is not a whole number of blocks")
+	}
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+	pad := int(plaintext[len(plaintext)-1])
+	if pad == 0 || pad > block.BlockSize() || !bytes.Equal(plaintext[len(plaintext)-pad:], bytes.Repeat([]byte{byte(pad)}, pad)) {
+		return nil, errors.New("invalid padding; the password may be incorrect")
+	}
+	return plaintext[:len(plaintext)-pad], nil
+}
+
+// A pbeAlgorithm is a parsed password-based encryption AlgorithmIdentifier.
+type pbeAlgorithm struct {
+	name    string
+	decrypt func(password string, ciphertext []byte) ([]byte, error)
+}
+
+// parsePBEAlgorithm parses body, the contents of an AlgorithmIdentifier. If it
+// is not a password-based encryption algorithm, it returns nil and no error.
+// If it is one, but cannot be used, it returns an error.
+func parsePBEAlgorithm(body []byte) (*pbeAlgorithm, error) {
+	children, ok := parseBERChildren(body)
+	if !ok || len(children) != 2 || children[0].tag != tagOID {
+		return nil, nil
+	}
+	oid := objectIdentifierToString(children[0].body)
+	params := children[1]
+	if c, ok := pkcs12Ciphers[oid]; ok {
+		return parsePKCS12PBE(c, params)
+	}
+	if oid == pbes2OID {
+		return parsePBES2(params)
+	}
+	return nil, nil
+}
+
+// parsePKCS12PBE parses params, the pkcs-12PbeParams of a PKCS #12 PBE
+// algorithm using c.
+func parsePKCS12PBE(c *pbeCipher, params berElement) (*pbeAlgorithm, error) {
+	if params.tag != tagSequence || len(params.children) != 2 || params.children[0].tag != tagOctetString {
+		return nil, fmt.Errorf("invalid %s parameters", c.name)
+	}
+	salt := params.children[0].body
+	iterations, err := parseIterations(params.children[1])
+	if err != nil {
+		return nil, err
+	}
+	return &pbeAlgorithm{
+		name: c.name,
+		decrypt: func(password string, ciphertext []byte) ([]byte, error) {
+			encoded := internal.PKCS12Password(password)
+			key, err := internal.PKCS12KDF(sha1.New, internal.PKCS12KeyID, encoded, salt, iterations, c.keyLen)
+			if err != nil {
+				return nil, err
+			}
+			var iv []byte
+			if c.ivLen != 0 {
+				iv, err = internal.PKCS12KDF(sha1.New, internal.PKCS12IVID, encoded, salt, iterations, c.ivLen)
+				if err != nil {
+					return nil, err
+				}
+			}
+			return c.decrypt(key, iv, ciphertext)
+		},
+	}, nil
+}
+
+// parsePBES2 parses params, the PBES2-params of a PBES2 algorithm, as
+// specified in RFC 8018, appendix A.4.
+func parsePBES2(params berElement) (*pbeAlgorithm, error) {
+	if params.tag != tagSequence || len(params.children) != 2 {
+		return nil, errors.New("invalid PBES2 parameters")
+	}
+	kdf, scheme := params.children[0], params.children[1]
+
+	// Parse the encryption scheme.
+	if scheme.tag != tagSequence || len(scheme.children) != 2 || scheme.children[0].tag != tagOID {
+		return nil, errors.New("invalid PBES2 encryption scheme")
+	}
+	schemeOID := objectIdentifierToString(scheme.children[0].body)
+	c, ok := pbes2Ciphers[schemeOID]
+	if !ok {
+		return nil, fmt.Errorf("unsupported PBES2 encryption scheme %s", schemeOID)
+	}
+	if scheme.children[1].tag != tagOctetString {
+		return nil, fmt.Errorf("invalid %s parameters", c.name)
+	}
+	iv := scheme.children[1].body
+
+	// Parse the key derivation function. Only PBKDF2 is supported.
+	if kdf.tag != tagSequence || len(kdf.children) != 2 || kdf.children[0].tag != tagOID {
+		return nil, errors.New("invalid PBES2 key derivation function")
+	}
+	if !kdf.children[0].isOID(pbkdf2OID) {
+		return nil, fmt.Errorf("unsupported PBES2 key derivation function %s", objectIdentifierToString(kdf.children[0].body))
+	}
+	kdfParams := kdf.children[1].children
+	if kdf.children[1].tag != tagSequence || len(kdfParams) < 2 || kdfParams[0].tag != tagOctetString {
+		return nil, errors.New("invalid PBKDF2 parameters")
+	}
+	salt := kdfParams[0].body
+	iterations, err := parseIterations(kdfParams[1])
+	if err != nil {
+		return nil, err
+	}
+	kdfParams = kdfParams[2:]
+	if len(kdfParams) > 0 && kdfParams[0].tag == tagInteger {
+		keyLen, ok := decodeInteger(kdfParams[0].body)
+		if !ok || keyLen != int64(c.keyLen) {
+			return nil, errors.New("invalid PBKDF2 key length")
+		}
+		kdfParams = kdfParams[1:]
+	}
+	prf := sha1.New
+	prfName := "hmacWithSHA1"
+	if len(kdfParams) > 0 {
+		alg := kdfParams[0]
+		if alg.tag != tagSequence || len(alg.children) == 0 || alg.children[0].tag != tagOID {
+			return nil, errors.New("invalid PBKDF2 PRF")
+		}
+		prfOID := objectIdentifierToString(alg.children[0].body)
+		if prf, ok = hmacsByOID[prfOID]; !ok {
+			return nil, fmt.Errorf("unsupported PBKDF2 PRF %s", prfOID)
+		}
+		prfName, _ = objectIdentifierToName(alg.children[0].body)
+		kdfParams = kdfParams[1:]
+	}
+	if len(kdfParams) != 0 {
+		return nil, errors.New("invalid PBKDF2 parameters")
+	}
+
+	return &pbeAlgorithm{
+		name: fmt.Sprintf("PBES2 (PBKDF2 with %s, %s)", prfName, c.name),
+		decrypt: func(password string, ciphertext []byte) ([]byte, error) {
+			key := internal.PBKDF2(prf, []byte(password), salt, iterations, c.keyLen)
+			return c.decrypt(key, iv, ciphertext)
+		},
+	}, nil
+}
+
+// verifyPKCS12MAC checks the MAC of in, a PKCS #12 PFX, against password. It
+// returns a comment describing the result, or the empty string if in is not a
+// PFX with a MAC.
+func verifyPKCS12MAC(in []byte, password string) string {
+	pfx, _, ok := parseBERElement(in, maxSchemaDepth)
+	if !ok || pfx.tag != tagSequence || len(pfx.children) != 3 {
+		return ""
+	}
+
+	// The MAC covers the contents of authSafe, which must be a data
+	// ContentInfo.
+	authSafe := pfx.children[1]
+	if authSafe.tag != tagSequence || len(authSafe.children) != 2 || !authSafe.children[0].isOID("1.2.840.113549.1.7.1") {
+		return ""
+	}
+	explicit := authSafe.children[1]
+	if explicit.tag != (internal.Tag{Class: internal.ClassContextSpecific, Number: 0, Constructed: true}) || len(explicit.children) != 1 || explicit.children[0].tag.Number != tagOctetString.Number {
+		return ""
+	}
+	content := explicit.children[0].stringContents()
+
+	macData := pfx.children[2]
+	if macData.tag != tagSequence || len(macData.children) < 2 || len(macData.children) > 3 {
+		return ""
+	}
+	digestInfo := macData.children[0]
+	if digestInfo.tag != tagSequence || len(digestInfo.children) != 2 || digestInfo.children[1].tag != tagOctetString {
+		return ""
+	}
+	digestAlg := digestInfo.children[0]
+	if digestAlg.tag != tagSequence || len(digestAlg.children) == 0 || digestAlg.children[0].tag != tagOID {
+		return ""
+	}
+	salt := macData.children[1]
+	if salt.tag != tagOctetString {
+		return ""
+	}
+	iterations := 1
+	if len(macData.children) == 3 {
+		var err error
+		if iterations, err = parseIterations(macData.children[2]); err != nil {
+			if _, ok := err.(iterationLimitError); ok {
+				return fmt.Sprintf("Skipped verifying PKCS #12 MAC: %s", err)
+			}
+			return fmt.Sprintf("Could not verify PKCS #12 MAC: %s", err)
+		}
+	}
+	digestOID := objectIdentifierToString(digestAlg.children[0].body)
+	h, ok := digestsByOID[digestOID]
+	if !ok {
+		return fmt.Sprintf("Could not verify PKCS #12 MAC: unsupported digest %s", digestOID)
+	}
+
+	key, err := internal.PKCS12KDF(h, internal.PKCS12MACID, internal.PKCS12Password(password), salt.body, iterations, h().Size())
+	if err != nil {
+		return fmt.Sprintf("Could not verify PKCS #12 MAC: %s", err)
+	}
+	mac := hmac.New(h, key)
+	mac.Write(content)
+	if !hmac.Equal(mac.Sum(nil), digestInfo.children[1].body) {
+		return "PKCS #12 MAC does not match the password"
+	}
+	return "PKCS #12 MAC verified"
+}
+
+// decryptStructure is a structure which decrypts password-encrypted contents,
+// such as a PKCS #8 EncryptedPrivateKeyInfo or a PKCS #7 EncryptedContentInfo.
+// When it sees a password-based encryption AlgorithmIdentifier followed by an
+// OCTET STRING or [0] sibling, it adds the disassembled plaintext as comments.
+type decryptStructure struct {
+	password string
+	// plaintext, if not nil, returns the structure to annotate decrypted
+	// contents with.
+	plaintext func(plaintext []byte) structure
+	// vocabulary, if not nil, contains the tag aliases used to disassemble
+	// plaintexts.
+	vocabulary *internal.Vocabulary
+	// mac, if not empty, is added as a comment before the third element.
+	mac string
+	// childMAC, if not empty, is passed to the first element's body as mac.
+	childMAC string
+	pos      int
+	// alg and algErr are the result of parsing the previous element as a
+	// PBE algorithm.
+	alg    *pbeAlgorithm
+	algErr error
+}
+
+// newDecryptStructure returns a structure which decrypts contents of in with
+// password and disassembles them using the tag aliases in v. If in is a PKCS
+// #12 PFX, the structure also reports whether its MAC matches.
+func newDecryptStructure(password string, in []byte, plaintext func([]byte) structure, v *internal.Vocabulary) structure {
+	return &decryptStructure{password: password, plaintext: plaintext, vocabulary: v, childMAC: verifyPKCS12MAC(in, password)}
+}
+
+func (d *decryptStructure) next(elem element) ([]string, structure) {
+	d.pos++
+	var comments []string
+	if d.pos == 3 && d.mac != "" {
+		comments = append(comments, d.mac)
+	}
+	alg, algErr := d.alg, d.algErr
+	d.alg, d.algErr = nil, nil
+
+	if (alg != nil || algErr != nil) && (elem.tag.Number == tagOctetString.Number && elem.tag.Class == internal.ClassUniversal || elem.tag.Number == 0 && elem.tag.Class == internal.ClassContextSpecific) {
+		if !elem.tag.Constructed {
+			return append(comments, d.decrypt(alg, algErr, elem.body)...), nil
+		}
+		return comments, &ciphertextStructure{d: d, alg: alg, algErr: algErr}
+	}
+
+	if elem.tag == tagSequence && !elem.indefinite {
+		d.alg, d.algErr = parsePBEAlgorithm(elem.body)
+	}
+	body := &decryptStructure{password: d.password, plaintext: d.plaintext, vocabulary: d.vocabulary}
+	if d.pos == 1 {
+		body.mac = d.childMAC
+	}
+	return comments, body
+}
+
+func (d *decryptStructure) end() []string { return nil }
+
+// decrypt returns comments describing the result of decrypting ciphertext
+// with alg.
+func (d *decryptStructure) decrypt(alg *pbeAlgorithm, algErr error, ciphertext []byte) []string {
+	if _, ok := algErr.(iterationLimitError); ok {
+		return []string{fmt.Sprintf("Skipped decrypting: %s", algErr)}
+	}
+	if algErr != nil {
+		return []string{fmt.Sprintf("Could not decrypt: %s", algErr)}
+	}
+	plaintext, err := alg.decrypt(d.password, ciphertext)
+	if err != nil {
+		return []string{fmt.Sprintf("Could not decrypt with %s: %s", alg.name, err)}
+	}
+	var s structure = &decryptStructure{password: d.password, plaintext: d.plaintext, vocabulary: d.vocabulary}
+	if d.plaintext != nil {
+		s = combineStructures(d.plaintext(plaintext), s)
+	}
+	comments := []string{fmt.Sprintf("Decrypted with %s:", alg.name)}
+	var out bytes.Buffer
+	writeASCII(&out, plaintext, 0, s, defaultLimits, d.vocabulary)
+	for _, line := range strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n") {
+		comments = append(comments, "  "+line)
+	}
+	return comments
+}
+
+// ciphertextStructure collects the segments of a constructed ciphertext and
+// decrypts them at the end.
+type ciphertextStructure struct {
+	d          *decryptStructure
+	alg        *pbeAlgorithm
+	algErr     error
+	ciphertext []byte
+	invalid    bool
+}
+
+func (c *ciphertextStructure) next(elem element) ([]string, structure) {
+	if elem.tag.Constructed {
+		c.invalid = true
+	} else {
+		c.ciphertext = append(c.ciphertext, elem.body...)
+	}
+	return nil, nil
+}
+
+func (c *ciphertextStructure) end() []string {
+	if c.invalid {
+		return []string{"Could not decrypt: nested constructed ciphertext is not supported"}
+	}
+	return c.d.decrypt(c.alg, c.algErr, c.ciphertext)
+}
+
+// multiStructure is a structure which combines the comments of several
+// structures.
+type multiStructure []structure
+
+// combineStructures returns a structure which combines the comments of each
+// non-nil structure in structures, or nil if there are none.
+func combineStructures(structures ...structure) structure {
+	var m multiStructure
+	for _, s := range structures {
+		if s != nil {
+			m = append(m, s)
+		}
+	}
+	switch len(m) {
+	case 0:
+		return nil
+	case 1:
+		return m[0]
+	}
+	return m
+}
+
+func (m multiStructure) next(elem element) ([]string, structure) {
+	var comments []string
+	bodies := make([]structure, 0, len(m))
+	for _, s := range m {
+		c, body := s.next(elem)
+		comments = append(comments, c...)
+		bodies = append(bodies, body)
+	}
+	return comments, combineStructures(bodies...)
+}
+
+func (m multiStructure) end() []string {
+	var comments []string
+	for _, s := range m {
+		comments = append(comments, s.end()...)
+	}
+	return comments
+}
@@ -0,0 +1,127 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package der2ascii
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// The following are an Ed25519 PrivateKeyInfo encrypted with the password
+// "secret" by OpenSSL, and a PFX with an empty AuthenticatedSafe and a MAC
+// keyed with the same password.
+const (
+	pkcs8PBES2Hex     = "30819a305606092a864886f70d01050d3049302806092a864886f70d01050c301b040818ffc09dae397d9b020110300c06082a864886f70d02090500301d06096086480165030401020410db071962365c784989a6801d21f196300440ed2909505a8dc9763cb26e26631de9f8419965d5ea45c566b1f467e70054579d633af4eeab07cde0f29d5133bfc61b689208af0c4b0ca3ab812389ef9b577006"
+	pkcs8TripleDESHex = "3057301b060a2a864886f70d010c0103300d0408fcae42b7cebf0e5902011004383425a234a72afbe33d9d8991c980f10664778855b1addb4eb5918afcd966caac7fd9f82ffac2076de698f3b1d903843f99014ea6847ffd7c"
+	pkcs8RC2Hex       = "3057301b060a2a864886f70d010c0106300d0408d5120ad71c660a040201100438f5504b8c50e858dd55cf039a5343167dcc42ea28807197576794c8825a6c06dcac9353949c2852b6c8b0f6e16a579311601e863b11c513c6"
+	pfxHex            = "3048020103301106092a864886f70d010701a0040402300030303021300906052b0e03021a05000414a41b7325649f7b2491c4f1f547d3b2fe79c404fb04080102030405060708020110"
+)
+
+var decryptTests = []struct {
+	in       string
+	password string
+	want     []string
+}{
+	{
+		pkcs8PBES2Hex,
+		"secret",
+		[]string{
+			"  # Decrypted with PBES2 (PBKDF2 with hmacWithSHA256, aes128-CBC):\n  #   SEQUENCE {\n  #     INTEGER { 0 }\n",
+			"  #     OCTET_STRING {\n  #       OCTET_STRING { `dcf13fbaf6fd4eb4889659c6b3214bc2cc196e0e9f4a64299bf08723a51debb0` }\n",
+			"  #   }\n  OCTET_STRING { `ed2909",
+		},
+	},
+	{
+		pkcs8PBES2Hex,
+		"wrong",
+		[]string{"  # Could not decrypt with PBES2 (PBKDF2 with hmacWithSHA256, aes128-CBC): invalid padding; the password may be incorrect\n"},
+	},
+	{
+		pkcs8TripleDESHex,
+		"secret",
+		[]string{"  # Decrypted with pbeWithSHAAnd3-KeyTripleDES-CBC:\n  #   SEQUENCE {\n  #     INTEGER { 0 }\n"},
+	},
+	{
+		pkcs8RC2Hex,
+		"secret",
+		[]string{"  # Decrypted with pbewithSHAAnd40BitRC2-CBC:\n  #   SEQUENCE {\n  #     INTEGER { 0 }\n"},
+	},
+	{
+		pfxHex,
+		"secret",
+		[]string{"  # PKCS #12 MAC verified\n  SEQUENCE {\n"},
+	},
+	{
+		// The iteration count is above the limit.
+		"3059301d060a2a864886f70d010c0103300f0408fcae42b7cebf0e590203200000" + pkcs8TripleDESHex[len(pkcs8TripleDESHex)-116:],
+		"secret",
+		[]string{"  # Skipped decrypting: iteration count 2097152 exceeds the limit of 1048576\n"},
+	},
+	{
+		"304a020103301106092a864886f70d010701a0040402300030323021300906052b0e03021a05000414a41b7325649f7b2491c4f1f547d3b2fe79c404fb040801020304050607080203200000",
+		"secret",
+		[]string{"  # Skipped verifying PKCS #12 MAC: iteration count 2097152 exceeds the limit of 1048576\n"},
+	},
+	{
+		pfxHex,
+		"wrong",
+		[]string{"  # PKCS #12 MAC does not match the password\n"},
+	},
+}
+
+func TestDecrypt(t *testing.T) {
+	for i, tt := range decryptTests {
+		in, err := hex.DecodeString(tt.in)
+		if err != nil {
+			t.Fatalf("%d. invalid hex: %s", i, err)
+		}
+		out := derToASCIIWithStructure(in, newDecryptStructure(tt.password, in, nil, nil))
+		for _, want := range tt.want {
+			if !strings.Contains(out, want) {
+				t.Errorf("%d. output did not contain %q:\n%s", i, want, out)
+			}
+		}
+		// Decrypting only adds comments.
+		if plain := removeComments(derToASCII(in)); removeComments(out) != plain {
+			t.Errorf("%d. output differs from %q beyond comments:\n%s", i, plain, out)
+		}
+	}
+}
+
+// removeComments removes comment lines from s.
+func removeComments(s string) string {
+	var out []string
+	for _, line := range strings.SplitAfter(s, "\n") {
+		if !strings.HasPrefix(strings.TrimLeft(line, " "), "#") {
+			out = append(out, line)
+		}
+	}
+	return strings.Join(out, "")
+}
+
+func TestParsePBEAlgorithmErrors(t *testing.T) {
+	for i, tt := range []string{
+		// PBES2 with scrypt.
+		"06092a864886f70d01050d301e300d06092b06010401da472f0b3000300d06096086480165030401020400",
+		// pbeWithSHAAnd3-KeyTripleDES-CBC with an excessive iteration count.
+		"060a2a864886f70d010c0103301004080102030405060708020440000000",
+	} {
+		body, _ := hex.DecodeString(tt)
+		if alg, err := parsePBEAlgorithm(body); alg != nil || err == nil {
+			t.Errorf("%d. parsePBEAlgorithm unexpectedly succeeded", i)
+		}
+	}
+}
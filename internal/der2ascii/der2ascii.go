@@ -0,0 +1,240 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package der2ascii disassembles DER and BER into DER ASCII and into JSON
+// element trees. It implements the der2ascii command and the public der2ascii
+// package.
+package der2ascii
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/der-ascii/internal"
+	"github.com/google/der-ascii/internal/ascii2der"
+)
+
+// DefaultMaxDepth is the nesting depth disassembled if no other limit is
+// specified. It keeps recursion within a reasonable stack size.
+const DefaultMaxDepth = 1024
+
+// Options configures how an input is disassembled.
+type Options struct {
+	// MaxDepth is the maximum number of nested elements to disassemble.
+	// The contents of elements nested any deeper are written as hex. Zero
+	// means no limit.
+	MaxDepth int
+	// MaxElements is the maximum number of elements to disassemble. The
+	// input after that is written as hex. Zero means no limit.
+	MaxElements int
+	// Vocabulary, if not nil, contains the aliases written in place of
+	// non-universal tag numbers. The output assumes the pragmas returned by
+	// VocabularyHeader precede it.
+	Vocabulary *internal.Vocabulary
+	// Schema, if not nil, is used to annotate the output with field names.
+	Schema *Schema
+	// SchemaType is the name of the type of the input in Schema. If empty,
+	// it is detected by the profile, or is the first type in the module.
+	SchemaType string
+	// Decrypt is true if password-encrypted contents are decrypted with
+	// Password and their plaintext added as comments.
+	Decrypt  bool
+	Password string
+}
+
+func (o *Options) limits() limits {
+	return limits{maxDepth: o.MaxDepth, maxElements: o.MaxElements}
+}
+
+// structure returns the structure which annotates the disassembly of in. If the
+// input is not in memory, in is a prefix of it.
+func (o *Options) structure(in []byte) (structure, error) {
+	var s structure = &namedBitsStructure{}
+	if o.Schema != nil {
+		var err error
+		s, err = o.Schema.structure(o.SchemaType, in, o.Vocabulary)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if o.Decrypt {
+		var plaintext func([]byte) structure
+		if o.Schema != nil && o.Schema.prof != nil {
+			// Annotate decrypted contents with the profile too.
+			plaintext = func(in []byte) structure {
+				st, _ := o.Schema.structure("", in, o.Vocabulary)
+				return st
+			}
+		}
+		s = combineStructures(s, newDecryptStructure(o.Password, in, plaintext, o.Vocabulary))
+	}
+	return s, nil
+}
+
+// A Schema describes the inputs to annotate. It is either an ASN.1 module or a
+// built-in profile.
+type Schema struct {
+	sch *schema
+	// prof, if not nil, is the profile the schema was loaded from.
+	prof *profile
+}
+
+// ParseSchema parses text, an ASN.1 module, into a Schema.
+func ParseSchema(text string) (*Schema, error) {
+	sch, err := parseSchema(text)
+	if err != nil {
+		return nil, err
+	}
+	return &Schema{sch: sch}, nil
+}
+
+// ProfileNames returns the names of the built-in profiles, sorted.
+func ProfileNames() []string {
+	return profileNames()
+}
+
+// LoadProfile returns the Schema of the built-in profile with the specified
+// name, which must be one of ProfileNames.
+func LoadProfile(name string) (*Schema, error) {
+	prof, ok := profiles[name]
+	if !ok {
+		return nil, errors.New("unknown profile")
+	}
+	sch, err := prof.schema()
+	if err != nil {
+		return nil, err
+	}
+	return &Schema{sch: sch, prof: prof}, nil
+}
+
+// HasType returns whether s defines a type with the specified name.
+func (s *Schema) HasType(name string) bool {
+	return s.sch.lookup(name) != nil
+}
+
+// Vocabulary returns the name of the built-in tag vocabulary which names the
+// schema's non-universal tags, or the empty string if there is none.
+func (s *Schema) Vocabulary() string {
+	if s.prof == nil {
+		return ""
+	}
+	return s.prof.vocabulary
+}
+
+// structure returns the structure which annotates in as the type with the
+// specified name, or the type detected from in if name is empty. Comments name
+// tags with the aliases in v.
+func (s *Schema) structure(name string, in []byte, v *internal.Vocabulary) (structure, error) {
+	if name == "" {
+		if s.prof != nil {
+			name = s.sch.detectRoot(s.prof.roots, in)
+		} else {
+			name = s.sch.order[0]
+		}
+	}
+	return newSchemaStructure(s.sch.withVocabulary(v), name, s.prof != nil && s.prof.repeated)
+}
+
+// DERToASCII disassembles in as specified by opts.
+func DERToASCII(in []byte, opts *Options) (string, error) {
+	s, err := opts.structure(in)
+	if err != nil {
+		return "", err
+	}
+	var out bytes.Buffer
+	writeASCII(&out, in, 0, s, opts.limits(), opts.Vocabulary)
+	return out.String(), nil
+}
+
+// WriteASCII disassembles the first size bytes of r as specified by opts and
+// writes the result to w. Only elements of up to 1 MiB are read into memory,
+// so the input may be arbitrarily large. The schema type is detected, and a
+// PKCS #12 MAC verified, from at most the first 1 MiB of input.
+func WriteASCII(w io.Writer, r io.ReaderAt, size int64, opts *Options) error {
+	var prefix []byte
+	if opts.Schema != nil || opts.Decrypt {
+		prefix = make([]byte, min64(size, maxBufferedElement))
+		if _, err := r.ReadAt(prefix, 0); err != nil && err != io.EOF {
+			return err
+		}
+	}
+	s, err := opts.structure(prefix)
+	if err != nil {
+		return err
+	}
+	return derToASCIIStream(w, r, size, s, opts.limits(), opts.Vocabulary)
+}
+
+// PEMBlockToASCII disassembles block, as specified by opts, as a DER ASCII pem
+// block, which assembles to the PEM encoding of block.
+func PEMBlockToASCII(block *pem.Block, opts *Options) (string, error) {
+	s, err := opts.structure(block.Bytes)
+	if err != nil {
+		return "", err
+	}
+	return pemBlockToASCII(block, s, opts.limits(), opts.Vocabulary), nil
+}
+
+// DERToJSON returns the JSON element tree of in, disassembled subject to the
+// limits in opts, followed by a newline. If pemType is not empty, it is
+// recorded as the type of the PEM block in was decoded from.
+func DERToJSON(in []byte, pemType string, opts *Options) ([]byte, error) {
+	doc := jsonDocument{PEMType: pemType, Elements: derToJSONWithLimits(in, opts.limits())}
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+// VocabularyHeader returns the pragmas which select v's tag aliases, followed
+// by a blank line, or the empty string if v is empty. They must precede output
+// which uses v.
+func VocabularyHeader(v *internal.Vocabulary) string {
+	return vocabularyHeader(v)
+}
+
+// VerifyASCII checks that text, the output of DERToASCII for in, assembles back
+// to in. The output of VocabularyHeader for v is assumed to precede text.
+func VerifyASCII(in []byte, text string, v *internal.Vocabulary) error {
+	return verifyASCII(in, text, v)
+}
+
+// VerifyPEMBlock checks that text, the output of PEMBlockToASCII for block,
+// assembles to the PEM encoding of block. The output of VocabularyHeader for v
+// is assumed to precede text.
+func VerifyPEMBlock(block *pem.Block, text string, v *internal.Vocabulary) error {
+	return verifyPEMBlock(block, text, v)
+}
+
+// VerifyJSON checks that doc, the output of DERToJSON for in, assembles back to
+// in.
+func VerifyJSON(in, doc []byte) error {
+	out, isPEM, err := ascii2der.AssembleJSON(doc)
+	if err != nil {
+		return fmt.Errorf("output does not assemble: %s", err)
+	}
+	if isPEM {
+		block, _ := pem.Decode(out)
+		if block == nil {
+			return errors.New("output does not assemble to PEM")
+		}
+		out = block.Bytes
+	}
+	return compareAssembled(in, out)
+}
@@ -0,0 +1,558 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package der2ascii
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/google/der-ascii/internal"
+)
+
+// maxBufferedElement is the size of the largest element derToASCIIStream reads
+// into memory. Larger elements are disassembled incrementally, so memory usage
+// does not depend on the size of the input.
+const maxBufferedElement = 1 << 20
+
+// streamChunkSize is the size of reads from the input, and of the pieces in
+// which large values are written.
+const streamChunkSize = 32 << 10
+
+// headerPeekSize is the number of bytes read to parse an element's tag and
+// length. Longer headers, which are only possible with non-minimal encodings,
+// are read with a second, larger read.
+const headerPeekSize = 32
+
+// A disassembler disassembles an input which is read from an io.ReaderAt.
+// Elements of up to limit bytes are read into memory whole. Larger ones are
+// disassembled incrementally, reading only their headers and writing their
+// values in pieces.
+type disassembler struct {
+	r io.ReaderAt
+	w *bufio.Writer
+	// limit is the size of the largest element which is read into memory
+	// whole.
+	limit int64
+	// lim and elements are the limits on the output and the number of
+	// elements disassembled so far.
+	lim      limits
+	elements int
+	// vocabulary, if not nil, contains the aliases written in place of
+	// non-universal tag numbers.
+	vocabulary *internal.Vocabulary
+	// window and windowOff are the most recent element read into memory
+	// whole. Reads within it return slices of it, so elements nested in it
+	// are not read again.
+	window    []byte
+	windowOff int64
+	// cache and cacheOff are the most recent chunk read from r.
+	cache    []byte
+	cacheOff int64
+	// eocs and smallEOCs cache the results of findEOC for elements larger
+	// than limit and for smaller ones, respectively. Otherwise, nested
+	// indefinite-length elements would be scanned again at each level of
+	// nesting. A value of zero means the element is not closed.
+	eocs      map[int64]int64
+	smallEOCs map[int64]int64
+	// err is the first error reading or writing.
+	err error
+}
+
+func newDisassembler(w io.Writer, r io.ReaderAt, limit int64, lim limits) *disassembler {
+	return &disassembler{r: r, w: bufio.NewWriterSize(w, streamChunkSize), limit: limit, lim: lim}
+}
+
+// derToASCIIStream disassembles the first size bytes of r, subject to lim, and
+// writes the result to w, using the tag aliases in v. If s is not nil, the
+// output is annotated with comments from s. Only elements of up to maxBufferedElement bytes are read
+// into memory. Structures see larger elements with a nil body, so they are not
+// described.
+func derToASCIIStream(w io.Writer, r io.ReaderAt, size int64, s structure, lim limits, v *internal.Vocabulary) error {
+	d := newDisassembler(w, r, maxBufferedElement, lim)
+	d.vocabulary = v
+	d.disassemble(0, size, 0, false, s, "")
+	if d.err != nil {
+		return d.err
+	}
+	return d.w.Flush()
+}
+
+// read returns n bytes of input at off. The caller must ensure they are
+// available. Reads within the window return a slice of it, and other small
+// reads are served from a cache.
+func (d *disassembler) read(off, n int64) []byte {
+	if off >= d.windowOff && off+n <= d.windowOff+int64(len(d.window)) {
+		i := off - d.windowOff
+		return d.window[i : i+n : i+n]
+	}
+	out := make([]byte, n)
+	if d.err != nil {
+		return out
+	}
+	if n > streamChunkSize {
+		if _, err := d.r.ReadAt(out, off); err != nil && err != io.EOF {
+			d.err = err
+		}
+		return out
+	}
+	if off < d.cacheOff || off+n > d.cacheOff+int64(len(d.cache)) {
+		if d.cache == nil {
+			d.cache = make([]byte, streamChunkSize)
+		}
+		d.cache = d.cache[:cap(d.cache)]
+		m, err := d.r.ReadAt(d.cache, off)
+		if err != nil && err != io.EOF {
+			d.err = err
+			return out
+		}
+		d.cache, d.cacheOff = d.cache[:m], off
+		if off+n > d.cacheOff+int64(m) {
+			d.err = io.ErrUnexpectedEOF
+			return out
+		}
+	}
+	copy(out, d.cache[off-d.cacheOff:])
+	return out
+}
+
+// buffer reads the element of the given size at off into the window, if it is
+// no larger than limit and not already in memory.
+func (d *disassembler) buffer(off, size int64) {
+	if size > d.limit || off >= d.windowOff && off+size <= d.windowOff+int64(len(d.window)) {
+		return
+	}
+	// read allocates a new slice, so bodies passed to structures from the
+	// previous window remain valid.
+	d.window, d.windowOff = d.read(off, size), off
+}
+
+func (d *disassembler) writeString(s string) {
+	if d.err == nil {
+		_, d.err = d.w.WriteString(s)
+	}
+}
+
+func (d *disassembler) addLine(indent int, value string) {
+	d.writeString(strings.Repeat("  ", indent))
+	d.writeString(value)
+	d.writeString("\n")
+}
+
+func (d *disassembler) addComments(indent int, comments []string) {
+	for _, comment := range comments {
+		d.addLine(indent, "# "+comment)
+	}
+}
+
+// isEOC returns whether an end-of-contents marker is at off.
+func (d *disassembler) isEOC(off, end int64) bool {
+	return end-off >= 2 && startsWithEOC(d.read(off, 2))
+}
+
+// parseHeader parses the tag and length of the element at off. It returns the
+// element, without a body, and the lengths of the header and contents. It
+// returns false if the header could not be parsed or the contents do not fit
+// before end.
+func (d *disassembler) parseHeader(off, end int64) (elem element, headerLen, length int64, ok bool) {
+	for _, peek := range []int64{headerPeekSize, maxBufferedElement} {
+		if peek > end-off {
+			peek = end - off
+		}
+		buf := d.read(off, peek)
+		var rest []byte
+		elem, length, rest, ok = parseTagAndLength(buf)
+		if ok {
+			headerLen = int64(len(buf) - len(rest))
+			ok = length <= end-off-headerLen
+			return
+		}
+		if peek == end-off {
+			break
+		}
+	}
+	return
+}
+
+// isMadeOfElements behaves like the function of the same name on the input
+// from start to end, but only reads the headers of each element.
+func (d *disassembler) isMadeOfElements(start, end int64) bool {
+	var indefiniteCount int
+	for off := start; off < end; {
+		if d.isEOC(off, end) {
+			if indefiniteCount == 0 {
+				return false
+			}
+			off += 2
+			indefiniteCount--
+			continue
+		}
+		elem, headerLen, length, ok := d.parseHeader(off, end)
+		if !ok || d.err != nil {
+			return false
+		}
+		off += headerLen + length
+		if elem.indefinite {
+			indefiniteCount++
+		}
+	}
+	return indefiniteCount == 0
+}
+
+// findEOC returns the offset just past the end-of-contents marker which closes
+// an indefinite-length element whose contents start at start. It returns false
+// if the element is not properly closed before end, in which case its
+// contents extend to end.
+func (d *disassembler) findEOC(start, end int64) (int64, bool) {
+	for _, m := range []map[int64]int64{d.eocs, d.smallEOCs} {
+		if eocEnd, ok := m[start]; ok {
+			// Each element is only looked up once.
+			delete(m, start)
+			return eocEnd, eocEnd != 0
+		}
+	}
+	// Any elements found by previous scans which are still cached are not
+	// disassembled, so discard them.
+	d.smallEOCs = nil
+	// open contains the contents offsets of the indefinite-length elements
+	// being scanned, innermost last. The scan is iterative, so deeply
+	// nested inputs do not exhaust the stack.
+	open := []int64{start}
+	for off := start; off < end && d.err == nil; {
+		if d.isEOC(off, end) {
+			off += 2
+			if len(open) == 1 {
+				return off, true
+			}
+			d.saveEOC(start, open[len(open)-1], off, off)
+			open = open[:len(open)-1]
+			continue
+		}
+		elem, headerLen, length, ok := d.parseHeader(off, end)
+		if !ok {
+			break
+		}
+		if !elem.indefinite {
+			off += headerLen + length
+			continue
+		}
+		contents := off + headerLen
+		if eocEnd, ok := d.eocs[contents]; ok {
+			if eocEnd == 0 {
+				break
+			}
+			off = eocEnd
+			continue
+		}
+		open = append(open, contents)
+		off = contents
+	}
+	for _, contents := range open[1:] {
+		d.saveEOC(start, contents, end, 0)
+	}
+	return 0, false
+}
+
+// saveEOC caches eocEnd as the result of findEOC for the element whose
+// contents start at start and extend to end, found by scanning from scanStart.
+// Elements no larger than limit are only cached while the scan is, so the
+// cache of them is bounded by limit.
+func (d *disassembler) saveEOC(scanStart, start, end, eocEnd int64) {
+	if d.err != nil {
+		return
+	}
+	if end-start > d.limit {
+		if d.eocs == nil {
+			d.eocs = make(map[int64]int64)
+		}
+		d.eocs[start] = eocEnd
+	} else if end-scanStart <= d.limit {
+		if d.smallEOCs == nil {
+			d.smallEOCs = make(map[int64]int64)
+		}
+		d.smallEOCs[start] = eocEnd
+	}
+}
+
+// isStreamedPrimitive returns whether a primitive element of the given length,
+// in the given context, may be disassembled incrementally by writePrimitive.
+// Types with a body renderer are otherwise read into memory, as they are
+// rendered as a whole and are not expected to be large.
+func isStreamedPrimitive(elem element, length int64, context string) bool {
+	if hasContextRenderer(elem.tag, context) {
+		return false
+	}
+	name, _, _ := elem.tag.GetAlias()
+	switch name {
+	case "INTEGER":
+		// Short INTEGERs may be written in decimal.
+		return length > 8
+	case "BIT_STRING":
+		// Short BIT STRINGs may be written as b`` literals.
+		return length > 5
+	}
+	// For instance, text strings may be written as UTF-8 literals and
+	// typed strings are validated.
+	r, ok := findRenderer(elem.tag, context)
+	return !ok || r.Render == nil
+}
+
+// disassemble disassembles the input from off to end, in the given context.
+// The indent is also the depth of the elements. If stopAtEOC is true, it will
+// stop before an end-of-contents marker. If s is not nil, the output is
+// annotated with comments from s. It returns the offset of the first
+// unprocessed byte.
+func (d *disassembler) disassemble(off, end int64, indent int, stopAtEOC bool, s structure, context string) int64 {
+	for off < end && d.err == nil {
+		if stopAtEOC && d.isEOC(off, end) {
+			// The caller will consume the EOC.
+			if s != nil {
+				d.addComments(indent, s.end())
+			}
+			return off
+		}
+
+		if d.lim.maxElements > 0 && d.elements >= d.lim.maxElements {
+			d.addLine(indent, "# "+d.lim.elementsNote())
+			d.writeValueLine(indent, "", off, end, true, "")
+			if s != nil {
+				d.addComments(indent, s.end())
+			}
+			return end
+		}
+
+		elem, headerLen, length, ok := d.parseHeader(off, end)
+		if !ok {
+			// Nothing more to encode. Write the rest as bytes.
+			d.writeValueLine(indent, "", off, end, false, "")
+			if s != nil {
+				d.addComments(indent, s.end())
+			}
+			return end
+		}
+		d.elements++
+
+		contents := off + headerLen
+		size := headerLen + length
+		eocEnd, closed := int64(0), false
+		if elem.indefinite {
+			eocEnd, closed = d.findEOC(contents, end)
+			if closed {
+				size = eocEnd - off
+			} else {
+				size = end - off
+			}
+		}
+		d.buffer(off, size)
+		// Structures see the bodies of elements which fit in memory, and
+		// of primitive elements of up to maxBufferedElement bytes, which
+		// may be described. The bodies of rendered types are always
+		// read, as they are rendered as a whole.
+		if !elem.indefinite && (length <= d.limit || !elem.tag.Constructed && (length <= maxBufferedElement || !isStreamedPrimitive(elem, length, context))) {
+			elem.body = d.read(contents, length)
+		}
+
+		var body structure
+		if s != nil {
+			var comments []string
+			comments, body = s.next(elem)
+			d.addComments(indent, comments)
+		}
+		atLimit := d.lim.depthReached(indent)
+		switch {
+		case elem.indefinite && closed:
+			// The indefinite-length element is properly closed, so
+			// write curly braces with an indefinite modifier.
+			d.addLine(indent, fmt.Sprintf("%s indefinite {", tagToStringWithVocabulary(elem.tag, d.vocabulary)))
+			if atLimit {
+				d.writeUnparsed(contents, eocEnd-2, indent+1)
+			} else {
+				// Stop before the EOC, so the contents end there
+				// even if the element limit is reached.
+				d.disassemble(contents, eocEnd-2, indent+1, true, body, context)
+			}
+			d.addLine(indent, "}")
+			off = eocEnd
+		case elem.indefinite:
+			// Otherwise, we must write a raw `80` literal.
+			d.addLine(indent, fmt.Sprintf("%s `80`", tagToStringWithVocabulary(elem.tag, d.vocabulary)))
+			if atLimit {
+				d.writeUnparsed(contents, end, indent+1)
+				off = end
+			} else {
+				off = d.disassemble(contents, end, indent+1, true, body, context)
+			}
+		case length == 0:
+			header := elementHeader(elem, d.vocabulary)
+			var comments []string
+			if body != nil {
+				comments = body.end()
+			}
+			if len(comments) != 0 {
+				d.addLine(indent, header)
+				d.addComments(indent+1, comments)
+				d.addLine(indent, "}")
+			} else {
+				// If the body is empty, skip the newlines.
+				d.addLine(indent, header+"}")
+			}
+			off = contents
+		case elem.tag.Constructed:
+			// If the element is constructed, recurse.
+			d.addLine(indent, elementHeader(elem, d.vocabulary))
+			if atLimit {
+				d.writeUnparsed(contents, contents+length, indent+1)
+			} else {
+				d.disassemble(contents, contents+length, indent+1, false, body, context)
+			}
+			d.addLine(indent, "}")
+			off = contents + length
+		default:
+			d.writePrimitive(elem, contents, contents+length, indent, body, context)
+			off = contents + length
+		}
+		context = nextContext(elem, context)
+	}
+	if s != nil {
+		d.addComments(indent, s.end())
+	}
+	return off
+}
+
+// writeUnparsed writes the contents from start to end of an element at the
+// depth limit as hex.
+func (d *disassembler) writeUnparsed(start, end int64, indent int) {
+	if start == end {
+		return
+	}
+	d.addLine(indent, "# "+d.lim.depthNote())
+	d.writeValueLine(indent, "", start, end, true, "")
+}
+
+// writePrimitive writes elem, a primitive element with contents from start to
+// end in the given context. If elem's body was not read into memory, it is
+// written incrementally.
+func (d *disassembler) writePrimitive(elem element, start, end int64, indent int, body structure, context string) {
+	header := elementHeader(elem, d.vocabulary)
+	// If ok is false, name will be empty.
+	name, _, _ := elem.tag.GetAlias()
+	// At the depth or element limit, do not check if the body looks like
+	// ASN.1.
+	atLimit := d.lim.depthReached(indent) || d.lim.maxElements > 0 && d.elements >= d.lim.maxElements
+	if !atLimit && !isPrimitiveDecoded(elem.tag, context) {
+		if name == "BIT_STRING" {
+			// X.509 signatures and SPKIs are always logically
+			// treated as byte strings, but mistakenly encoded as a
+			// BIT STRING. In some cases, these byte strings are
+			// DER-encoded structures themselves.
+			if end-start > 1 && d.read(start, 1)[0] == 0 && d.isMadeOfElements(start+1, end) {
+				d.addLine(indent, header)
+				// Emit number of unused bits.
+				d.addLine(indent+1, "`00`")
+				d.disassemble(start+1, end, indent+1, false, body, context)
+				d.addLine(indent, "}")
+				return
+			}
+		} else if d.isMadeOfElements(start, end) {
+			// Keep parsing if the body looks like ASN.1.
+			d.addLine(indent, header)
+			d.disassemble(start, end, indent+1, false, body, context)
+			d.addLine(indent, "}")
+			return
+		}
+	}
+
+	if elem.body != nil {
+		// Emit the body on the same line as curly braces, as rendered
+		// by the registered body renderer.
+		value := bytesToString(elem.body)
+		if r, ok := findRenderer(elem.tag, context); ok && r.Render != nil {
+			if rendered, comments, ok := r.Render(Element{Tag: elem.tag, Body: elem.body}); ok {
+				d.addComments(indent, comments)
+				value = rendered
+			}
+		}
+		d.addLine(indent, fmt.Sprintf("%s %s }", header, value))
+		return
+	}
+
+	// The body is too large to read into memory, and its type is not
+	// rendered, so write it as the renderers would.
+	switch name {
+	case "INTEGER":
+		// The value is too large to write in decimal.
+		d.writeValueLine(indent, header+" ", start, end, true, " }")
+	case "BIT_STRING":
+		unused := d.read(start, 1)
+		if unused[0] < 8 {
+			d.writeValueLine(indent, fmt.Sprintf("%s %s ", header, bytesToString(unused)), start+1, end, false, " }")
+		} else {
+			d.writeValueLine(indent, header+" ", start, end, false, " }")
+		}
+	default:
+		d.writeValueLine(indent, header+" ", start, end, false, " }")
+	}
+}
+
+// writeValueLine writes a line consisting of prefix, the input from start to
+// end as bytesToString, or bytesToHexString if hexOnly is true, would write
+// it, and suffix.
+func (d *disassembler) writeValueLine(indent int, prefix string, start, end int64, hexOnly bool, suffix string) {
+	quoted := false
+	if !hexOnly && end > start {
+		// Match the heuristic in bytesToString.
+		var asciiCount int64
+		for off := start; off < end && d.err == nil; off += streamChunkSize {
+			for _, b := range d.read(off, min64(streamChunkSize, end-off)) {
+				if b < 0x80 && (b == '\n' || unicode.IsPrint(rune(b))) {
+					asciiCount++
+				}
+			}
+		}
+		quoted = float64(asciiCount)/float64(end-start) > 0.85
+	}
+
+	d.writeString(strings.Repeat("  ", indent))
+	d.writeString(prefix)
+	if end > start {
+		delim := "`"
+		if quoted {
+			delim = `"`
+		}
+		d.writeString(delim)
+		for off := start; off < end && d.err == nil; off += streamChunkSize {
+			chunk := d.read(off, min64(streamChunkSize, end-off))
+			if quoted {
+				s := bytesToQuotedString(chunk)
+				d.writeString(s[1 : len(s)-1])
+			} else {
+				d.writeString(hex.EncodeToString(chunk))
+			}
+		}
+		d.writeString(delim)
+	}
+	d.writeString(suffix)
+	d.writeString("\n")
+}
+
+func min64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,112 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package der2ascii
+
+import (
+	"bytes"
+	"testing"
+)
+
+// streamLimits are the limits on the size of elements read into memory at which
+// the disassembler is tested. Small limits cause nearly every element to be
+// disassembled incrementally.
+var streamLimits = []int64{0, 1, 4, 16, maxBufferedElement}
+
+func streamToASCII(t *testing.T, in []byte, limit int64, s structure) string {
+	return streamToASCIIWithLimits(t, in, limit, s, defaultLimits)
+}
+
+func streamToASCIIWithLimits(t *testing.T, in []byte, limit int64, s structure, lim limits) string {
+	var out bytes.Buffer
+	d := newDisassembler(&out, bytes.NewReader(in), limit, lim)
+	d.disassemble(0, int64(len(in)), 0, false, s, "")
+	if d.err == nil {
+		d.err = d.w.Flush()
+	}
+	if d.err != nil {
+		t.Fatalf("streaming %x failed: %s", in, d.err)
+	}
+	return out.String()
+}
+
+// largeStreamTests are inputs with elements larger than the default limit.
+var largeStreamTests = [][]byte{
+	// A large text string.
+	append([]byte{0x04, 0x83, 0x20, 0x00, 0x00}, bytes.Repeat([]byte("text\""), 0x200000/5+1)[:0x200000]...),
+	// A large binary string.
+	append([]byte{0x04, 0x83, 0x20, 0x00, 0x00}, bytes.Repeat([]byte{0x00, 0xff}, 0x100000)...),
+	// A large BIT STRING and INTEGER.
+	append([]byte{0x03, 0x83, 0x20, 0x00, 0x01, 0x00}, bytes.Repeat([]byte{0x01}, 0x200000)...),
+	append([]byte{0x02, 0x83, 0x20, 0x00, 0x00}, bytes.Repeat([]byte{0x01}, 0x200000)...),
+	// A large SEQUENCE, nested in a BIT STRING and an OCTET STRING.
+	append([]byte{0x03, 0x83, 0x20, 0x08, 0x0b, 0x00, 0x04, 0x83, 0x20, 0x08, 0x05, 0x30, 0x83, 0x20, 0x08, 0x00}, bytes.Repeat(append([]byte{0x04, 0x82, 0x10, 0x00}, make([]byte, 0x1000)...), 0x200)...),
+	// A large indefinite-length element, with and without an EOC.
+	append(append([]byte{0x30, 0x80}, bytes.Repeat(append([]byte{0x24, 0x80, 0x04, 0x82, 0x10, 0x00}, append(make([]byte, 0x1000), 0x00, 0x00)...), 0x200)...), 0x00, 0x00),
+	append([]byte{0x30, 0x80}, bytes.Repeat(append([]byte{0x24, 0x80, 0x04, 0x82, 0x10, 0x00}, append(make([]byte, 0x1000), 0x00, 0x00)...), 0x200)...),
+	// A large element followed by trailing garbage.
+	append(append([]byte{0x30, 0x83, 0x10, 0x00, 0x05, 0x04, 0x83, 0x10, 0x00, 0x00}, bytes.Repeat([]byte{0xff}, 0x100000)...), 0x30, 0x05),
+}
+
+func TestDERToASCIIStream(t *testing.T) {
+	var inputs [][]byte
+	for _, tt := range derToASCIITests {
+		inputs = append(inputs, tt.in)
+	}
+	for i, in := range inputs {
+		want := derToASCII(in)
+		for _, limit := range streamLimits {
+			if out := streamToASCII(t, in, limit, nil); out != want {
+				t.Errorf("%d. streaming %x with limit %d = %q, want %q.", i, in, limit, out, want)
+			}
+		}
+	}
+	for i, in := range largeStreamTests {
+		want := derToASCII(in)
+		for _, limit := range []int64{16, maxBufferedElement} {
+			if out := streamToASCII(t, in, limit, nil); out != want {
+				t.Errorf("%d. streaming large input with limit %d did not match derToASCII.", i, limit)
+			}
+		}
+	}
+}
+
+func TestDERToASCIIStreamLimits(t *testing.T) {
+	for i, tt := range limitTests {
+		for _, limit := range streamLimits {
+			if out := streamToASCIIWithLimits(t, tt.in, limit, nil, tt.lim); out != tt.out {
+				t.Errorf("%d. streaming %x with limit %d and %+v = %q, want %q.", i, tt.in, limit, tt.lim, out, tt.out)
+			}
+		}
+	}
+}
+
+func TestDERToASCIIStreamAnnotated(t *testing.T) {
+	s, err := parseSchema(testSchema)
+	if err != nil {
+		t.Fatalf("parseSchema failed: %s", err)
+	}
+	s.definedBy["1.2.840"] = &schemaType{kind: schemaReference, name: "Name"}
+	for i, tt := range annotateTests {
+		for _, limit := range streamLimits {
+			st, err := newSchemaStructure(s, tt.typ, false)
+			if err != nil {
+				t.Fatalf("%d. newSchemaStructure failed: %s", i, err)
+			}
+			if out := streamToASCII(t, tt.in, limit, st); out != tt.out {
+				t.Errorf("%d. streaming %x with limit %d = %q, want %q.", i, tt.in, limit, out, tt.out)
+			}
+		}
+	}
+}
@@ -0,0 +1,321 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package der2ascii
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/google/der-ascii/internal"
+)
+
+// A jsonTag is the JSON representation of a tag.
+type jsonTag struct {
+	Class       string      `json:"class"`
+	Number      json.Number `json:"number"`
+	Constructed bool        `json:"constructed"`
+	// Alias is the name of the tag, if it has one.
+	Alias string `json:"alias,omitempty"`
+	// LongForm, if non-zero, is the number of bytes the tag number is
+	// encoded with in long form, excluding the initial byte.
+	LongForm int `json:"long_form,omitempty"`
+}
+
+// A jsonValue is the JSON representation of a decoded element body. At most
+// one group of fields is set.
+type jsonValue struct {
+	// Integer is the decimal value of an INTEGER or ENUMERATED.
+	Integer string `json:"integer,omitempty"`
+	// OID and OIDName are the dotted value and name, if known, of an OBJECT
+	// IDENTIFIER.
+	OID     string `json:"oid,omitempty"`
+	OIDName string `json:"oid_name,omitempty"`
+	// RelativeOID is the value of a RELATIVE-OID, with a leading dot.
+	RelativeOID string `json:"relative_oid,omitempty"`
+	// Real is the value of a REAL, as written in der2ascii's output without
+	// the "real:" prefix.
+	Real string `json:"real,omitempty"`
+	// Boolean is the value of a BOOLEAN.
+	Boolean *bool `json:"boolean,omitempty"`
+	// UnusedBits and Bits are the number of unused bits and the value, as a
+	// string of 0s and 1s, of a BIT STRING.
+	UnusedBits *int    `json:"unused_bits,omitempty"`
+	Bits       *string `json:"bits,omitempty"`
+	// String is the value of a string type.
+	String *string `json:"string,omitempty"`
+	// Text and Comments are the value and comments written by a renderer
+	// without a JSON function.
+	Text     string   `json:"text,omitempty"`
+	Comments []string `json:"comments,omitempty"`
+}
+
+// A jsonElement is the JSON representation of an element, or of bytes which
+// could not be parsed as an element.
+type jsonElement struct {
+	// Offset is the offset of the element, or the bytes, in the input.
+	Offset int `json:"offset"`
+	// Raw, if the node is not an element, is the hex-encoded bytes.
+	Raw string `json:"raw,omitempty"`
+
+	Tag          *jsonTag `json:"tag,omitempty"`
+	HeaderLength int      `json:"header_length,omitempty"`
+	BodyOffset   int      `json:"body_offset,omitempty"`
+	// BodyLength is the length of the body, excluding any end-of-contents
+	// marker. It is a pointer so that empty bodies are distinguished from
+	// nodes which are not elements.
+	BodyLength *int `json:"body_length,omitempty"`
+	// Indefinite is true if the element has an indefinite length.
+	Indefinite bool `json:"indefinite,omitempty"`
+	// MissingEOC is true if the element has an indefinite length, but the
+	// end-of-contents marker was not found.
+	MissingEOC bool `json:"missing_eoc,omitempty"`
+	// LongForm, if non-zero, is the number of bytes the length is encoded
+	// with in non-minimal long form, excluding the initial byte.
+	LongForm int `json:"long_form,omitempty"`
+
+	// Children, if the body was parsed as a series of elements, contains
+	// them. Otherwise, Bytes is the hex-encoded body and Value, if
+	// non-nil, is the body as decoded by the registered renderer. For the
+	// built-in types, it is a jsonValue.
+	Children []jsonElement `json:"children,omitempty"`
+	Bytes    *string       `json:"bytes,omitempty"`
+	Value    interface{}   `json:"value,omitempty"`
+
+	// Note, if not empty, explains why the node was not disassembled
+	// further because of a limit. The remaining bytes are then in Raw or,
+	// for an element, Bytes.
+	Note string `json:"note,omitempty"`
+}
+
+// A jsonDocument is the JSON representation of a der2ascii input.
+type jsonDocument struct {
+	// PEMType is the type of the PEM block the input was decoded from, if
+	// any.
+	PEMType  string        `json:"pem_type,omitempty"`
+	Elements []jsonElement `json:"elements"`
+}
+
+func tagToJSON(tag internal.Tag) *jsonTag {
+	var class string
+	switch tag.Class {
+	case internal.ClassContextSpecific:
+		class = "CONTEXT-SPECIFIC"
+	default:
+		class = classToString(tag.Class)
+	}
+	alias, _, _ := tag.GetAlias()
+	return &jsonTag{
+		Class:       class,
+		Number:      json.Number(tag.NumberString()),
+		Constructed: tag.Constructed,
+		Alias:       alias,
+		LongForm:    tag.LongFormOverride,
+	}
+}
+
+func bitStringToJSON(in []byte) *jsonValue {
+	if len(in) == 0 || in[0] >= 8 || (len(in) == 1 && in[0] != 0) {
+		return nil
+	}
+	unused := int(in[0])
+	var bits strings.Builder
+	for i, b := range in[1:] {
+		n := 8
+		if i == len(in)-2 {
+			n -= unused
+		}
+		for j := 0; j < n; j++ {
+			if b&(0x80>>uint(j)) != 0 {
+				bits.WriteByte('1')
+			} else {
+				bits.WriteByte('0')
+			}
+		}
+	}
+	s := bits.String()
+	return &jsonValue{UnusedBits: &unused, Bits: &s}
+}
+
+func utf16ToJSON(in []byte) *jsonValue {
+	if len(in)%2 != 0 {
+		return nil
+	}
+	u := make([]uint16, len(in)/2)
+	for i := range u {
+		u[i] = uint16(in[2*i])<<8 | uint16(in[2*i+1])
+	}
+	for i := 0; i < len(u); i++ {
+		// Reject unpaired surrogates, which utf16.Decode would replace.
+		if utf16.IsSurrogate(rune(u[i])) {
+			if i+1 >= len(u) || utf16.DecodeRune(rune(u[i]), rune(u[i+1])) == utf8.RuneError {
+				return nil
+			}
+			i++
+		}
+	}
+	s := string(utf16.Decode(u))
+	return &jsonValue{String: &s}
+}
+
+func utf32ToJSON(in []byte) *jsonValue {
+	if len(in)%4 != 0 {
+		return nil
+	}
+	var s strings.Builder
+	for i := 0; i < len(in); i += 4 {
+		r := rune(uint32(in[i])<<24 | uint32(in[i+1])<<16 | uint32(in[i+2])<<8 | uint32(in[i+3]))
+		if !utf8.ValidRune(r) {
+			return nil
+		}
+		s.WriteRune(r)
+	}
+	str := s.String()
+	return &jsonValue{String: &str}
+}
+
+func integerToJSON(in []byte) *jsonValue {
+	if v, ok := decodeBigInteger(in); ok {
+		return &jsonValue{Integer: v.String()}
+	}
+	return nil
+}
+
+func objectIdentifierToJSON(in []byte) *jsonValue {
+	if _, ok := decodeObjectIdentifier(in); !ok {
+		return nil
+	}
+	name, _ := objectIdentifierToName(in)
+	return &jsonValue{OID: objectIdentifierToString(in), OIDName: name}
+}
+
+func relativeOIDToJSON(in []byte) *jsonValue {
+	if _, ok := decodeRelativeOID(in); !ok {
+		return nil
+	}
+	return &jsonValue{RelativeOID: relativeOIDToString(in)}
+}
+
+func realToJSON(in []byte) *jsonValue {
+	if v, ok := decodeReal(in); ok {
+		return &jsonValue{Real: v}
+	}
+	return nil
+}
+
+func booleanToJSON(in []byte) *jsonValue {
+	if len(in) != 1 || (in[0] != 0x00 && in[0] != 0xff) {
+		return nil
+	}
+	b := in[0] == 0xff
+	return &jsonValue{Boolean: &b}
+}
+
+func stringToJSON(in []byte) *jsonValue {
+	if !utf8.Valid(in) {
+		return nil
+	}
+	s := string(in)
+	return &jsonValue{String: &s}
+}
+
+// jsonValueFunc returns a function for Renderer.JSON which decodes bodies with
+// f, which returns nil if the body cannot be decoded.
+func jsonValueFunc(f func(in []byte) *jsonValue) func(elem Element) (interface{}, bool) {
+	return func(elem Element) (interface{}, bool) {
+		if v := f(elem.Body); v != nil {
+			return v, true
+		}
+		return nil, false
+	}
+}
+
+// bodyToJSON returns the value of a primitive element, in the specified
+// context, as rendered by the registered renderer, or nil if there is none.
+func bodyToJSON(elem element, context string) interface{} {
+	r, ok := findRenderer(elem.tag, context)
+	if !ok {
+		return nil
+	}
+	e := Element{Tag: elem.tag, Body: elem.body}
+	if r.JSON != nil {
+		if v, ok := r.JSON(e); ok {
+			return v
+		}
+		return nil
+	}
+	if r.Render != nil {
+		if value, comments, ok := r.Render(e); ok {
+			return &jsonValue{Text: value, Comments: comments}
+		}
+	}
+	return nil
+}
+
+// nodesToJSON appends the JSON representation of nodes to out.
+func nodesToJSON(out []jsonElement, nodes []node) []jsonElement {
+	for i := range nodes {
+		out = append(out, nodeToJSON(&nodes[i]))
+	}
+	return out
+}
+
+func nodeToJSON(n *node) jsonElement {
+	if n.raw != nil {
+		return jsonElement{Offset: n.offset, Raw: hex.EncodeToString(n.raw), Note: n.note}
+	}
+
+	elem := n.elem
+	bodyLength := n.bodyLen
+	e := jsonElement{
+		Offset:       n.offset,
+		Tag:          tagToJSON(elem.tag),
+		HeaderLength: n.headerLen,
+		BodyOffset:   n.offset + n.headerLen,
+		BodyLength:   &bodyLength,
+		Indefinite:   elem.indefinite,
+		MissingEOC:   elem.indefinite && !n.closed,
+		LongForm:     elem.longFormOverride,
+	}
+	name, _, _ := elem.tag.GetAlias()
+	if n.note != "" {
+		b := hex.EncodeToString(n.unparsed)
+		e.Bytes = &b
+		e.Note = n.note
+	} else if elem.tag.Constructed {
+		e.Children = nodesToJSON(nil, n.children)
+	} else if n.children != nil && name == "BIT_STRING" {
+		e.Children = nodesToJSON([]jsonElement{{Offset: e.BodyOffset, Raw: "00"}}, n.children)
+	} else if n.children != nil {
+		e.Children = nodesToJSON(nil, n.children)
+	} else {
+		b := hex.EncodeToString(elem.body)
+		e.Bytes = &b
+		e.Value = bodyToJSON(elem, n.context)
+	}
+	return e
+}
+
+// derToJSON disassembles in into a tree of JSON nodes.
+func derToJSON(in []byte) []jsonElement {
+	return derToJSONWithLimits(in, defaultLimits)
+}
+
+// derToJSONWithLimits behaves like derToJSON, but disassembles subject to lim.
+func derToJSONWithLimits(in []byte, lim limits) []jsonElement {
+	return nodesToJSON([]jsonElement{}, parseTree(in, lim))
+}
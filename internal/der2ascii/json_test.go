@@ -0,0 +1,87 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package der2ascii
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+var derToJSONTests = []struct {
+	in  []byte
+	out string
+}{
+	{[]byte{}, `[]`},
+	// Primitive elements include their decoded values.
+	{
+		[]byte{0x02, 0x01, 0x01},
+		`[{"offset":0,"tag":{"class":"UNIVERSAL","number":2,"constructed":false,"alias":"INTEGER"},"header_length":2,"body_offset":2,"body_length":1,"bytes":"01","value":{"integer":"1"}}]`,
+	},
+	{
+		[]byte{0x02, 0x09, 0x00, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		`[{"offset":0,"tag":{"class":"UNIVERSAL","number":2,"constructed":false,"alias":"INTEGER"},"header_length":2,"body_offset":2,"body_length":9,"bytes":"00ffffffffffffffff","value":{"integer":"18446744073709551615"}}]`,
+	},
+	{
+		[]byte{0x06, 0x08, 0x2a, 0x86, 0x48, 0xce, 0x3d, 0x03, 0x01, 0x07},
+		`[{"offset":0,"tag":{"class":"UNIVERSAL","number":6,"constructed":false,"alias":"OBJECT_IDENTIFIER"},"header_length":2,"body_offset":2,"body_length":8,"bytes":"2a8648ce3d030107","value":{"oid":"1.2.840.10045.3.1.7","oid_name":"secp256r1"}}]`,
+	},
+	{
+		[]byte{0x01, 0x01, 0xff, 0x03, 0x02, 0x04, 0xa0, 0x1e, 0x02, 0xd8, 0x34},
+		`[{"offset":0,"tag":{"class":"UNIVERSAL","number":1,"constructed":false,"alias":"BOOLEAN"},"header_length":2,"body_offset":2,"body_length":1,"bytes":"ff","value":{"boolean":true}},` +
+			`{"offset":3,"tag":{"class":"UNIVERSAL","number":3,"constructed":false,"alias":"BIT_STRING"},"header_length":2,"body_offset":5,"body_length":2,"bytes":"04a0","value":{"unused_bits":4,"bits":"1010"}},` +
+			`{"offset":7,"tag":{"class":"UNIVERSAL","number":30,"constructed":false,"alias":"BMPString"},"header_length":2,"body_offset":9,"body_length":2,"bytes":"d834"}]`,
+	},
+	{
+		[]byte{0x0c, 0x02, 0xc3, 0xbc},
+		`[{"offset":0,"tag":{"class":"UNIVERSAL","number":12,"constructed":false,"alias":"UTF8String"},"header_length":2,"body_offset":2,"body_length":2,"bytes":"c3bc","value":{"string":"ü"}}]`,
+	},
+	// Constructed elements, and primitive elements which look like ASN.1,
+	// have children.
+	{
+		[]byte{0xbf, 0x80, 0x01, 0x81, 0x03, 0x04, 0x01, 0x00},
+		`[{"offset":0,"tag":{"class":"CONTEXT-SPECIFIC","number":1,"constructed":true,"long_form":2},"header_length":5,"body_offset":5,"body_length":3,"long_form":1,` +
+			`"children":[{"offset":5,"tag":{"class":"UNIVERSAL","number":4,"constructed":false,"alias":"OCTET_STRING"},"header_length":2,"body_offset":7,"body_length":1,"bytes":"00"}]}]`,
+	},
+	{
+		[]byte{0x04, 0x02, 0x05, 0x00},
+		`[{"offset":0,"tag":{"class":"UNIVERSAL","number":4,"constructed":false,"alias":"OCTET_STRING"},"header_length":2,"body_offset":2,"body_length":2,` +
+			`"children":[{"offset":2,"tag":{"class":"UNIVERSAL","number":5,"constructed":false,"alias":"NULL"},"header_length":2,"body_offset":4,"body_length":0,"bytes":""}]}]`,
+	},
+	{
+		[]byte{0x03, 0x03, 0x00, 0x05, 0x00},
+		`[{"offset":0,"tag":{"class":"UNIVERSAL","number":3,"constructed":false,"alias":"BIT_STRING"},"header_length":2,"body_offset":2,"body_length":3,` +
+			`"children":[{"offset":2,"raw":"00"},{"offset":3,"tag":{"class":"UNIVERSAL","number":5,"constructed":false,"alias":"NULL"},"header_length":2,"body_offset":5,"body_length":0,"bytes":""}]}]`,
+	},
+	// Indefinite-length elements, with and without EOC, followed by
+	// trailing data.
+	{
+		[]byte{0x30, 0x80, 0x05, 0x00, 0x00, 0x00, 0x30, 0x80, 0x05, 0x00, 0xff},
+		`[{"offset":0,"tag":{"class":"UNIVERSAL","number":16,"constructed":true,"alias":"SEQUENCE"},"header_length":2,"body_offset":2,"body_length":2,"indefinite":true,` +
+			`"children":[{"offset":2,"tag":{"class":"UNIVERSAL","number":5,"constructed":false,"alias":"NULL"},"header_length":2,"body_offset":4,"body_length":0,"bytes":""}]},` +
+			`{"offset":6,"tag":{"class":"UNIVERSAL","number":16,"constructed":true,"alias":"SEQUENCE"},"header_length":2,"body_offset":8,"body_length":3,"indefinite":true,"missing_eoc":true,` +
+			`"children":[{"offset":8,"tag":{"class":"UNIVERSAL","number":5,"constructed":false,"alias":"NULL"},"header_length":2,"body_offset":10,"body_length":0,"bytes":""},{"offset":10,"raw":"ff"}]}]`,
+	},
+}
+
+func TestDERToJSON(t *testing.T) {
+	for i, tt := range derToJSONTests {
+		out, err := json.Marshal(derToJSON(tt.in))
+		if err != nil {
+			t.Errorf("%d. json.Marshal failed: %s", i, err)
+		} else if string(out) != tt.out {
+			t.Errorf("%d. derToJSON(%x) = %s, want %s.", i, tt.in, out, tt.out)
+		}
+	}
+}
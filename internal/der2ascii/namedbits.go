@@ -0,0 +1,109 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package der2ascii
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/der-ascii/internal"
+)
+
+var (
+	tagBoolean   = internal.Tag{Class: internal.ClassUniversal, Number: 1}
+	tagBitString = internal.Tag{Class: internal.ClassUniversal, Number: 3}
+)
+
+// namedBitExtensions maps the OIDs of X.509 extensions whose values are BIT
+// STRINGs to their types in the named bit registry.
+var namedBitExtensions = map[string]string{
+	"2.5.29.15":             "keyUsage",
+	"2.16.840.1.113730.1.1": "netscapeCertType",
+}
+
+// setBits returns the positions of the set bits in in, the contents of a BIT
+// STRING, or false if in is not a valid BIT STRING.
+func setBits(in []byte) ([]int, bool) {
+	if len(in) == 0 || in[0] >= 8 || (len(in) == 1 && in[0] != 0) {
+		return nil, false
+	}
+	var positions []int
+	bits := in[1:]
+	for i := 0; i < len(bits)*8; i++ {
+		if bits[i/8]&(0x80>>uint(i%8)) != 0 {
+			positions = append(positions, i)
+		}
+	}
+	return positions, true
+}
+
+// describeNamedBits returns the names of the bits set in in, the contents of a
+// BIT STRING of type typ in the named bit registry, or the empty string if
+// there are none.
+func describeNamedBits(typ string, in []byte) string {
+	positions, _ := setBits(in)
+	var names []string
+	for _, p := range positions {
+		if name, ok := internal.NamedBit(typ, p); ok {
+			names = append(names, name)
+		} else {
+			names = append(names, fmt.Sprintf("bit %d", p))
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// namedBitsDescriber returns a function which describes BIT STRINGs of type typ
+// in the named bit registry, for use in profiles.
+func namedBitsDescriber(typ string) func(element) string {
+	return func(elem element) string { return describeNamedBits(typ, elem.body) }
+}
+
+// namedBitsStructure recognizes BIT STRINGs with named bits in X.509
+// extensions and describes which bits are set. It is used when der2ascii is
+// not given a schema.
+type namedBitsStructure struct {
+	// typ, if not empty, is the named bit type of BIT STRINGs in this
+	// position.
+	typ string
+	// extension is the named bit type of the X.509 extension whose extnID
+	// was seen, if any.
+	extension string
+}
+
+func (n *namedBitsStructure) next(elem element) ([]string, structure) {
+	if n.typ != "" && elem.tag == tagBitString {
+		if desc := describeNamedBits(n.typ, elem.body); desc != "" {
+			return []string{desc}, nil
+		}
+		return nil, nil
+	}
+
+	body := &namedBitsStructure{}
+	switch elem.tag {
+	case tagOID:
+		n.extension = namedBitExtensions[objectIdentifierToString(elem.body)]
+	case tagBoolean:
+		// Skip the critical flag.
+	case tagOctetString:
+		body.typ = n.extension
+		n.extension = ""
+	default:
+		n.extension = ""
+	}
+	return nil, body
+}
+
+func (n *namedBitsStructure) end() []string { return nil }
@@ -0,0 +1,101 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package der2ascii
+
+import (
+	"bytes"
+	"testing"
+)
+
+var describeNamedBitsTests = []struct {
+	typ  string
+	in   []byte
+	want string
+}{
+	{"keyUsage", []byte{0x02, 0x84}, "digitalSignature, keyCertSign"},
+	{"keyUsage", []byte{0x07, 0x00, 0x80}, "decipherOnly"},
+	{"keyUsage", []byte{0x00, 0x00, 0x10}, "bit 11"},
+	{"keyUsage", []byte{0x00}, ""},
+	{"keyUsage", []byte{0x08, 0xff}, ""},
+	{"keyUsage", []byte{0x01}, ""},
+	{"keyUsage", nil, ""},
+}
+
+func TestDescribeNamedBits(t *testing.T) {
+	for i, tt := range describeNamedBitsTests {
+		if got := describeNamedBits(tt.typ, tt.in); got != tt.want {
+			t.Errorf("%d. describeNamedBits(%q, %x) = %q, want %q", i, tt.typ, tt.in, got, tt.want)
+		}
+	}
+}
+
+var namedBitsStructureTests = []struct {
+	in   []byte
+	want string
+}{
+	// A critical key usage extension.
+	{
+		[]byte{0x30, 0x0e, 0x06, 0x03, 0x55, 0x1d, 0x0f, 0x01, 0x01, 0xff, 0x04, 0x04, 0x03, 0x02, 0x02, 0x84},
+		`SEQUENCE {
+  # keyUsage
+  OBJECT_IDENTIFIER { 2.5.29.15 }
+  BOOLEAN { TRUE }
+  OCTET_STRING {
+    # digitalSignature, keyCertSign
+    BIT_STRING { b` + "`100001`" + ` }
+  }
+}
+`,
+	},
+	// Other extensions are not annotated.
+	{
+		[]byte{0x30, 0x0b, 0x06, 0x03, 0x55, 0x1d, 0x0e, 0x04, 0x04, 0x03, 0x02, 0x02, 0x84},
+		`SEQUENCE {
+  # subjectKeyIdentifier
+  OBJECT_IDENTIFIER { 2.5.29.14 }
+  OCTET_STRING {
+    BIT_STRING { b` + "`100001`" + ` }
+  }
+}
+`,
+	},
+	// The extension value must immediately follow the OID.
+	{
+		[]byte{0x30, 0x0d, 0x06, 0x03, 0x55, 0x1d, 0x0f, 0x05, 0x00, 0x04, 0x04, 0x03, 0x02, 0x02, 0x84},
+		`SEQUENCE {
+  # keyUsage
+  OBJECT_IDENTIFIER { 2.5.29.15 }
+  NULL {}
+  OCTET_STRING {
+    BIT_STRING { b` + "`100001`" + ` }
+  }
+}
+`,
+	},
+}
+
+func TestNamedBitsStructure(t *testing.T) {
+	for i, tt := range namedBitsStructureTests {
+		if got := derToASCIIWithStructure(tt.in, &namedBitsStructure{}); got != tt.want {
+			t.Errorf("%d. derToASCIIWithStructure(%x) = %s, want %s", i, tt.in, got, tt.want)
+		}
+		var out bytes.Buffer
+		if err := derToASCIIStream(&out, bytes.NewReader(tt.in), int64(len(tt.in)), &namedBitsStructure{}, defaultLimits, nil); err != nil {
+			t.Errorf("%d. derToASCIIStream failed: %s", i, err)
+		} else if out.String() != tt.want {
+			t.Errorf("%d. derToASCIIStream = %s, want %s", i, out.String(), tt.want)
+		}
+	}
+}
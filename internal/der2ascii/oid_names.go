@@ -15,7 +15,7 @@
 // This file is generated by make_oid_names.go. Do not edit by hand.
 // To regenerate, run "go run util/make_oid_names.go" from the top-level directory.
 
-package main
+package der2ascii
 
 var oidNames = []struct {
 	oid  []byte
@@ -210,4 +210,31 @@ var oidNames = []struct {
 	{[]byte{0x60, 0x86, 0x48, 0x1, 0x65, 0x3, 0x4, 0x1, 0x2}, "AES-128-CBC"},
 	{[]byte{0x60, 0x86, 0x48, 0x1, 0x65, 0x3, 0x4, 0x1, 0x16}, "AES-192-CBC"},
 	{[]byte{0x60, 0x86, 0x48, 0x1, 0x65, 0x3, 0x4, 0x1, 0x2a}, "AES-256-CBC"},
+	{[]byte{0x2a, 0x86, 0x48, 0x86, 0xf7, 0x12, 0x1, 0x2, 0x2}, "krb5"},
+	{[]byte{0x2a, 0x86, 0x48, 0x86, 0xf7, 0x12, 0x1, 0x2, 0x2, 0x3}, "krb5-user-to-user"},
+	{[]byte{0x2a, 0x86, 0x48, 0x82, 0xf7, 0x12, 0x1, 0x2, 0x2}, "ms-krb5"},
+	{[]byte{0x2b, 0x6, 0x1, 0x5, 0x5, 0x2}, "spnego"},
+	{[]byte{0x2b, 0x6, 0x1, 0x4, 0x1, 0x82, 0x37, 0x2, 0x2, 0xa}, "ntlmssp"},
+	{[]byte{0x2b, 0x6, 0x1, 0x5, 0x2, 0x2}, "id-pkinit-san"},
+	{[]byte{0x2b, 0x6, 0x1, 0x5, 0x2, 0x3, 0x1}, "id-pkinit-authData"},
+	{[]byte{0x2b, 0x6, 0x1, 0x5, 0x2, 0x3, 0x2}, "id-pkinit-DHKeyData"},
+	{[]byte{0x2b, 0x6, 0x1, 0x5, 0x2, 0x3, 0x3}, "id-pkinit-rkeyData"},
+	{[]byte{0x2b, 0x6, 0x1, 0x5, 0x2, 0x3, 0x4}, "id-pkinit-KPClientAuth"},
+	{[]byte{0x2b, 0x6, 0x1, 0x5, 0x2, 0x3, 0x5}, "id-pkinit-KPKdc"},
+	{[]byte{0x2a, 0x86, 0x48, 0x86, 0xf7, 0x14, 0x1, 0x4, 0x82, 0x3f}, "pagedResults"},
+	{[]byte{0x2a, 0x86, 0x48, 0x86, 0xf7, 0x14, 0x1, 0x4, 0x83, 0x59}, "sortRequest"},
+	{[]byte{0x2a, 0x86, 0x48, 0x86, 0xf7, 0x14, 0x1, 0x4, 0x83, 0x5a}, "sortResponse"},
+	{[]byte{0x60, 0x86, 0x48, 0x1, 0x86, 0xf8, 0x42, 0x3, 0x4, 0x2}, "manageDsaIT"},
+	{[]byte{0x60, 0x86, 0x48, 0x1, 0x86, 0xf8, 0x42, 0x3, 0x4, 0x12}, "proxiedAuthorization"},
+	{[]byte{0x2b, 0x6, 0x1, 0x1, 0xc}, "assertion"},
+	{[]byte{0x2b, 0x6, 0x1, 0x1, 0xd, 0x1}, "preRead"},
+	{[]byte{0x2b, 0x6, 0x1, 0x1, 0xd, 0x2}, "postRead"},
+	{[]byte{0x2b, 0x6, 0x1, 0x1, 0x8}, "cancel"},
+	{[]byte{0x2b, 0x6, 0x1, 0x4, 0x1, 0x8b, 0x3a, 0x81, 0x9c, 0x44}, "noticeOfDisconnection"},
+	{[]byte{0x2b, 0x6, 0x1, 0x4, 0x1, 0x8b, 0x3a, 0x81, 0x9c, 0x45}, "startTLS"},
+	{[]byte{0x2b, 0x6, 0x1, 0x4, 0x1, 0xa0, 0x6b, 0x1, 0xb, 0x1}, "passwordModify"},
+	{[]byte{0x2b, 0x6, 0x1, 0x4, 0x1, 0xa0, 0x6b, 0x1, 0xb, 0x3}, "whoAmI"},
+	{[]byte{0x2b, 0x6, 0x1, 0x4, 0x1, 0xa0, 0x6b, 0x1, 0x9, 0x1, 0x1}, "syncRequest"},
+	{[]byte{0x2b, 0x6, 0x1, 0x4, 0x1, 0xa0, 0x6b, 0x1, 0x9, 0x1, 0x2}, "syncState"},
+	{[]byte{0x2b, 0x6, 0x1, 0x4, 0x1, 0xa0, 0x6b, 0x1, 0x9, 0x1, 0x3}, "syncDone"},
 }
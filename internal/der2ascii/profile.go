@@ -0,0 +1,175 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package der2ascii
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/der-ascii/internal"
+)
+
+// A profile is a built-in schema for a family of formats.
+type profile struct {
+	// modules are the texts of the ASN.1 modules, which may refer to each
+	// other's types.
+	modules []string
+	// roots are the names of the types which may appear at the top level.
+	roots []string
+	// definedBy maps dotted OIDs to ASN.1 types, and is used to resolve ANY
+	// DEFINED BY. A key may also be prefixed by the name of a SEQUENCE or
+	// SET type and a space, to apply only to that type's components.
+	definedBy map[string]string
+	// describers maps type names to functions which describe values of
+	// that type.
+	describers map[string]func(element) string
+	// repeated is true if the input may contain several top-level
+	// elements, as in a capture of a connection.
+	repeated bool
+	// vocabulary, if not empty, is the name of the built-in tag vocabulary
+	// used to name the profile's non-universal tags.
+	vocabulary string
+}
+
+// profiles contains the built-in profiles, by name.
+var profiles = map[string]*profile{
+	"cms":      cmsProfile,
+	"kerberos": kerberosProfile,
+	"ldap":     ldapProfile,
+	"pkcs12":   pkcs12Profile,
+	"snmp":     snmpProfile,
+	"x509":     x509Profile,
+}
+
+// mergeDefinedBy returns a map containing the entries of each of maps. Later
+// maps take precedence.
+func mergeDefinedBy(maps ...map[string]string) map[string]string {
+	ret := make(map[string]string)
+	for _, m := range maps {
+		for k, v := range m {
+			ret[k] = v
+		}
+	}
+	return ret
+}
+
+// profileNames returns the names of the built-in profiles, sorted.
+func profileNames() []string {
+	var names []string
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// schema parses the profile's module and returns the resulting schema.
+func (p *profile) schema() (*schema, error) {
+	s, err := parseSchema(strings.Join(p.modules, "\n"))
+	if err != nil {
+		return nil, err
+	}
+	for key, text := range p.definedBy {
+		t, err := s.parseType(text)
+		if err != nil {
+			return nil, fmt.Errorf("type for %s: %s", key, err)
+		}
+		s.definedBy[key] = t
+	}
+	for name, f := range p.describers {
+		s.describers[name] = f
+	}
+	return s, nil
+}
+
+// rootDetectionDepth is how deep detectRoot looks to distinguish root types.
+const rootDetectionDepth = 4
+
+// detectRoot returns the name of the type in roots which best matches in.
+func (s *schema) detectRoot(roots []string, in []byte) string {
+	best, bestCount := roots[0], -1
+	for _, root := range roots {
+		st, err := newSchemaStructure(s, root, false)
+		if err != nil {
+			continue
+		}
+		if count := countMismatches(st, in, rootDetectionDepth); bestCount < 0 || count < bestCount {
+			best, bestCount = root, count
+		}
+	}
+	return best
+}
+
+// countMismatches returns the number of deviations from st in in, looking at
+// most depth levels deep. in may be a prefix of the input, in which case the
+// last element is truncated and only its available contents are checked.
+func countMismatches(st structure, in []byte, depth int) int {
+	return countMismatchesImpl(st, in, depth, true)
+}
+
+// countMismatchesImpl implements countMismatches. If isPrefix is false, in is
+// a complete list of elements, so a truncated element is a mismatch.
+func countMismatchesImpl(st structure, in []byte, depth int, isPrefix bool) int {
+	var count int
+	countComments := func(comments []string) {
+		for _, comment := range comments {
+			if strings.HasPrefix(comment, mismatchPrefix) {
+				count++
+			}
+		}
+	}
+	for len(in) != 0 {
+		elem, rest, ok := parseElement(in)
+		if !ok {
+			if !isPrefix {
+				count++
+				break
+			}
+			// The last element may be truncated. Check what is
+			// available, but do not count components missing from
+			// the end.
+			if elem, _, rest, ok = parseTagAndLength(in); ok {
+				comments, body := st.next(elem)
+				countComments(comments)
+				if body != nil && depth > 0 && elem.tag.Constructed {
+					count += countMismatchesImpl(body, rest, depth-1, true)
+				}
+			}
+			return count
+		}
+		in = rest
+		comments, body := st.next(elem)
+		countComments(comments)
+		if elem.indefinite {
+			// Stop at indefinite-length elements. Their contents
+			// follow in the input.
+			return count
+		}
+		if body == nil || depth == 0 {
+			continue
+		}
+		contents := elem.body
+		if elem.tag.Class == internal.ClassUniversal && elem.tag.Number == 3 && len(contents) > 0 {
+			// Skip the unused bits count of a BIT STRING.
+			contents = contents[1:]
+		}
+		if elem.tag.Constructed || isMadeOfElements(contents) {
+			count += countMismatchesImpl(body, contents, depth-1, false)
+		}
+	}
+	countComments(st.end())
+	return count
+}
@@ -0,0 +1,374 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package der2ascii
+
+// kerberosModule is a subset of the module in RFC 4120, appendix A, covering
+// the Kerberos V5 messages and their encrypted parts. Fields which are Int32
+// in RFC 4120 but take registered values are given named numbers, so they are
+// described in the output.
+const kerberosModule = `
+KerberosV5Spec2 DEFINITIONS EXPLICIT TAGS ::= BEGIN
+
+Int32 ::= INTEGER (-2147483648..2147483647)
+
+UInt32 ::= INTEGER (0..4294967295)
+
+Microseconds ::= INTEGER (0..999999)
+
+KerberosString ::= GeneralString (IA5String)
+
+Realm ::= KerberosString
+
+PrincipalName ::= SEQUENCE {
+  name-type   [0] NameType,
+  name-string [1] SEQUENCE OF KerberosString }
+
+-- RFC 4120, section 6.2.
+NameType ::= INTEGER {
+  nt-unknown(0), nt-principal(1), nt-srv-inst(2), nt-srv-hst(3),
+  nt-srv-xhst(4), nt-uid(5), nt-x500-principal(6), nt-smtp-name(7),
+  nt-enterprise(10) }
+
+KerberosTime ::= GeneralizedTime
+
+HostAddress ::= SEQUENCE {
+  addr-type [0] AddressType,
+  address   [1] OCTET STRING }
+
+-- RFC 4120, section 7.5.3.
+AddressType ::= INTEGER {
+  ipv4(2), directional(3), chaosnet(5), xns(6), iso(7),
+  decnet-phase-iv(12), appletalk-ddp(16), netbios(20), ipv6(24) }
+
+HostAddresses ::= SEQUENCE OF HostAddress
+
+AuthorizationData ::= SEQUENCE OF SEQUENCE {
+  ad-type [0] AuthorizationDataType,
+  ad-data [1] OCTET STRING }
+
+-- RFC 4120, section 7.5.4, and MS-PAC.
+AuthorizationDataType ::= INTEGER {
+  ad-if-relevant(1), ad-intended-for-server(2),
+  ad-intended-for-application-class(3), ad-kdc-issued(4), ad-and-or(5),
+  ad-mandatory-ticket-extensions(6), ad-in-ticket-extensions(7),
+  ad-mandatory-for-kdc(8), ad-win2k-pac(128) }
+
+PA-DATA ::= SEQUENCE {
+  padata-type  [1] PADataType,
+  padata-value [2] OCTET STRING }
+
+-- RFC 4120, section 7.5.2, RFC 4556, RFC 6113, and MS-KILE.
+PADataType ::= INTEGER {
+  pa-tgs-req(1), pa-enc-timestamp(2), pa-pw-salt(3), pa-etype-info(11),
+  pa-pk-as-req-old(14), pa-pk-as-rep-old(15), pa-pk-as-req(16),
+  pa-pk-as-rep(17), pa-etype-info2(19), pa-pac-request(128),
+  pa-for-user(129), pa-fx-cookie(133), pa-fx-fast(136), pa-fx-error(137),
+  pa-encrypted-challenge(138), pa-req-enc-pa-rep(149),
+  pa-pac-options(167) }
+
+KerberosFlags ::= BIT STRING (SIZE (32..MAX))
+
+TicketFlags ::= KerberosFlags
+
+KDCOptions ::= KerberosFlags
+
+APOptions ::= KerberosFlags
+
+EncryptedData ::= SEQUENCE {
+  etype  [0] EncryptionType,
+  kvno   [1] UInt32 OPTIONAL,
+  cipher [2] OCTET STRING }
+
+EncryptionKey ::= SEQUENCE {
+  keytype  [0] EncryptionType,
+  keyvalue [1] OCTET STRING }
+
+Checksum ::= SEQUENCE {
+  cksumtype [0] ChecksumType,
+  checksum  [1] OCTET STRING }
+
+-- RFC 3961, section 8, RFC 3962, RFC 4757, RFC 6803, and RFC 8009.
+EncryptionType ::= INTEGER {
+  des-cbc-crc(1), des-cbc-md4(2), des-cbc-md5(3), des3-cbc-sha1-kd(16),
+  aes128-cts-hmac-sha1-96(17), aes256-cts-hmac-sha1-96(18),
+  aes128-cts-hmac-sha256-128(19), aes256-cts-hmac-sha384-192(20),
+  rc4-hmac(23), rc4-hmac-exp(24), camellia128-cts-cmac(25),
+  camellia256-cts-cmac(26) }
+
+-- RFC 3961, section 8, RFC 3962, RFC 4757, RFC 6803, and RFC 8009.
+ChecksumType ::= INTEGER {
+  crc32(1), rsa-md4(2), rsa-md4-des(3), des-mac(4), des-mac-k(5),
+  rsa-md4-des-k(6), rsa-md5(7), rsa-md5-des(8), hmac-sha1-des3-kd(12),
+  hmac-sha1-96-aes128(15), hmac-sha1-96-aes256(16), cmac-camellia128(17),
+  cmac-camellia256(18), hmac-sha256-128-aes128(19),
+  hmac-sha384-192-aes256(20), hmac-md5(-138) }
+
+MessageType ::= INTEGER {
+  krb-as-req(10), krb-as-rep(11), krb-tgs-req(12), krb-tgs-rep(13),
+  krb-ap-req(14), krb-ap-rep(15), krb-safe(20), krb-priv(21),
+  krb-cred(22), krb-error(30) }
+
+Ticket ::= [APPLICATION 1] SEQUENCE {
+  tkt-vno  [0] INTEGER (5),
+  realm    [1] Realm,
+  sname    [2] PrincipalName,
+  enc-part [3] EncryptedData }
+
+EncTicketPart ::= [APPLICATION 3] SEQUENCE {
+  flags              [0] TicketFlags,
+  key                [1] EncryptionKey,
+  crealm             [2] Realm,
+  cname              [3] PrincipalName,
+  transited          [4] TransitedEncoding,
+  authtime           [5] KerberosTime,
+  starttime          [6] KerberosTime OPTIONAL,
+  endtime            [7] KerberosTime,
+  renew-till         [8] KerberosTime OPTIONAL,
+  caddr              [9] HostAddresses OPTIONAL,
+  authorization-data [10] AuthorizationData OPTIONAL }
+
+TransitedEncoding ::= SEQUENCE {
+  tr-type  [0] Int32,
+  contents [1] OCTET STRING }
+
+AS-REQ ::= [APPLICATION 10] KDC-REQ
+
+TGS-REQ ::= [APPLICATION 12] KDC-REQ
+
+KDC-REQ ::= SEQUENCE {
+  pvno     [1] INTEGER (5),
+  msg-type [2] MessageType,
+  padata   [3] SEQUENCE OF PA-DATA OPTIONAL,
+  req-body [4] KDC-REQ-BODY }
+
+KDC-REQ-BODY ::= SEQUENCE {
+  kdc-options             [0] KDCOptions,
+  cname                   [1] PrincipalName OPTIONAL,
+  realm                   [2] Realm,
+  sname                   [3] PrincipalName OPTIONAL,
+  from                    [4] KerberosTime OPTIONAL,
+  till                    [5] KerberosTime,
+  rtime                   [6] KerberosTime OPTIONAL,
+  nonce                   [7] UInt32,
+  etype                   [8] SEQUENCE OF EncryptionType,
+  addresses               [9] HostAddresses OPTIONAL,
+  enc-authorization-data  [10] EncryptedData OPTIONAL,
+  additional-tickets      [11] SEQUENCE OF Ticket OPTIONAL }
+
+AS-REP ::= [APPLICATION 11] KDC-REP
+
+TGS-REP ::= [APPLICATION 13] KDC-REP
+
+KDC-REP ::= SEQUENCE {
+  pvno     [0] INTEGER (5),
+  msg-type [1] MessageType,
+  padata   [2] SEQUENCE OF PA-DATA OPTIONAL,
+  crealm   [3] Realm,
+  cname    [4] PrincipalName,
+  ticket   [5] Ticket,
+  enc-part [6] EncryptedData }
+
+EncASRepPart ::= [APPLICATION 25] EncKDCRepPart
+
+EncTGSRepPart ::= [APPLICATION 26] EncKDCRepPart
+
+EncKDCRepPart ::= SEQUENCE {
+  key            [0] EncryptionKey,
+  last-req       [1] LastReq,
+  nonce          [2] UInt32,
+  key-expiration [3] KerberosTime OPTIONAL,
+  flags          [4] TicketFlags,
+  authtime       [5] KerberosTime,
+  starttime      [6] KerberosTime OPTIONAL,
+  endtime        [7] KerberosTime,
+  renew-till     [8] KerberosTime OPTIONAL,
+  srealm         [9] Realm,
+  sname          [10] PrincipalName,
+  caddr          [11] HostAddresses OPTIONAL }
+
+LastReq ::= SEQUENCE OF SEQUENCE {
+  lr-type  [0] Int32,
+  lr-value [1] KerberosTime }
+
+AP-REQ ::= [APPLICATION 14] SEQUENCE {
+  pvno          [0] INTEGER (5),
+  msg-type      [1] MessageType,
+  ap-options    [2] APOptions,
+  ticket        [3] Ticket,
+  authenticator [4] EncryptedData }
+
+Authenticator ::= [APPLICATION 2] SEQUENCE {
+  authenticator-vno  [0] INTEGER (5),
+  crealm             [1] Realm,
+  cname              [2] PrincipalName,
+  cksum              [3] Checksum OPTIONAL,
+  cusec              [4] Microseconds,
+  ctime              [5] KerberosTime,
+  subkey             [6] EncryptionKey OPTIONAL,
+  seq-number         [7] UInt32 OPTIONAL,
+  authorization-data [8] AuthorizationData OPTIONAL }
+
+AP-REP ::= [APPLICATION 15] SEQUENCE {
+  pvno     [0] INTEGER (5),
+  msg-type [1] MessageType,
+  enc-part [2] EncryptedData }
+
+EncAPRepPart ::= [APPLICATION 27] SEQUENCE {
+  ctime      [0] KerberosTime,
+  cusec      [1] Microseconds,
+  subkey     [2] EncryptionKey OPTIONAL,
+  seq-number [3] UInt32 OPTIONAL }
+
+KRB-SAFE ::= [APPLICATION 20] SEQUENCE {
+  pvno      [0] INTEGER (5),
+  msg-type  [1] MessageType,
+  safe-body [2] KRB-SAFE-BODY,
+  cksum     [3] Checksum }
+
+KRB-SAFE-BODY ::= SEQUENCE {
+  user-data  [0] OCTET STRING,
+  timestamp  [1] KerberosTime OPTIONAL,
+  usec       [2] Microseconds OPTIONAL,
+  seq-number [3] UInt32 OPTIONAL,
+  s-address  [4] HostAddress,
+  r-address  [5] HostAddress OPTIONAL }
+
+KRB-PRIV ::= [APPLICATION 21] SEQUENCE {
+  pvno     [0] INTEGER (5),
+  msg-type [1] MessageType,
+  enc-part [3] EncryptedData }
+
+EncKrbPrivPart ::= [APPLICATION 28] SEQUENCE {
+  user-data  [0] OCTET STRING,
+  timestamp  [1] KerberosTime OPTIONAL,
+  usec       [2] Microseconds OPTIONAL,
+  seq-number [3] UInt32 OPTIONAL,
+  s-address  [4] HostAddress,
+  r-address  [5] HostAddress OPTIONAL }
+
+KRB-CRED ::= [APPLICATION 22] SEQUENCE {
+  pvno     [0] INTEGER (5),
+  msg-type [1] MessageType,
+  tickets  [2] SEQUENCE OF Ticket,
+  enc-part [3] EncryptedData }
+
+EncKrbCredPart ::= [APPLICATION 29] SEQUENCE {
+  ticket-info [0] SEQUENCE OF KrbCredInfo,
+  nonce       [1] UInt32 OPTIONAL,
+  timestamp   [2] KerberosTime OPTIONAL,
+  usec        [3] Microseconds OPTIONAL,
+  s-address   [4] HostAddress OPTIONAL,
+  r-address   [5] HostAddress OPTIONAL }
+
+KrbCredInfo ::= SEQUENCE {
+  key        [0] EncryptionKey,
+  prealm     [1] Realm OPTIONAL,
+  pname      [2] PrincipalName OPTIONAL,
+  flags      [3] TicketFlags OPTIONAL,
+  authtime   [4] KerberosTime OPTIONAL,
+  starttime  [5] KerberosTime OPTIONAL,
+  endtime    [6] KerberosTime OPTIONAL,
+  renew-till [7] KerberosTime OPTIONAL,
+  srealm     [8] Realm OPTIONAL,
+  sname      [9] PrincipalName OPTIONAL,
+  caddr      [10] HostAddresses OPTIONAL }
+
+KRB-ERROR ::= [APPLICATION 30] SEQUENCE {
+  pvno       [0] INTEGER (5),
+  msg-type   [1] MessageType,
+  ctime      [2] KerberosTime OPTIONAL,
+  cusec      [3] Microseconds OPTIONAL,
+  stime      [4] KerberosTime,
+  susec      [5] Microseconds,
+  error-code [6] ErrorCode,
+  crealm     [7] Realm OPTIONAL,
+  cname      [8] PrincipalName OPTIONAL,
+  realm      [9] Realm,
+  sname      [10] PrincipalName,
+  e-text     [11] KerberosString OPTIONAL,
+  e-data     [12] OCTET STRING OPTIONAL }
+
+-- RFC 4120, section 7.5.9, and RFC 4556, section 3.1.3.
+ErrorCode ::= INTEGER {
+  kdc-err-none(0), kdc-err-name-exp(1), kdc-err-service-exp(2),
+  kdc-err-bad-pvno(3), kdc-err-c-old-mast-kvno(4),
+  kdc-err-s-old-mast-kvno(5), kdc-err-c-principal-unknown(6),
+  kdc-err-s-principal-unknown(7), kdc-err-principal-not-unique(8),
+  kdc-err-null-key(9), kdc-err-cannot-postdate(10),
+  kdc-err-never-valid(11), kdc-err-policy(12), kdc-err-badoption(13),
+  kdc-err-etype-nosupp(14), kdc-err-sumtype-nosupp(15),
+  kdc-err-padata-type-nosupp(16), kdc-err-trtype-nosupp(17),
+  kdc-err-client-revoked(18), kdc-err-service-revoked(19),
+  kdc-err-tgt-revoked(20), kdc-err-client-notyet(21),
+  kdc-err-service-notyet(22), kdc-err-key-expired(23),
+  kdc-err-preauth-failed(24), kdc-err-preauth-required(25),
+  kdc-err-server-nomatch(26), kdc-err-must-use-user2user(27),
+  kdc-err-path-not-accepted(28), kdc-err-svc-unavailable(29),
+  krb-ap-err-bad-integrity(31), krb-ap-err-tkt-expired(32),
+  krb-ap-err-tkt-nyv(33), krb-ap-err-repeat(34), krb-ap-err-not-us(35),
+  krb-ap-err-badmatch(36), krb-ap-err-skew(37), krb-ap-err-badaddr(38),
+  krb-ap-err-badversion(39), krb-ap-err-msg-type(40),
+  krb-ap-err-modified(41), krb-ap-err-badorder(42),
+  krb-ap-err-badkeyver(44), krb-ap-err-nokey(45),
+  krb-ap-err-mut-fail(46), krb-ap-err-baddirection(47),
+  krb-ap-err-method(48), krb-ap-err-badseq(49),
+  krb-ap-err-inapp-cksum(50), krb-ap-path-not-accepted(51),
+  krb-err-response-too-big(52), krb-err-generic(60),
+  krb-err-field-toolong(61), kdc-error-client-not-trusted(62),
+  kdc-error-kdc-not-trusted(63), kdc-error-invalid-sig(64),
+  kdc-err-key-too-weak(65), kdc-err-certificate-mismatch(66),
+  krb-ap-err-no-tgt(67), kdc-err-wrong-realm(68),
+  krb-ap-err-user-to-user-required(69),
+  kdc-err-cant-verify-certificate(70),
+  kdc-err-invalid-certificate(71), kdc-err-revoked-certificate(72),
+  kdc-err-revocation-status-unknown(73),
+  kdc-err-revocation-status-unavailable(74),
+  kdc-err-client-name-mismatch(75), kdc-err-kdc-name-mismatch(76) }
+
+METHOD-DATA ::= SEQUENCE OF PA-DATA
+
+PA-ENC-TS-ENC ::= SEQUENCE {
+  patimestamp [0] KerberosTime,
+  pausec      [1] Microseconds OPTIONAL }
+
+ETYPE-INFO2-ENTRY ::= SEQUENCE {
+  etype     [0] EncryptionType,
+  salt      [1] KerberosString OPTIONAL,
+  s2kparams [2] OCTET STRING OPTIONAL }
+
+ETYPE-INFO2 ::= SEQUENCE SIZE (1..MAX) OF ETYPE-INFO2-ENTRY
+
+END
+`
+
+// kerberosDescribers describes Kerberos flags and times.
+var kerberosDescribers = map[string]func(element) string{
+	"TicketFlags":  namedBitsDescriber("ticketFlags"),
+	"KDCOptions":   namedBitsDescriber("kdcOptions"),
+	"APOptions":    namedBitsDescriber("apOptions"),
+	"KerberosTime": describeTime,
+}
+
+var kerberosProfile = &profile{
+	modules: []string{kerberosModule},
+	roots: []string{
+		"AS-REQ", "AS-REP", "TGS-REQ", "TGS-REP", "AP-REQ", "AP-REP",
+		"KRB-SAFE", "KRB-PRIV", "KRB-CRED", "KRB-ERROR", "Ticket",
+		"Authenticator", "EncTicketPart", "EncASRepPart", "EncTGSRepPart",
+		"EncAPRepPart", "EncKrbPrivPart", "EncKrbCredPart",
+	},
+	describers: kerberosDescribers,
+	vocabulary: "kerberos",
+}
@@ -0,0 +1,260 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package der2ascii
+
+// ldapModule is the module in RFC 4511, appendix B. COMPONENTS OF is not
+// supported, so the components of LDAPResult are written out in
+// BindResponse and ExtendedResponse, and the result codes are given their own
+// type.
+const ldapModule = `
+Lightweight-Directory-Access-Protocol-V3 {1 3 6 1 1 18}
+DEFINITIONS IMPLICIT TAGS EXTENSIBILITY IMPLIED ::= BEGIN
+
+LDAPMessage ::= SEQUENCE {
+  messageID  MessageID,
+  protocolOp CHOICE {
+    bindRequest          BindRequest,
+    bindResponse         BindResponse,
+    unbindRequest        UnbindRequest,
+    searchRequest        SearchRequest,
+    searchResEntry       SearchResultEntry,
+    searchResDone        SearchResultDone,
+    searchResRef         SearchResultReference,
+    modifyRequest        ModifyRequest,
+    modifyResponse       ModifyResponse,
+    addRequest           AddRequest,
+    addResponse          AddResponse,
+    delRequest           DelRequest,
+    delResponse          DelResponse,
+    modDNRequest         ModifyDNRequest,
+    modDNResponse        ModifyDNResponse,
+    compareRequest       CompareRequest,
+    compareResponse      CompareResponse,
+    abandonRequest       AbandonRequest,
+    extendedReq          ExtendedRequest,
+    extendedResp         ExtendedResponse,
+    ...,
+    intermediateResponse IntermediateResponse },
+  controls   [0] Controls OPTIONAL }
+
+MessageID ::= INTEGER (0..maxInt)
+
+maxInt INTEGER ::= 2147483647
+
+LDAPString ::= OCTET STRING
+
+LDAPOID ::= OCTET STRING
+
+LDAPDN ::= LDAPString
+
+RelativeLDAPDN ::= LDAPString
+
+AttributeDescription ::= LDAPString
+
+AttributeValue ::= OCTET STRING
+
+AttributeValueAssertion ::= SEQUENCE {
+  attributeDesc  AttributeDescription,
+  assertionValue AssertionValue }
+
+AssertionValue ::= OCTET STRING
+
+PartialAttribute ::= SEQUENCE {
+  type AttributeDescription,
+  vals SET OF value AttributeValue }
+
+Attribute ::= PartialAttribute (WITH COMPONENTS { ..., vals (SIZE(1..MAX)) })
+
+MatchingRuleId ::= LDAPString
+
+LDAPResult ::= SEQUENCE {
+  resultCode        ResultCode,
+  matchedDN         LDAPDN,
+  diagnosticMessage LDAPString,
+  referral          [3] Referral OPTIONAL }
+
+ResultCode ::= ENUMERATED {
+  success(0), operationsError(1), protocolError(2),
+  timeLimitExceeded(3), sizeLimitExceeded(4), compareFalse(5),
+  compareTrue(6), authMethodNotSupported(7), strongerAuthRequired(8),
+  referral(10), adminLimitExceeded(11),
+  unavailableCriticalExtension(12), confidentialityRequired(13),
+  saslBindInProgress(14), noSuchAttribute(16),
+  undefinedAttributeType(17), inappropriateMatching(18),
+  constraintViolation(19), attributeOrValueExists(20),
+  invalidAttributeSyntax(21), noSuchObject(32), aliasProblem(33),
+  invalidDNSyntax(34), aliasDereferencingProblem(36),
+  inappropriateAuthentication(48), invalidCredentials(49),
+  insufficientAccessRights(50), busy(51), unavailable(52),
+  unwillingToPerform(53), loopDetect(54), namingViolation(64),
+  objectClassViolation(65), notAllowedOnNonLeaf(66),
+  notAllowedOnRDN(67), entryAlreadyExists(68),
+  objectClassModsProhibited(69), affectsMultipleDSAs(71), other(80),
+  ... }
+
+Referral ::= SEQUENCE SIZE (1..MAX) OF uri URI
+
+URI ::= LDAPString
+
+Controls ::= SEQUENCE OF control Control
+
+Control ::= SEQUENCE {
+  controlType  LDAPOID,
+  criticality  BOOLEAN DEFAULT FALSE,
+  controlValue OCTET STRING OPTIONAL }
+
+BindRequest ::= [APPLICATION 0] SEQUENCE {
+  version        INTEGER (1..127),
+  name           LDAPDN,
+  authentication AuthenticationChoice }
+
+AuthenticationChoice ::= CHOICE {
+  simple [0] OCTET STRING,
+  sasl   [3] SaslCredentials,
+  ... }
+
+SaslCredentials ::= SEQUENCE {
+  mechanism   LDAPString,
+  credentials OCTET STRING OPTIONAL }
+
+BindResponse ::= [APPLICATION 1] SEQUENCE {
+  resultCode        ResultCode,
+  matchedDN         LDAPDN,
+  diagnosticMessage LDAPString,
+  referral          [3] Referral OPTIONAL,
+  serverSaslCreds   [7] OCTET STRING OPTIONAL }
+
+UnbindRequest ::= [APPLICATION 2] NULL
+
+SearchRequest ::= [APPLICATION 3] SEQUENCE {
+  baseObject   LDAPDN,
+  scope        ENUMERATED {
+    baseObject(0), singleLevel(1), wholeSubtree(2), ... },
+  derefAliases ENUMERATED {
+    neverDerefAliases(0), derefInSearching(1),
+    derefFindingBaseObj(2), derefAlways(3) },
+  sizeLimit    INTEGER (0..maxInt),
+  timeLimit    INTEGER (0..maxInt),
+  typesOnly    BOOLEAN,
+  filter       Filter,
+  attributes   AttributeSelection }
+
+AttributeSelection ::= SEQUENCE OF selector LDAPString
+
+Filter ::= CHOICE {
+  and             [0] SET SIZE (1..MAX) OF filter Filter,
+  or              [1] SET SIZE (1..MAX) OF filter Filter,
+  not             [2] Filter,
+  equalityMatch   [3] AttributeValueAssertion,
+  substrings      [4] SubstringFilter,
+  greaterOrEqual  [5] AttributeValueAssertion,
+  lessOrEqual     [6] AttributeValueAssertion,
+  present         [7] AttributeDescription,
+  approxMatch     [8] AttributeValueAssertion,
+  extensibleMatch [9] MatchingRuleAssertion,
+  ... }
+
+SubstringFilter ::= SEQUENCE {
+  type       AttributeDescription,
+  substrings SEQUENCE SIZE (1..MAX) OF substring CHOICE {
+    initial [0] AssertionValue,
+    any     [1] AssertionValue,
+    final   [2] AssertionValue } }
+
+MatchingRuleAssertion ::= SEQUENCE {
+  matchingRule [1] MatchingRuleId OPTIONAL,
+  type         [2] AttributeDescription OPTIONAL,
+  matchValue   [3] AssertionValue,
+  dnAttributes [4] BOOLEAN DEFAULT FALSE }
+
+SearchResultEntry ::= [APPLICATION 4] SEQUENCE {
+  objectName LDAPDN,
+  attributes PartialAttributeList }
+
+PartialAttributeList ::= SEQUENCE OF partialAttribute PartialAttribute
+
+SearchResultReference ::= [APPLICATION 19] SEQUENCE SIZE (1..MAX) OF uri URI
+
+SearchResultDone ::= [APPLICATION 5] LDAPResult
+
+ModifyRequest ::= [APPLICATION 6] SEQUENCE {
+  object  LDAPDN,
+  changes SEQUENCE OF change SEQUENCE {
+    operation    ENUMERATED { add(0), delete(1), replace(2), ... },
+    modification PartialAttribute } }
+
+ModifyResponse ::= [APPLICATION 7] LDAPResult
+
+AddRequest ::= [APPLICATION 8] SEQUENCE {
+  entry      LDAPDN,
+  attributes AttributeList }
+
+AttributeList ::= SEQUENCE OF attribute Attribute
+
+AddResponse ::= [APPLICATION 9] LDAPResult
+
+DelRequest ::= [APPLICATION 10] LDAPDN
+
+DelResponse ::= [APPLICATION 11] LDAPResult
+
+ModifyDNRequest ::= [APPLICATION 12] SEQUENCE {
+  entry        LDAPDN,
+  newrdn       RelativeLDAPDN,
+  deleteoldrdn BOOLEAN,
+  newSuperior  [0] LDAPDN OPTIONAL }
+
+ModifyDNResponse ::= [APPLICATION 13] LDAPResult
+
+CompareRequest ::= [APPLICATION 14] SEQUENCE {
+  entry LDAPDN,
+  ava   AttributeValueAssertion }
+
+CompareResponse ::= [APPLICATION 15] LDAPResult
+
+AbandonRequest ::= [APPLICATION 16] MessageID
+
+ExtendedRequest ::= [APPLICATION 23] SEQUENCE {
+  requestName  [0] LDAPOID,
+  requestValue [1] OCTET STRING OPTIONAL }
+
+ExtendedResponse ::= [APPLICATION 24] SEQUENCE {
+  resultCode        ResultCode,
+  matchedDN         LDAPDN,
+  diagnosticMessage LDAPString,
+  referral          [3] Referral OPTIONAL,
+  responseName      [10] LDAPOID OPTIONAL,
+  responseValue     [11] OCTET STRING OPTIONAL }
+
+IntermediateResponse ::= [APPLICATION 25] SEQUENCE {
+  responseName  [0] LDAPOID OPTIONAL,
+  responseValue [1] OCTET STRING OPTIONAL }
+
+END
+`
+
+// describeLDAPOID describes elem, an LDAPOID, by the name of the OID it
+// contains, if known.
+func describeLDAPOID(elem element) string {
+	name, _ := dottedOIDToName(string(elem.body))
+	return name
+}
+
+var ldapProfile = &profile{
+	modules:    []string{ldapModule},
+	roots:      []string{"LDAPMessage"},
+	describers: map[string]func(element) string{"LDAPOID": describeLDAPOID},
+	repeated:   true,
+	vocabulary: "ldap",
+}
@@ -0,0 +1,153 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package der2ascii
+
+// pkcs12Module is a subset of the modules in RFC 7292, RFC 5208, and RFC
+// 8018, covering PKCS #12, PKCS #8, and password-based encryption. It refers
+// to types in x509Module and cmsModule.
+const pkcs12Module = `
+PKCS-12 DEFINITIONS IMPLICIT TAGS ::= BEGIN
+
+PFX ::= SEQUENCE {
+  version     INTEGER { v3(3) },
+  authSafe    AuthSafeContentInfo,
+  macData     MacData OPTIONAL }
+
+-- AuthSafeContentInfo is a ContentInfo containing the AuthenticatedSafe.
+AuthSafeContentInfo ::= SEQUENCE {
+  contentType ContentType,
+  content [0] EXPLICIT ANY DEFINED BY contentType }
+
+MacData ::= SEQUENCE {
+  mac         DigestInfo,
+  macSalt     OCTET STRING,
+  iterations  INTEGER DEFAULT 1 }
+
+DigestInfo ::= SEQUENCE {
+  digestAlgorithm DigestAlgorithmIdentifier,
+  digest OCTET STRING }
+
+AuthenticatedSafe ::= SEQUENCE OF ContentInfo
+
+SafeContents ::= SEQUENCE OF SafeBag
+
+SafeBag ::= SEQUENCE {
+  bagId          OBJECT IDENTIFIER,
+  bagValue       [0] EXPLICIT ANY DEFINED BY bagId,
+  bagAttributes  SET OF Attribute OPTIONAL }
+
+CertBag ::= SEQUENCE {
+  certId      OBJECT IDENTIFIER,
+  certValue   [0] EXPLICIT ANY DEFINED BY certId }
+
+CRLBag ::= SEQUENCE {
+  crlId       OBJECT IDENTIFIER,
+  crlValue    [0] EXPLICIT ANY DEFINED BY crlId }
+
+SecretBag ::= SEQUENCE {
+  secretTypeId  OBJECT IDENTIFIER,
+  secretValue   [0] EXPLICIT ANY DEFINED BY secretTypeId }
+
+PKCS12PbeParams ::= SEQUENCE {
+  salt        OCTET STRING,
+  iterations  INTEGER }
+
+FriendlyName ::= BMPString
+
+LocalKeyId ::= OCTET STRING
+
+END
+
+PKCS-8 DEFINITIONS IMPLICIT TAGS ::= BEGIN
+
+PrivateKeyInfo ::= SEQUENCE {
+  version                   INTEGER { v1(0), v2(1) },
+  privateKeyAlgorithm       AlgorithmIdentifier,
+  privateKey                OCTET STRING,
+  attributes           [0]  SET OF Attribute OPTIONAL,
+  publicKey            [1]  BIT STRING OPTIONAL }
+
+EncryptedPrivateKeyInfo ::= SEQUENCE {
+  encryptionAlgorithm  AlgorithmIdentifier,
+  encryptedData        OCTET STRING }
+
+END
+
+PKCS-5 DEFINITIONS EXPLICIT TAGS ::= BEGIN
+
+PBEParameter ::= SEQUENCE {
+  salt OCTET STRING (SIZE(8)),
+  iterationCount INTEGER }
+
+PBES2-params ::= SEQUENCE {
+  keyDerivationFunc AlgorithmIdentifier,
+  encryptionScheme AlgorithmIdentifier }
+
+PBKDF2-params ::= SEQUENCE {
+  salt CHOICE {
+    specified OCTET STRING,
+    otherSource AlgorithmIdentifier },
+  iterationCount INTEGER (1..MAX),
+  keyLength INTEGER (1..MAX) OPTIONAL,
+  prf AlgorithmIdentifier DEFAULT algid-hmacWithSHA1 }
+
+END
+`
+
+// pkcs12DefinedBy maps PKCS #12 bag types, attributes, and password-based
+// encryption algorithms to their types.
+var pkcs12DefinedBy = map[string]string{
+	// The data in the outer ContentInfo contains the AuthenticatedSafe,
+	// and the data in each of its ContentInfos contains SafeContents.
+	"AuthSafeContentInfo 1.2.840.113549.1.7.1":     "OCTET STRING (CONTAINING AuthenticatedSafe)",
+	"AuthSafeContentInfo 1.2.840.113549.1.7.2":     "SignedData",
+	"EncapsulatedContentInfo 1.2.840.113549.1.7.1": "AuthenticatedSafe",
+	"ContentInfo 1.2.840.113549.1.7.1":             "OCTET STRING (CONTAINING SafeContents)",
+	// Bag types.
+	"1.2.840.113549.1.12.10.1.1": "PrivateKeyInfo",
+	"1.2.840.113549.1.12.10.1.2": "EncryptedPrivateKeyInfo",
+	"1.2.840.113549.1.12.10.1.3": "CertBag",
+	"1.2.840.113549.1.12.10.1.4": "CRLBag",
+	"1.2.840.113549.1.12.10.1.5": "SecretBag",
+	"1.2.840.113549.1.12.10.1.6": "SafeContents",
+	// Certificate and CRL types.
+	"1.2.840.113549.1.9.22.1": "OCTET STRING (CONTAINING Certificate)",
+	"1.2.840.113549.1.9.22.2": "IA5String",
+	"1.2.840.113549.1.9.23.1": "OCTET STRING (CONTAINING CertificateList)",
+	// Attributes.
+	"1.2.840.113549.1.9.20": "FriendlyName",
+	"1.2.840.113549.1.9.21": "LocalKeyId",
+	// PKCS #12 password-based encryption.
+	"1.2.840.113549.1.12.1.1": "PKCS12PbeParams",
+	"1.2.840.113549.1.12.1.2": "PKCS12PbeParams",
+	"1.2.840.113549.1.12.1.3": "PKCS12PbeParams",
+	"1.2.840.113549.1.12.1.4": "PKCS12PbeParams",
+	"1.2.840.113549.1.12.1.5": "PKCS12PbeParams",
+	"1.2.840.113549.1.12.1.6": "PKCS12PbeParams",
+	// PKCS #5 password-based encryption.
+	"1.2.840.113549.1.5.3":  "PBEParameter",
+	"1.2.840.113549.1.5.6":  "PBEParameter",
+	"1.2.840.113549.1.5.10": "PBEParameter",
+	"1.2.840.113549.1.5.11": "PBEParameter",
+	"1.2.840.113549.1.5.12": "PBKDF2-params",
+	"1.2.840.113549.1.5.13": "PBES2-params",
+}
+
+var pkcs12Profile = &profile{
+	modules:    []string{x509Module, cmsModule, pkcs12Module},
+	roots:      []string{"PFX", "EncryptedPrivateKeyInfo", "PrivateKeyInfo", "SafeContents"},
+	definedBy:  mergeDefinedBy(x509DefinedBy, cmsDefinedBy, pkcs12DefinedBy),
+	describers: x509Describers,
+}
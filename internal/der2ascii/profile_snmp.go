@@ -0,0 +1,270 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package der2ascii
+
+import (
+	"net"
+	"strings"
+)
+
+// snmpModule combines the message formats of SNMPv1 (RFC 1157), SNMPv2c (RFC
+// 1901), and SNMPv3 (RFC 3412 and RFC 3414) with the PDUs in RFC 3416 and the
+// SNMPv1 Trap-PDU.
+const snmpModule = `
+SNMPv2-PDU DEFINITIONS IMPLICIT TAGS ::= BEGIN
+
+-- RFC 1157 and RFC 1901.
+Message ::= SEQUENCE {
+  version   INTEGER { version-1(0), version-2c(1) },
+  community OCTET STRING,
+  data      PDUs }
+
+ObjectName ::= OBJECT IDENTIFIER
+
+ObjectSyntax ::= CHOICE {
+  simple           SimpleSyntax,
+  application-wide ApplicationSyntax }
+
+SimpleSyntax ::= CHOICE {
+  integer-value  INTEGER (-2147483648..2147483647),
+  string-value   OCTET STRING (SIZE (0..65535)),
+  objectID-value OBJECT IDENTIFIER }
+
+ApplicationSyntax ::= CHOICE {
+  ipAddress-value        IpAddress,
+  counter-value          Counter32,
+  timeticks-value        TimeTicks,
+  arbitrary-value        Opaque,
+  big-counter-value      Counter64,
+  unsigned-integer-value Unsigned32 }
+
+IpAddress ::= [APPLICATION 0] IMPLICIT OCTET STRING (SIZE (4))
+
+Counter32 ::= [APPLICATION 1] IMPLICIT INTEGER (0..4294967295)
+
+Unsigned32 ::= [APPLICATION 2] IMPLICIT INTEGER (0..4294967295)
+
+Gauge32 ::= Unsigned32
+
+TimeTicks ::= [APPLICATION 3] IMPLICIT INTEGER (0..4294967295)
+
+Opaque ::= [APPLICATION 4] IMPLICIT OCTET STRING
+
+Counter64 ::= [APPLICATION 6] IMPLICIT INTEGER (0..18446744073709551615)
+
+PDUs ::= CHOICE {
+  get-request      GetRequest-PDU,
+  get-next-request GetNextRequest-PDU,
+  get-bulk-request GetBulkRequest-PDU,
+  response         Response-PDU,
+  set-request      SetRequest-PDU,
+  inform-request   InformRequest-PDU,
+  snmpV2-trap      SNMPv2-Trap-PDU,
+  report           Report-PDU,
+  trap             Trap-PDU }
+
+GetRequest-PDU ::= [0] IMPLICIT PDU
+
+GetNextRequest-PDU ::= [1] IMPLICIT PDU
+
+Response-PDU ::= [2] IMPLICIT PDU
+
+SetRequest-PDU ::= [3] IMPLICIT PDU
+
+GetBulkRequest-PDU ::= [5] IMPLICIT BulkPDU
+
+InformRequest-PDU ::= [6] IMPLICIT PDU
+
+SNMPv2-Trap-PDU ::= [7] IMPLICIT PDU
+
+Report-PDU ::= [8] IMPLICIT PDU
+
+max-bindings INTEGER ::= 2147483647
+
+PDU ::= SEQUENCE {
+  request-id        INTEGER (-2147483648..2147483647),
+  error-status      INTEGER {
+    noError(0), tooBig(1), noSuchName(2), badValue(3), readOnly(4),
+    genErr(5), noAccess(6), wrongType(7), wrongLength(8),
+    wrongEncoding(9), wrongValue(10), noCreation(11),
+    inconsistentValue(12), resourceUnavailable(13), commitFailed(14),
+    undoFailed(15), authorizationError(16), notWritable(17),
+    inconsistentName(18) },
+  error-index       INTEGER (0..max-bindings),
+  variable-bindings VarBindList }
+
+BulkPDU ::= SEQUENCE {
+  request-id        INTEGER (-2147483648..2147483647),
+  non-repeaters     INTEGER (0..max-bindings),
+  max-repetitions   INTEGER (0..max-bindings),
+  variable-bindings VarBindList }
+
+-- The CHOICE is unnamed in RFC 3416.
+VarBind ::= SEQUENCE {
+  name  ObjectName,
+  value CHOICE {
+    value          ObjectSyntax,
+    unSpecified    NULL,
+    noSuchObject   [0] IMPLICIT NULL,
+    noSuchInstance [1] IMPLICIT NULL,
+    endOfMibView   [2] IMPLICIT NULL } }
+
+VarBindList ::= SEQUENCE (SIZE (0..max-bindings)) OF VarBind
+
+-- RFC 1157.
+Trap-PDU ::= [4] IMPLICIT SEQUENCE {
+  enterprise        OBJECT IDENTIFIER,
+  agent-addr        NetworkAddress,
+  generic-trap      INTEGER {
+    coldStart(0), warmStart(1), linkDown(2), linkUp(3),
+    authenticationFailure(4), egpNeighborLoss(5),
+    enterpriseSpecific(6) },
+  specific-trap     INTEGER,
+  time-stamp        TimeTicks,
+  variable-bindings VarBindList }
+
+NetworkAddress ::= CHOICE {
+  internet IpAddress }
+
+END
+
+SNMPv3MessageSyntax DEFINITIONS IMPLICIT TAGS ::= BEGIN
+
+-- The security parameters are assumed to use the user-based security model.
+SNMPv3Message ::= SEQUENCE {
+  msgVersion            INTEGER { snmpv3(3) } (0..2147483647),
+  msgGlobalData         HeaderData,
+  msgSecurityParameters OCTET STRING (CONTAINING UsmSecurityParameters),
+  msgData               ScopedPduData }
+
+HeaderData ::= SEQUENCE {
+  msgID            INTEGER (0..2147483647),
+  msgMaxSize       INTEGER (484..2147483647),
+  msgFlags         OCTET STRING (SIZE(1)),
+  msgSecurityModel INTEGER {
+    snmpv1(1), snmpv2c(2), usm(3) } (1..2147483647) }
+
+ScopedPduData ::= CHOICE {
+  plaintext    ScopedPDU,
+  encryptedPDU OCTET STRING }
+
+ScopedPDU ::= SEQUENCE {
+  contextEngineID OCTET STRING,
+  contextName     OCTET STRING,
+  data            PDUs }
+
+UsmSecurityParameters ::= SEQUENCE {
+  msgAuthoritativeEngineID    OCTET STRING,
+  msgAuthoritativeEngineBoots INTEGER (0..2147483647),
+  msgAuthoritativeEngineTime  INTEGER (0..2147483647),
+  msgUserName                 OCTET STRING (SIZE(0..32)),
+  msgAuthenticationParameters OCTET STRING,
+  msgPrivacyParameters        OCTET STRING }
+
+END
+`
+
+// snmpObjectNames contains names for common SNMP objects and arcs, from
+// RFC 1213, RFC 3411, RFC 3414, and RFC 3418.
+var snmpObjectNames = []struct {
+	oid  string
+	name string
+}{
+	{"1.3.6.1", "internet"},
+	{"1.3.6.1.2.1", "mib-2"},
+	{"1.3.6.1.2.1.1", "system"},
+	{"1.3.6.1.2.1.1.1", "sysDescr"},
+	{"1.3.6.1.2.1.1.2", "sysObjectID"},
+	{"1.3.6.1.2.1.1.3", "sysUpTime"},
+	{"1.3.6.1.2.1.1.4", "sysContact"},
+	{"1.3.6.1.2.1.1.5", "sysName"},
+	{"1.3.6.1.2.1.1.6", "sysLocation"},
+	{"1.3.6.1.2.1.1.7", "sysServices"},
+	{"1.3.6.1.2.1.2", "interfaces"},
+	{"1.3.6.1.2.1.2.1", "ifNumber"},
+	{"1.3.6.1.2.1.2.2.1.1", "ifIndex"},
+	{"1.3.6.1.2.1.2.2.1.2", "ifDescr"},
+	{"1.3.6.1.2.1.2.2.1.3", "ifType"},
+	{"1.3.6.1.2.1.2.2.1.4", "ifMtu"},
+	{"1.3.6.1.2.1.2.2.1.5", "ifSpeed"},
+	{"1.3.6.1.2.1.2.2.1.6", "ifPhysAddress"},
+	{"1.3.6.1.2.1.2.2.1.7", "ifAdminStatus"},
+	{"1.3.6.1.2.1.2.2.1.8", "ifOperStatus"},
+	{"1.3.6.1.2.1.2.2.1.10", "ifInOctets"},
+	{"1.3.6.1.2.1.2.2.1.16", "ifOutOctets"},
+	{"1.3.6.1.2.1.31.1.1.1.1", "ifName"},
+	{"1.3.6.1.2.1.31.1.1.1.6", "ifHCInOctets"},
+	{"1.3.6.1.2.1.31.1.1.1.10", "ifHCOutOctets"},
+	{"1.3.6.1.4.1", "enterprises"},
+	{"1.3.6.1.6.3.1.1.4.1", "snmpTrapOID"},
+	{"1.3.6.1.6.3.1.1.4.3", "snmpTrapEnterprise"},
+	{"1.3.6.1.6.3.1.1.5.1", "coldStart"},
+	{"1.3.6.1.6.3.1.1.5.2", "warmStart"},
+	{"1.3.6.1.6.3.1.1.5.3", "linkDown"},
+	{"1.3.6.1.6.3.1.1.5.4", "linkUp"},
+	{"1.3.6.1.6.3.1.1.5.5", "authenticationFailure"},
+	{"1.3.6.1.6.3.10.1.1.1", "usmNoAuthProtocol"},
+	{"1.3.6.1.6.3.10.1.1.2", "usmHMACMD5AuthProtocol"},
+	{"1.3.6.1.6.3.10.1.1.3", "usmHMACSHAAuthProtocol"},
+	{"1.3.6.1.6.3.10.1.2.1", "usmNoPrivProtocol"},
+	{"1.3.6.1.6.3.10.1.2.2", "usmDESPrivProtocol"},
+	{"1.3.6.1.6.3.10.1.2.4", "usmAesCfb128Protocol"},
+	{"1.3.6.1.6.3.10.2.1.1", "snmpEngineID"},
+	{"1.3.6.1.6.3.10.2.1.2", "snmpEngineBoots"},
+	{"1.3.6.1.6.3.10.2.1.3", "snmpEngineTime"},
+	{"1.3.6.1.6.3.15.1.1.1", "usmStatsUnsupportedSecLevels"},
+	{"1.3.6.1.6.3.15.1.1.2", "usmStatsNotInTimeWindows"},
+	{"1.3.6.1.6.3.15.1.1.3", "usmStatsUnknownUserNames"},
+	{"1.3.6.1.6.3.15.1.1.4", "usmStatsUnknownEngineIDs"},
+	{"1.3.6.1.6.3.15.1.1.5", "usmStatsWrongDigests"},
+	{"1.3.6.1.6.3.15.1.1.6", "usmStatsDecryptionErrors"},
+}
+
+// describeSNMPObjectName describes elem, an OBJECT IDENTIFIER, by the longest
+// matching entry in snmpObjectNames, followed by the remaining arcs. For
+// example, 1.3.6.1.2.1.1.3.0 is described as sysUpTime.0.
+func describeSNMPObjectName(elem element) string {
+	oid := objectIdentifierToString(elem.body)
+	var best, suffix string
+	for _, entry := range snmpObjectNames {
+		if rest, ok := strings.CutPrefix(oid, entry.oid); ok && (rest == "" || rest[0] == '.') && len(entry.oid) > len(best) {
+			best, suffix = entry.oid, entry.name+rest
+		}
+	}
+	return suffix
+}
+
+// describeIPAddress describes elem, an SNMP IpAddress, in dotted-decimal
+// notation.
+func describeIPAddress(elem element) string {
+	if len(elem.body) != net.IPv4len {
+		return ""
+	}
+	return net.IP(elem.body).String()
+}
+
+// snmpDescribers describes SNMP object names and addresses.
+var snmpDescribers = map[string]func(element) string{
+	"ObjectName":        describeSNMPObjectName,
+	"OBJECT IDENTIFIER": describeSNMPObjectName,
+	"IpAddress":         describeIPAddress,
+}
+
+var snmpProfile = &profile{
+	modules:    []string{snmpModule},
+	roots:      []string{"Message", "SNMPv3Message"},
+	describers: snmpDescribers,
+	vocabulary: "snmp",
+}
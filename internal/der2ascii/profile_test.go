@@ -0,0 +1,185 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package der2ascii
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/google/der-ascii/internal"
+)
+
+func TestProfiles(t *testing.T) {
+	for _, name := range profileNames() {
+		p := profiles[name]
+		s, err := p.schema()
+		if err != nil {
+			t.Errorf("Error loading profile %s: %s", name, err)
+			continue
+		}
+		for _, root := range p.roots {
+			if s.lookup(root) == nil {
+				t.Errorf("Profile %s root %s is not defined", name, root)
+			}
+		}
+	}
+}
+
+var detectRootTests = []struct {
+	profile string
+	in      string
+	root    string
+}{
+	// A certificate.
+	{"x509", "30543043020101300a06082a8648ce3d0403023000301e170d31363031303130" +
+		"30303030305a170d3137303130313030303030305a3000300e300906072a8648" +
+		"ce3d0201030100300a06082a8648ce3d040302030100", "Certificate"},
+	// A CRL.
+	{"x509", "302e301d300a06082a8648ce3d0403023000170d313630313031303030303030" +
+		"5a300a06082a8648ce3d040302030100", "CertificateList"},
+	// A certification request.
+	{"x509", "302830170201003000300e300906072a8648ce3d0201030100a000300a06082a" +
+		"8648ce3d040302030100", "CertificationRequest"},
+	// An OCSP request.
+	{"x509", "300430023000", "OCSPRequest"},
+	// An OCSP response.
+	{"x509", "30030a0101", "OCSPResponse"},
+	// A PKCS #12 file.
+	{"pkcs12", "3014020103300f06092a864886f70d010701a0020400", "PFX"},
+	// An encrypted PKCS #8 private key.
+	{"pkcs12", "300f300b06092a864886f70d01050d0400", "EncryptedPrivateKeyInfo"},
+	// An unencrypted PKCS #8 private key.
+	{"pkcs12", "3010020100300906072a8648ce3d02010400", "PrivateKeyInfo"},
+	// A Kerberos TGS-REQ.
+	{"kerberos", "6c3d303ba103020105a20302010ca42f302da00703050000000000a2031b" +
+		"0141a511180f32303337303931333032343830355aa703020101a80530030201" +
+		"12", "TGS-REQ"},
+	// A Kerberos KRB-ERROR.
+	{"kerberos", "7e433041a003020105a10302011ea411180f323032343031303131323030" +
+		"30305aa503020100a603020119a9031b0141aa133011a003020102a10a30081b" +
+		"066b7262746774", "KRB-ERROR"},
+	// An LDAP bind request.
+	{"ldap", "30050201016000", "LDAPMessage"},
+	// An SNMPv2c GetRequest.
+	{"snmp", "30070201010400a000", "Message"},
+	// An SNMPv3 GetRequest.
+	{"snmp", "3038020103300e020101020300ffe30401040201030410300e0400020100" +
+		"020100040004000400301104000400a00b0201010201000201003000", "SNMPv3Message"},
+	// Truncated inputs, as when streaming large files, are detected by
+	// their available prefix.
+	{"x509", "30543043020101300a06082a8648ce3d0403023000301e170d3136", "Certificate"},
+	{"x509", "302e301d300a06082a8648ce3d040302300017", "CertificateList"},
+}
+
+func TestDetectRoot(t *testing.T) {
+	for i, tt := range detectRootTests {
+		p := profiles[tt.profile]
+		s, err := p.schema()
+		if err != nil {
+			t.Fatalf("Error loading profile %s: %s", tt.profile, err)
+		}
+		in, err := hex.DecodeString(tt.in)
+		if err != nil {
+			t.Fatalf("%d. Invalid hex: %s", i, err)
+		}
+		if root := s.detectRoot(p.roots, in); root != tt.root {
+			t.Errorf("%d. detectRoot(%s) = %s, want %s", i, tt.in, root, tt.root)
+		}
+	}
+}
+
+var describeTimeTests = []struct {
+	tag  internal.Tag
+	in   string
+	want string
+}{
+	{internal.Tag{Class: internal.ClassUniversal, Number: 23}, "160302184712Z", "2016-03-02 18:47:12 UTC"},
+	{internal.Tag{Class: internal.ClassUniversal, Number: 23}, "500101000000Z", "1950-01-01 00:00:00 UTC"},
+	{internal.Tag{Class: internal.ClassUniversal, Number: 23}, "491231235959Z", "2049-12-31 23:59:59 UTC"},
+	{internal.Tag{Class: internal.ClassUniversal, Number: 24}, "20500101000000Z", "2050-01-01 00:00:00 UTC"},
+	{internal.Tag{Class: internal.ClassUniversal, Number: 24}, "20160302184712.5Z", "2016-03-02 18:47:12 UTC"},
+	{internal.Tag{Class: internal.ClassUniversal, Number: 24}, "bogus", ""},
+	{internal.Tag{Class: internal.ClassUniversal, Number: 4}, "160302184712Z", ""},
+}
+
+func TestDescribeTime(t *testing.T) {
+	for i, tt := range describeTimeTests {
+		if out := describeTime(element{tag: tt.tag, body: []byte(tt.in)}); out != tt.want {
+			t.Errorf("%d. describeTime(%q) = %q, want %q", i, tt.in, out, tt.want)
+		}
+	}
+}
+
+var describeExtensionTests = []struct {
+	in   []byte
+	want string
+}{
+	{[]byte{0x06, 0x03, 0x55, 0x1d, 0x13, 0x01, 0x01, 0xff, 0x04, 0x00}, "basicConstraints, critical"},
+	{[]byte{0x06, 0x03, 0x55, 0x1d, 0x13, 0x01, 0x01, 0x00, 0x04, 0x00}, "basicConstraints"},
+	{[]byte{0x06, 0x03, 0x55, 0x1d, 0x11, 0x04, 0x00}, "subjectAltName"},
+	{[]byte{0x06, 0x02, 0x2a, 0x03, 0x04, 0x00}, "1.2.3"},
+	{[]byte{0x04, 0x00}, ""},
+}
+
+func TestDescribeExtension(t *testing.T) {
+	for i, tt := range describeExtensionTests {
+		elem := element{tag: internal.Tag{Class: internal.ClassUniversal, Number: 16, Constructed: true}, body: tt.in}
+		if out := describeExtension(elem); out != tt.want {
+			t.Errorf("%d. describeExtension(%x) = %q, want %q", i, tt.in, out, tt.want)
+		}
+	}
+}
+
+var describeSNMPObjectNameTests = []struct {
+	in   string
+	want string
+}{
+	{"2b06010201010300", "sysUpTime.0"},
+	{"2b0601060301010503", "linkDown"},
+	{"2b06010201041401", "mib-2.4.20.1"},
+	{"2b0601020101", "system"},
+	// 1.3.6.1.2.1.10 must not match 1.3.6.1.2.1.1.
+	{"2b060102010a", "mib-2.10"},
+	{"2a0304", ""},
+}
+
+func TestDescribeSNMPObjectName(t *testing.T) {
+	for i, tt := range describeSNMPObjectNameTests {
+		in, err := hex.DecodeString(tt.in)
+		if err != nil {
+			t.Fatalf("%d. Invalid hex: %s", i, err)
+		}
+		elem := element{tag: tagOID, body: in}
+		if out := describeSNMPObjectName(elem); out != tt.want {
+			t.Errorf("%d. describeSNMPObjectName(%s) = %q, want %q", i, tt.in, out, tt.want)
+		}
+	}
+}
+
+func TestDescribeProtocolValues(t *testing.T) {
+	ipAddress := internal.Tag{Class: internal.ClassApplication, Number: 0}
+	if out := describeIPAddress(element{tag: ipAddress, body: []byte{192, 0, 2, 1}}); out != "192.0.2.1" {
+		t.Errorf("describeIPAddress = %q, want %q", out, "192.0.2.1")
+	}
+	if out := describeIPAddress(element{tag: ipAddress, body: []byte{192, 0, 2}}); out != "" {
+		t.Errorf("describeIPAddress of a truncated address = %q, want %q", out, "")
+	}
+	if out := describeLDAPOID(element{tag: tagOctetString, body: []byte("1.3.6.1.4.1.1466.20037")}); out != "startTLS" {
+		t.Errorf("describeLDAPOID = %q, want %q", out, "startTLS")
+	}
+	if out := describeLDAPOID(element{tag: tagOctetString, body: []byte("cn")}); out != "" {
+		t.Errorf("describeLDAPOID of a non-OID = %q, want %q", out, "")
+	}
+}
@@ -0,0 +1,461 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package der2ascii
+
+import (
+	"time"
+
+	"github.com/google/der-ascii/internal"
+)
+
+// x509Module is a subset of the modules in RFC 5280, RFC 2986, and RFC 6960,
+// covering certificates, CRLs, certification requests, and OCSP.
+const x509Module = `
+PKIX1Explicit88 DEFINITIONS EXPLICIT TAGS ::= BEGIN
+
+Certificate ::= SEQUENCE {
+  tbsCertificate       TBSCertificate,
+  signatureAlgorithm   AlgorithmIdentifier,
+  signatureValue       BIT STRING }
+
+TBSCertificate ::= SEQUENCE {
+  version         [0]  Version DEFAULT v1,
+  serialNumber         CertificateSerialNumber,
+  signature            AlgorithmIdentifier,
+  issuer               Name,
+  validity             Validity,
+  subject              Name,
+  subjectPublicKeyInfo SubjectPublicKeyInfo,
+  issuerUniqueID  [1]  IMPLICIT UniqueIdentifier OPTIONAL,
+  subjectUniqueID [2]  IMPLICIT UniqueIdentifier OPTIONAL,
+  extensions      [3]  Extensions OPTIONAL }
+
+Version ::= INTEGER { v1(0), v2(1), v3(2) }
+
+CertificateSerialNumber ::= INTEGER
+
+Validity ::= SEQUENCE {
+  notBefore      Time,
+  notAfter       Time }
+
+Time ::= CHOICE {
+  utcTime        UTCTime,
+  generalTime    GeneralizedTime }
+
+UniqueIdentifier ::= BIT STRING
+
+SubjectPublicKeyInfo ::= SEQUENCE {
+  algorithm            AlgorithmIdentifier,
+  subjectPublicKey     BIT STRING }
+
+Extensions ::= SEQUENCE SIZE (1..MAX) OF Extension
+
+Extension ::= SEQUENCE {
+  extnID      OBJECT IDENTIFIER,
+  critical    BOOLEAN DEFAULT FALSE,
+  extnValue   OCTET STRING (CONTAINING ANY DEFINED BY extnID) }
+
+AlgorithmIdentifier ::= SEQUENCE {
+  algorithm               OBJECT IDENTIFIER,
+  parameters              ANY DEFINED BY algorithm OPTIONAL }
+
+Name ::= CHOICE { rdnSequence RDNSequence }
+
+RDNSequence ::= SEQUENCE OF RelativeDistinguishedName
+
+RelativeDistinguishedName ::= SET SIZE (1..MAX) OF AttributeTypeAndValue
+
+AttributeTypeAndValue ::= SEQUENCE {
+  type     OBJECT IDENTIFIER,
+  value    ANY DEFINED BY type }
+
+DirectoryString ::= CHOICE {
+  teletexString           TeletexString,
+  printableString         PrintableString,
+  universalString         UniversalString,
+  utf8String              UTF8String,
+  bmpString               BMPString }
+
+CertificateList ::= SEQUENCE {
+  tbsCertList          TBSCertList,
+  signatureAlgorithm   AlgorithmIdentifier,
+  signatureValue       BIT STRING }
+
+TBSCertList ::= SEQUENCE {
+  version                 Version OPTIONAL,
+  signature               AlgorithmIdentifier,
+  issuer                  Name,
+  thisUpdate              Time,
+  nextUpdate              Time OPTIONAL,
+  revokedCertificates     SEQUENCE OF RevokedCertificate OPTIONAL,
+  crlExtensions           [0] Extensions OPTIONAL }
+
+RevokedCertificate ::= SEQUENCE {
+  userCertificate         CertificateSerialNumber,
+  revocationDate          Time,
+  crlEntryExtensions      Extensions OPTIONAL }
+
+END
+
+PKCS-10 DEFINITIONS IMPLICIT TAGS ::= BEGIN
+
+CertificationRequest ::= SEQUENCE {
+  certificationRequestInfo CertificationRequestInfo,
+  signatureAlgorithm AlgorithmIdentifier,
+  signature          BIT STRING }
+
+CertificationRequestInfo ::= SEQUENCE {
+  version       INTEGER { v1(0) },
+  subject       Name,
+  subjectPKInfo SubjectPublicKeyInfo,
+  attributes    [0] Attributes }
+
+Attributes ::= SET OF Attribute
+
+Attribute ::= SEQUENCE {
+  type   OBJECT IDENTIFIER,
+  values SET SIZE(1..MAX) OF ANY DEFINED BY type }
+
+END
+
+OCSP-2013-88 DEFINITIONS EXPLICIT TAGS ::= BEGIN
+
+OCSPRequest ::= SEQUENCE {
+  tbsRequest              TBSRequest,
+  optionalSignature   [0] EXPLICIT Signature OPTIONAL }
+
+TBSRequest ::= SEQUENCE {
+  version             [0] EXPLICIT Version DEFAULT v1,
+  requestorName       [1] EXPLICIT GeneralName OPTIONAL,
+  requestList             SEQUENCE OF Request,
+  requestExtensions   [2] EXPLICIT Extensions OPTIONAL }
+
+Signature ::= SEQUENCE {
+  signatureAlgorithm      AlgorithmIdentifier,
+  signature               BIT STRING,
+  certs               [0] EXPLICIT SEQUENCE OF Certificate OPTIONAL }
+
+Request ::= SEQUENCE {
+  reqCert                     CertID,
+  singleRequestExtensions [0] EXPLICIT Extensions OPTIONAL }
+
+CertID ::= SEQUENCE {
+  hashAlgorithm           AlgorithmIdentifier,
+  issuerNameHash          OCTET STRING,
+  issuerKeyHash           OCTET STRING,
+  serialNumber            CertificateSerialNumber }
+
+OCSPResponse ::= SEQUENCE {
+  responseStatus          OCSPResponseStatus,
+  responseBytes       [0] EXPLICIT ResponseBytes OPTIONAL }
+
+OCSPResponseStatus ::= ENUMERATED {
+  successful            (0),
+  malformedRequest      (1),
+  internalError         (2),
+  tryLater              (3),
+  sigRequired           (5),
+  unauthorized          (6) }
+
+ResponseBytes ::= SEQUENCE {
+  responseType   OBJECT IDENTIFIER,
+  response       OCTET STRING (CONTAINING ANY DEFINED BY responseType) }
+
+BasicOCSPResponse ::= SEQUENCE {
+  tbsResponseData      ResponseData,
+  signatureAlgorithm   AlgorithmIdentifier,
+  signature            BIT STRING,
+  certs            [0] EXPLICIT SEQUENCE OF Certificate OPTIONAL }
+
+ResponseData ::= SEQUENCE {
+  version              [0] EXPLICIT Version DEFAULT v1,
+  responderID              ResponderID,
+  producedAt               GeneralizedTime,
+  responses                SEQUENCE OF SingleResponse,
+  responseExtensions   [1] EXPLICIT Extensions OPTIONAL }
+
+ResponderID ::= CHOICE {
+  byName   [1] Name,
+  byKey    [2] KeyHash }
+
+KeyHash ::= OCTET STRING
+
+SingleResponse ::= SEQUENCE {
+  certID                       CertID,
+  certStatus                   CertStatus,
+  thisUpdate                   GeneralizedTime,
+  nextUpdate           [0]     EXPLICIT GeneralizedTime OPTIONAL,
+  singleExtensions     [1]     EXPLICIT Extensions OPTIONAL }
+
+CertStatus ::= CHOICE {
+  good                [0]     IMPLICIT NULL,
+  revoked             [1]     IMPLICIT RevokedInfo,
+  unknown             [2]     IMPLICIT NULL }
+
+RevokedInfo ::= SEQUENCE {
+  revocationTime              GeneralizedTime,
+  revocationReason    [0]     EXPLICIT CRLReason OPTIONAL }
+
+Nonce ::= OCTET STRING
+
+END
+
+PKIX1Implicit88 DEFINITIONS IMPLICIT TAGS ::= BEGIN
+
+AuthorityKeyIdentifier ::= SEQUENCE {
+  keyIdentifier             [0] KeyIdentifier           OPTIONAL,
+  authorityCertIssuer       [1] GeneralNames            OPTIONAL,
+  authorityCertSerialNumber [2] CertificateSerialNumber OPTIONAL }
+
+KeyIdentifier ::= OCTET STRING
+
+SubjectKeyIdentifier ::= KeyIdentifier
+
+KeyUsage ::= BIT STRING {
+  digitalSignature        (0),
+  nonRepudiation          (1),
+  keyEncipherment         (2),
+  dataEncipherment        (3),
+  keyAgreement            (4),
+  keyCertSign             (5),
+  cRLSign                 (6),
+  encipherOnly            (7),
+  decipherOnly            (8) }
+
+CertificatePolicies ::= SEQUENCE SIZE (1..MAX) OF PolicyInformation
+
+PolicyInformation ::= SEQUENCE {
+  policyIdentifier   OBJECT IDENTIFIER,
+  policyQualifiers   SEQUENCE SIZE (1..MAX) OF PolicyQualifierInfo OPTIONAL }
+
+PolicyQualifierInfo ::= SEQUENCE {
+  policyQualifierId  OBJECT IDENTIFIER,
+  qualifier          ANY DEFINED BY policyQualifierId }
+
+CPSuri ::= IA5String
+
+UserNotice ::= SEQUENCE {
+  noticeRef        NoticeReference OPTIONAL,
+  explicitText     DisplayText OPTIONAL }
+
+NoticeReference ::= SEQUENCE {
+  organization     DisplayText,
+  noticeNumbers    SEQUENCE OF INTEGER }
+
+DisplayText ::= CHOICE {
+  ia5String        IA5String,
+  visibleString    VisibleString,
+  bmpString        BMPString,
+  utf8String       UTF8String }
+
+SubjectAltName ::= GeneralNames
+
+IssuerAltName ::= GeneralNames
+
+GeneralNames ::= SEQUENCE SIZE (1..MAX) OF GeneralName
+
+GeneralName ::= CHOICE {
+  otherName                       [0]     OtherName,
+  rfc822Name                      [1]     IA5String,
+  dNSName                         [2]     IA5String,
+  x400Address                     [3]     ANY,
+  directoryName                   [4]     Name,
+  ediPartyName                    [5]     EDIPartyName,
+  uniformResourceIdentifier       [6]     IA5String,
+  iPAddress                       [7]     OCTET STRING,
+  registeredID                    [8]     OBJECT IDENTIFIER }
+
+OtherName ::= SEQUENCE {
+  type-id    OBJECT IDENTIFIER,
+  value      [0] EXPLICIT ANY DEFINED BY type-id }
+
+EDIPartyName ::= SEQUENCE {
+  nameAssigner            [0]     DirectoryString OPTIONAL,
+  partyName               [1]     DirectoryString }
+
+BasicConstraints ::= SEQUENCE {
+  cA                      BOOLEAN DEFAULT FALSE,
+  pathLenConstraint       INTEGER (0..MAX) OPTIONAL }
+
+NameConstraints ::= SEQUENCE {
+  permittedSubtrees       [0]     GeneralSubtrees OPTIONAL,
+  excludedSubtrees        [1]     GeneralSubtrees OPTIONAL }
+
+GeneralSubtrees ::= SEQUENCE SIZE (1..MAX) OF GeneralSubtree
+
+GeneralSubtree ::= SEQUENCE {
+  base                    GeneralName,
+  minimum         [0]     BaseDistance DEFAULT 0,
+  maximum         [1]     BaseDistance OPTIONAL }
+
+BaseDistance ::= INTEGER (0..MAX)
+
+PolicyConstraints ::= SEQUENCE {
+  requireExplicitPolicy   [0]     SkipCerts OPTIONAL,
+  inhibitPolicyMapping    [1]     SkipCerts OPTIONAL }
+
+SkipCerts ::= INTEGER (0..MAX)
+
+ExtKeyUsageSyntax ::= SEQUENCE SIZE (1..MAX) OF KeyPurposeId
+
+KeyPurposeId ::= OBJECT IDENTIFIER
+
+CRLDistributionPoints ::= SEQUENCE SIZE (1..MAX) OF DistributionPoint
+
+DistributionPoint ::= SEQUENCE {
+  distributionPoint       [0]     DistributionPointName OPTIONAL,
+  reasons                 [1]     ReasonFlags OPTIONAL,
+  cRLIssuer               [2]     GeneralNames OPTIONAL }
+
+DistributionPointName ::= CHOICE {
+  fullName                [0]     GeneralNames,
+  nameRelativeToCRLIssuer [1]     RelativeDistinguishedName }
+
+ReasonFlags ::= BIT STRING {
+  unused                  (0),
+  keyCompromise           (1),
+  cACompromise            (2),
+  affiliationChanged      (3),
+  superseded              (4),
+  cessationOfOperation    (5),
+  certificateHold         (6),
+  privilegeWithdrawn      (7),
+  aACompromise            (8) }
+
+AuthorityInfoAccessSyntax ::= SEQUENCE SIZE (1..MAX) OF AccessDescription
+
+AccessDescription ::= SEQUENCE {
+  accessMethod          OBJECT IDENTIFIER,
+  accessLocation        GeneralName }
+
+CRLNumber ::= INTEGER (0..MAX)
+
+IssuingDistributionPoint ::= SEQUENCE {
+  distributionPoint          [0] DistributionPointName OPTIONAL,
+  onlyContainsUserCerts      [1] BOOLEAN DEFAULT FALSE,
+  onlyContainsCACerts        [2] BOOLEAN DEFAULT FALSE,
+  onlySomeReasons            [3] ReasonFlags OPTIONAL,
+  indirectCRL                [4] BOOLEAN DEFAULT FALSE,
+  onlyContainsAttributeCerts [5] BOOLEAN DEFAULT FALSE }
+
+CRLReason ::= ENUMERATED {
+  unspecified             (0),
+  keyCompromise           (1),
+  cACompromise            (2),
+  affiliationChanged      (3),
+  superseded              (4),
+  cessationOfOperation    (5),
+  certificateHold         (6),
+  removeFromCRL           (8),
+  privilegeWithdrawn      (9),
+  aACompromise           (10) }
+
+InvalidityDate ::= GeneralizedTime
+
+END
+`
+
+// x509DefinedBy maps X.509 extensions and other OIDs to their types.
+var x509DefinedBy = map[string]string{
+	// Certificate and CRL extensions.
+	"2.5.29.14":          "SubjectKeyIdentifier",
+	"2.5.29.15":          "KeyUsage",
+	"2.5.29.17":          "SubjectAltName",
+	"2.5.29.18":          "IssuerAltName",
+	"2.5.29.19":          "BasicConstraints",
+	"2.5.29.20":          "CRLNumber",
+	"2.5.29.21":          "CRLReason",
+	"2.5.29.24":          "InvalidityDate",
+	"2.5.29.27":          "CRLNumber",
+	"2.5.29.28":          "IssuingDistributionPoint",
+	"2.5.29.30":          "NameConstraints",
+	"2.5.29.31":          "CRLDistributionPoints",
+	"2.5.29.32":          "CertificatePolicies",
+	"2.5.29.35":          "AuthorityKeyIdentifier",
+	"2.5.29.36":          "PolicyConstraints",
+	"2.5.29.37":          "ExtKeyUsageSyntax",
+	"2.5.29.46":          "CRLDistributionPoints",
+	"2.5.29.54":          "SkipCerts",
+	"1.3.6.1.5.5.7.1.1":  "AuthorityInfoAccessSyntax",
+	"1.3.6.1.5.5.7.1.11": "AuthorityInfoAccessSyntax",
+	// Policy qualifiers.
+	"1.3.6.1.5.5.7.2.1": "CPSuri",
+	"1.3.6.1.5.5.7.2.2": "UserNotice",
+	// Name attributes.
+	"2.5.4.3":  "DirectoryString",
+	"2.5.4.7":  "DirectoryString",
+	"2.5.4.8":  "DirectoryString",
+	"2.5.4.10": "DirectoryString",
+	"2.5.4.11": "DirectoryString",
+	// The PKCS #9 extensionRequest attribute.
+	"1.2.840.113549.1.9.14": "Extensions",
+	// OCSP.
+	"1.3.6.1.5.5.7.48.1.1": "BasicOCSPResponse",
+	"1.3.6.1.5.5.7.48.1.2": "Nonce",
+}
+
+// x509Describers describes X.509 extensions and times.
+var x509Describers = map[string]func(element) string{
+	"Extension":       describeExtension,
+	"UTCTime":         describeTime,
+	"GeneralizedTime": describeTime,
+}
+
+var x509Profile = &profile{
+	modules:    []string{x509Module},
+	roots:      []string{"Certificate", "CertificateList", "CertificationRequest", "OCSPRequest", "OCSPResponse"},
+	definedBy:  x509DefinedBy,
+	describers: x509Describers,
+}
+
+// describeExtension describes elem, an X.509 Extension, by its name and
+// whether it is critical.
+func describeExtension(elem element) string {
+	id, rest, ok := parseElement(elem.body)
+	if !ok || id.tag != (internal.Tag{Class: internal.ClassUniversal, Number: 6}) {
+		return ""
+	}
+	desc, ok := objectIdentifierToName(id.body)
+	if !ok {
+		desc = objectIdentifierToString(id.body)
+	}
+	if critical, _, ok := parseElement(rest); ok && critical.tag == (internal.Tag{Class: internal.ClassUniversal, Number: 1}) && len(critical.body) == 1 && critical.body[0] != 0 {
+		desc += ", critical"
+	}
+	return desc
+}
+
+// describeTime describes elem, a UTCTime or GeneralizedTime, as a date.
+func describeTime(elem element) string {
+	var t time.Time
+	var err error
+	switch elem.tag {
+	case internal.Tag{Class: internal.ClassUniversal, Number: 23}:
+		t, err = time.Parse("060102150405Z0700", string(elem.body))
+		// RFC 5280 interprets two-digit years from 50 to 99 as 19xx
+		// rather than Go's 69 to 99.
+		if err == nil && t.Year() >= 2050 {
+			t = t.AddDate(-100, 0, 0)
+		}
+	case internal.Tag{Class: internal.ClassUniversal, Number: 24}:
+		t, err = time.Parse("20060102150405Z0700", string(elem.body))
+	default:
+		return ""
+	}
+	if err != nil {
+		return ""
+	}
+	return t.UTC().Format("2006-01-02 15:04:05 UTC")
+}
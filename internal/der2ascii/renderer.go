@@ -0,0 +1,258 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package der2ascii
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/google/der-ascii/internal"
+)
+
+// An Element is the primitive element passed to a Renderer.
+type Element struct {
+	Tag  internal.Tag
+	Body []byte
+}
+
+// A Renderer renders the bodies of primitive elements of some type.
+type Renderer struct {
+	// Render, if not nil, returns the value to write between elem's curly
+	// braces, which must assemble to elem's body, and comments to write
+	// before the element. If ok is false, the body is written as bytes.
+	Render func(elem Element) (value string, comments []string, ok bool)
+	// JSON, if not nil, returns the value of elem in JSON output, which is
+	// marshaled with encoding/json. Otherwise, the value is the result of
+	// Render. If ok is false, the element has no value.
+	JSON func(elem Element) (value interface{}, ok bool)
+	// Decoded is true if bodies are always rendered based on their type.
+	// Otherwise, Render is only used if the body does not look like ASN.1.
+	Decoded bool
+}
+
+// A rendererKey identifies the elements a Renderer applies to.
+type rendererKey struct {
+	tag internal.Tag
+	// context, if not empty, is the dotted OID the elements must appear
+	// in the context of, as in node.context.
+	context string
+}
+
+var (
+	// renderersMu guards renderers, so renderers may be registered while
+	// inputs are disassembled.
+	renderersMu sync.RWMutex
+	// renderers contains the registered renderers.
+	renderers = map[rendererKey]Renderer{}
+)
+
+// Register registers r for primitive elements with the specified tag, for both
+// DER ASCII and JSON output. If context is not empty, r only applies to
+// elements in the context of that dotted OID, which is the value of the last
+// OBJECT IDENTIFIER preceding the element as a sibling of it or of one of its
+// ancestors, and takes precedence over a renderer registered without one. It
+// replaces any renderer previously registered with the same tag and context,
+// including the built-in ones.
+func Register(tag internal.Tag, context string, r Renderer) {
+	tag.Constructed = false
+	tag.LongFormOverride = 0
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+	renderers[rendererKey{tag, context}] = r
+}
+
+// findRenderer returns the renderer for primitive elements with the specified
+// tag in the specified context, if any.
+func findRenderer(tag internal.Tag, context string) (Renderer, bool) {
+	tag.Constructed = false
+	tag.LongFormOverride = 0
+	renderersMu.RLock()
+	defer renderersMu.RUnlock()
+	if context != "" {
+		if r, ok := renderers[rendererKey{tag, context}]; ok {
+			return r, true
+		}
+	}
+	r, ok := renderers[rendererKey{tag, ""}]
+	return r, ok
+}
+
+// hasContextRenderer returns true if a renderer is registered for primitive
+// elements with the specified tag in exactly the specified context.
+func hasContextRenderer(tag internal.Tag, context string) bool {
+	if context == "" {
+		return false
+	}
+	tag.Constructed = false
+	tag.LongFormOverride = 0
+	renderersMu.RLock()
+	defer renderersMu.RUnlock()
+	_, ok := renderers[rendererKey{tag, context}]
+	return ok
+}
+
+// nextContext returns the context of the elements following elem, which
+// appears in the specified context. An OBJECT IDENTIFIER sets the context for
+// its later siblings and their descendants.
+func nextContext(elem element, context string) string {
+	if elem.tag.Class != internal.ClassUniversal || elem.tag.Number != 6 || elem.tag.Constructed {
+		return context
+	}
+	if _, ok := decodeObjectIdentifier(elem.body); !ok {
+		return context
+	}
+	return objectIdentifierToString(elem.body)
+}
+
+// renderInteger renders INTEGER bodies in decimal.
+func renderInteger(elem Element) (string, []string, bool) {
+	return integerToString(elem.Body), nil, true
+}
+
+// renderObjectIdentifier renders OBJECT IDENTIFIER bodies in dotted form,
+// preceded by the name of the OID, if known.
+func renderObjectIdentifier(elem Element) (string, []string, bool) {
+	var comments []string
+	if name, ok := objectIdentifierToName(elem.Body); ok {
+		comments = []string{name}
+	}
+	return objectIdentifierToString(elem.Body), comments, true
+}
+
+func renderRelativeOID(elem Element) (string, []string, bool) {
+	return relativeOIDToString(elem.Body), nil, true
+}
+
+func renderReal(elem Element) (string, []string, bool) {
+	return realToString(elem.Body), nil, true
+}
+
+// renderBoolean renders BOOLEAN bodies as TRUE or FALSE if they are valid DER.
+func renderBoolean(elem Element) (string, []string, bool) {
+	if len(elem.Body) == 1 && elem.Body[0] == 0x00 {
+		return "FALSE", nil, true
+	}
+	if len(elem.Body) == 1 && elem.Body[0] == 0xff {
+		return "TRUE", nil, true
+	}
+	return bytesToHexString(elem.Body), nil, true
+}
+
+// renderBitString renders BIT STRING bodies which do not contain a DER-encoded
+// structure.
+func renderBitString(elem Element) (string, []string, bool) {
+	if len(elem.Body) == 1 && elem.Body[0] == 0 {
+		return "b``", nil, true
+	}
+	if len(elem.Body) > 1 && len(elem.Body) <= 5 && elem.Body[0] < 8 {
+		// Convert to a b`` literal when the leading byte is valid and the
+		// number of data octets is at most 4; we limit the length for
+		// readability.
+		bits := new(strings.Builder)
+
+		// The first octet is the number of unused bits.
+		significant := 8 - elem.Body[0]
+		for i, octet := range elem.Body[1:] {
+			// Last octet gets some special handling.
+			isLast := i == len(elem.Body)-2
+			for j := 0; j < 8; j++ {
+				if isLast && int(significant) == j {
+					if octet == 0 {
+						break
+					}
+					bits.WriteRune('|')
+				}
+
+				if octet&0x80 == 0 {
+					bits.WriteRune('0')
+				} else {
+					bits.WriteRune('1')
+				}
+				octet <<= 1
+			}
+		}
+		return fmt.Sprintf("b`%s`", bits), nil, true
+	}
+	if len(elem.Body) > 1 && elem.Body[0] < 8 {
+		// The first byte is the number of unused bits.
+		return fmt.Sprintf("%s %s", bytesToString(elem.Body[:1]), bytesToString(elem.Body[1:])), nil, true
+	}
+	return "", nil, false
+}
+
+// renderTypedString renders the bodies of the time types and OID-IRI types
+// as typed strings.
+func renderTypedString(elem Element) (string, []string, bool) {
+	// If ok is false, name will be empty.
+	name, _, _ := elem.Tag.GetAlias()
+	value, comment := typedStringToString(name, elem.Body)
+	var comments []string
+	if comment != "" {
+		comments = []string{comment}
+	}
+	return value, comments, true
+}
+
+func renderBMPString(elem Element) (string, []string, bool) {
+	return bytesToUTF16String(elem.Body), nil, true
+}
+
+func renderUniversalString(elem Element) (string, []string, bool) {
+	return bytesToUTF32String(elem.Body), nil, true
+}
+
+// renderTextString renders text string bodies as UTF-8 literals if they
+// contain non-ASCII text.
+func renderTextString(elem Element) (string, []string, bool) {
+	if !isUTF8Text(elem.Body) {
+		return "", nil, false
+	}
+	return bytesToUTF8String(elem.Body), nil, true
+}
+
+// registerUniversalRenderer registers r for the universal type with the
+// specified name, in any context.
+func registerUniversalRenderer(name string, r Renderer) {
+	tag, ok := internal.TagByName(name)
+	if !ok {
+		panic("unknown universal type " + name)
+	}
+	Register(tag, "", r)
+}
+
+func init() {
+	registerUniversalRenderer("INTEGER", Renderer{Render: renderInteger, JSON: jsonValueFunc(integerToJSON), Decoded: true})
+	registerUniversalRenderer("ENUMERATED", Renderer{JSON: jsonValueFunc(integerToJSON)})
+	registerUniversalRenderer("OBJECT_IDENTIFIER", Renderer{Render: renderObjectIdentifier, JSON: jsonValueFunc(objectIdentifierToJSON), Decoded: true})
+	registerUniversalRenderer("RELATIVE_OID", Renderer{Render: renderRelativeOID, JSON: jsonValueFunc(relativeOIDToJSON), Decoded: true})
+	registerUniversalRenderer("REAL", Renderer{Render: renderReal, JSON: jsonValueFunc(realToJSON), Decoded: true})
+	registerUniversalRenderer("BOOLEAN", Renderer{Render: renderBoolean, JSON: jsonValueFunc(booleanToJSON), Decoded: true})
+	// X.509 signatures and SPKIs are logically byte strings, but encoded as
+	// a BIT STRING, so the contents may still be a DER-encoded structure.
+	registerUniversalRenderer("BIT_STRING", Renderer{Render: renderBitString, JSON: jsonValueFunc(bitStringToJSON)})
+	for _, name := range typedStringTypes {
+		registerUniversalRenderer(name, Renderer{Render: renderTypedString, JSON: jsonValueFunc(stringToJSON), Decoded: true})
+	}
+	registerUniversalRenderer("BMPString", Renderer{Render: renderBMPString, JSON: jsonValueFunc(utf16ToJSON), Decoded: true})
+	registerUniversalRenderer("UniversalString", Renderer{Render: renderUniversalString, JSON: jsonValueFunc(utf32ToJSON), Decoded: true})
+	for _, name := range textStringTypes {
+		registerUniversalRenderer(name, Renderer{Render: renderTextString, JSON: jsonValueFunc(stringToJSON)})
+	}
+	// These are written as bytes, but decoded in JSON output.
+	for _, name := range []string{"UTCTime", "GeneralizedTime"} {
+		registerUniversalRenderer(name, Renderer{JSON: jsonValueFunc(stringToJSON)})
+	}
+}
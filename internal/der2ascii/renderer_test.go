@@ -0,0 +1,154 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package der2ascii
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/google/der-ascii/internal"
+)
+
+// withRenderer calls f with r registered for tag in context, restoring the
+// previous renderer afterwards.
+func withRenderer(tag internal.Tag, context string, r Renderer, f func()) {
+	key := rendererKey{tag, context}
+	renderersMu.RLock()
+	old, ok := renderers[key]
+	renderersMu.RUnlock()
+	Register(tag, context, r)
+	defer func() {
+		renderersMu.Lock()
+		defer renderersMu.Unlock()
+		if ok {
+			renderers[key] = old
+		} else {
+			delete(renderers, key)
+		}
+	}()
+	f()
+}
+
+// renderTestCounter renders bodies as big-endian counters, for testing.
+func renderTestCounter(elem Element) (string, []string, bool) {
+	if len(elem.Body) != 2 {
+		return "", nil, false
+	}
+	return fmt.Sprintf("`%02x` `%02x`", elem.Body[0], elem.Body[1]), []string{"counter"}, true
+}
+
+var bodyRendererTests = []struct {
+	in  []byte
+	out string
+}{
+	// OCTET STRINGs after the OID are rendered, including those nested in
+	// later siblings, but not the one before it.
+	{
+		[]byte{0x30, 0x13, 0x04, 0x02, 0x30, 0x00, 0x06, 0x03, 0x2a, 0x03, 0x04, 0x04, 0x02, 0x30, 0x00, 0x30, 0x04, 0x04, 0x02, 0x01, 0x02},
+		"SEQUENCE {\n  OCTET_STRING {\n    SEQUENCE {}\n  }\n  OBJECT_IDENTIFIER { 1.2.3.4 }\n  # counter\n  OCTET_STRING { `30` `00` }\n  SEQUENCE {\n    # counter\n    OCTET_STRING { `01` `02` }\n  }\n}\n",
+	},
+	// A later OID changes the context.
+	{
+		[]byte{0x30, 0x0d, 0x06, 0x03, 0x2a, 0x03, 0x04, 0x06, 0x02, 0x2a, 0x03, 0x04, 0x02, 0x01, 0x02},
+		"SEQUENCE {\n  OBJECT_IDENTIFIER { 1.2.3.4 }\n  OBJECT_IDENTIFIER { 1.2.3 }\n  OCTET_STRING { `0102` }\n}\n",
+	},
+	// The context does not extend past the end of the enclosing element.
+	{
+		[]byte{0x30, 0x05, 0x06, 0x03, 0x2a, 0x03, 0x04, 0x04, 0x02, 0x01, 0x02},
+		"SEQUENCE {\n  OBJECT_IDENTIFIER { 1.2.3.4 }\n}\nOCTET_STRING { `0102` }\n",
+	},
+	// If the renderer declines, the body is written as bytes.
+	{
+		[]byte{0x06, 0x03, 0x2a, 0x03, 0x04, 0x04, 0x01, 0x01},
+		"OBJECT_IDENTIFIER { 1.2.3.4 }\nOCTET_STRING { `01` }\n",
+	},
+	// Context-free renderers apply anywhere.
+	{
+		[]byte{0xc1, 0x02, 0x01, 0x02},
+		"# counter\n[PRIVATE 1 PRIMITIVE] { `01` `02` }\n",
+	},
+}
+
+func TestBodyRenderers(t *testing.T) {
+	octetString, _ := internal.TagByName("OCTET_STRING")
+	private := internal.Tag{Class: internal.ClassPrivate, Number: 1}
+	r := Renderer{Render: renderTestCounter, Decoded: true}
+	withRenderer(octetString, "1.2.3.4", r, func() {
+		withRenderer(private, "", r, func() {
+			for i, tt := range bodyRendererTests {
+				if out := derToASCII(tt.in); out != tt.out {
+					t.Errorf("%d. derToASCII(%x) = %q, want %q.", i, tt.in, out, tt.out)
+				}
+				for _, limit := range streamLimits {
+					if out := streamToASCII(t, tt.in, limit, nil); out != tt.out {
+						t.Errorf("%d. streaming %x with limit %d = %q, want %q.", i, tt.in, limit, out, tt.out)
+					}
+				}
+			}
+		})
+	})
+}
+
+func TestBuiltinBodyRenderers(t *testing.T) {
+	// Every universal type the writer decodes has a renderer in any
+	// context.
+	for _, name := range append([]string{"INTEGER", "OBJECT_IDENTIFIER", "RELATIVE_OID", "REAL", "BOOLEAN", "BIT_STRING", "BMPString", "UniversalString"}, append(textStringTypes, typedStringTypes...)...) {
+		tag, ok := internal.TagByName(name)
+		if !ok {
+			t.Fatalf("unknown type %s", name)
+		}
+		if _, ok := findRenderer(tag, "1.2.3.4"); !ok {
+			t.Errorf("no renderer for %s", name)
+		}
+	}
+}
+
+func TestRendererJSON(t *testing.T) {
+	private := internal.Tag{Class: internal.ClassPrivate, Number: 1}
+	in := []byte{0x30, 0x07, 0x06, 0x01, 0x2a, 0xc1, 0x02, 0x01, 0x02}
+	tests := []struct {
+		r   Renderer
+		out string
+	}{
+		// Without a JSON function, the result of Render is used.
+		{
+			Renderer{Render: renderTestCounter},
+			`{"text":"` + "`01` `02`" + `","comments":["counter"]}`,
+		},
+		{
+			Renderer{
+				Render: renderTestCounter,
+				JSON: func(elem Element) (interface{}, bool) {
+					return int(elem.Body[0])<<8 | int(elem.Body[1]), true
+				},
+			},
+			`258`,
+		},
+	}
+	for i, tt := range tests {
+		for _, context := range []string{"", "1.2"} {
+			withRenderer(private, context, tt.r, func() {
+				out, err := json.Marshal(derToJSON(in)[0].Children[1].Value)
+				if err != nil {
+					t.Fatalf("%d. json.Marshal failed: %s", i, err)
+				}
+				if string(out) != tt.out {
+					t.Errorf("%d. value in context %q = %s, want %s.", i, context, out, tt.out)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,918 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package der2ascii
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/der-ascii/internal"
+)
+
+// This file implements a parser for a subset of the ASN.1 module syntax in
+// X.680. It supports type assignments using SEQUENCE, SET, CHOICE, SEQUENCE
+// OF, SET OF, tagged types, OPTIONAL and DEFAULT, named numbers and bits, and
+// the universal types. Constraints are ignored, except for CONTAINING.
+// Information object classes and parameterized types are not supported,
+// though value and class assignments are skipped. The 1988 ANY and ANY
+// DEFINED BY syntax is supported for open types.
+
+// A schemaKind is a kind of ASN.1 type.
+type schemaKind int
+
+const (
+	// schemaUniversal is a type with a universal tag, other than those
+	// below.
+	schemaUniversal schemaKind = iota
+	schemaSequence
+	schemaSet
+	schemaSequenceOf
+	schemaSetOf
+	schemaChoice
+	schemaAny
+	schemaTagged
+	schemaReference
+)
+
+// A tagMode describes whether a tagged type is implicitly or explicitly
+// tagged.
+type tagMode int
+
+const (
+	tagModeDefault tagMode = iota
+	tagModeExplicit
+	tagModeImplicit
+)
+
+// A schemaType is an ASN.1 type.
+type schemaType struct {
+	kind schemaKind
+	// name, for a schemaUniversal, is the ASN.1 name of the type. For a
+	// schemaReference, it is the name of the referenced type.
+	name string
+	// tag, for a schemaUniversal or schemaTagged, is the tag of the type.
+	tag internal.Tag
+	// mode, for a schemaTagged, is the tagging mode. If tagModeDefault,
+	// implicitDefault determines the mode.
+	mode            tagMode
+	implicitDefault bool
+	// inner, for a schemaTagged, is the type being tagged. For
+	// a schemaSequenceOf or schemaSetOf, it is the element type. For an
+	// OCTET STRING or BIT STRING, it is the type of the contents, if known.
+	inner *schemaType
+	// components, for a schemaSequence, schemaSet, or schemaChoice, are the
+	// components or alternatives.
+	components []*schemaComponent
+	// extensible, for a schemaSequence, schemaSet, or schemaChoice, is
+	// true if the type contains an extension marker.
+	extensible bool
+	// namedValues, for an INTEGER or ENUMERATED, maps values to names. For
+	// a BIT STRING, it maps bit positions to names.
+	namedValues map[int64]string
+	// definedBy, for a schemaAny, is the name of the component which
+	// determines the type, if any.
+	definedBy string
+}
+
+// A schemaComponent is a component of a SEQUENCE or SET, or an alternative of
+// a CHOICE.
+type schemaComponent struct {
+	name string
+	typ  *schemaType
+	// optional is true if the component is OPTIONAL or has a DEFAULT.
+	optional bool
+}
+
+// A schema is a collection of ASN.1 type assignments.
+type schema struct {
+	types map[string]*schemaType
+	// order contains the names of types in the order they were defined.
+	order []string
+	// definedBy maps dotted OIDs to types, and is used to resolve ANY
+	// DEFINED BY. A key may also be prefixed by the name of a SEQUENCE or
+	// SET type and a space, to apply only to that type's components.
+	definedBy map[string]*schemaType
+	// describers maps type names to functions which describe values of
+	// that type in comments.
+	describers map[string]func(element) string
+	// vocabulary, if not nil, contains the aliases used to name
+	// non-universal tags in comments.
+	vocabulary *internal.Vocabulary
+}
+
+func newSchema() *schema {
+	return &schema{
+		types:      make(map[string]*schemaType),
+		definedBy:  make(map[string]*schemaType),
+		describers: make(map[string]func(element) string),
+	}
+}
+
+// withVocabulary returns a copy of s which names non-universal tags in
+// comments with the aliases in v.
+func (s *schema) withVocabulary(v *internal.Vocabulary) *schema {
+	ret := *s
+	ret.vocabulary = v
+	return &ret
+}
+
+// tagName returns the tag expression for tag, for use in comments.
+func (s *schema) tagName(tag internal.Tag) string {
+	return tagToStringWithVocabulary(tag, s.vocabulary)
+}
+
+// lookup returns the type with the specified name, or nil if there is none.
+func (s *schema) lookup(name string) *schemaType {
+	return s.types[name]
+}
+
+// resolve follows references from t and returns the referenced type. It
+// returns nil if a reference cannot be resolved.
+func (s *schema) resolve(t *schemaType) *schemaType {
+	// Bound the number of references followed, in case of cycles.
+	for i := 0; t != nil && t.kind == schemaReference; i++ {
+		if i > 100 {
+			return nil
+		}
+		t = s.types[t.name]
+	}
+	return t
+}
+
+// isImplicit returns whether t, a schemaTagged, is implicitly tagged.
+func (s *schema) isImplicit(t *schemaType) bool {
+	switch t.mode {
+	case tagModeExplicit:
+		return false
+	case tagModeImplicit:
+		return true
+	}
+	if !t.implicitDefault {
+		return false
+	}
+	// Tags on CHOICE and ANY types are always explicit.
+	inner := s.resolve(t.inner)
+	return inner == nil || (inner.kind != schemaChoice && inner.kind != schemaAny)
+}
+
+// universalSchemaTypes maps ASN.1 type names to the names used in
+// internal.TagByName.
+var universalSchemaTypes = map[string]string{
+	"BOOLEAN":           "BOOLEAN",
+	"INTEGER":           "INTEGER",
+	"BIT STRING":        "BIT_STRING",
+	"OCTET STRING":      "OCTET_STRING",
+	"NULL":              "NULL",
+	"OBJECT IDENTIFIER": "OBJECT_IDENTIFIER",
+	"ObjectDescriptor":  "OBJECT_DESCRIPTOR",
+	"EXTERNAL":          "EXTERNAL",
+	"REAL":              "REAL",
+	"ENUMERATED":        "ENUMERATED",
+	"EMBEDDED PDV":      "EMBEDDED_PDV",
+	"UTF8String":        "UTF8String",
+	"RELATIVE-OID":      "RELATIVE_OID",
+	"TIME":              "TIME",
+	"NumericString":     "NumericString",
+	"PrintableString":   "PrintableString",
+	"TeletexString":     "T61String",
+	"T61String":         "T61String",
+	"VideotexString":    "VideotexString",
+	"IA5String":         "IA5String",
+	"UTCTime":           "UTCTime",
+	"GeneralizedTime":   "GeneralizedTime",
+	"GraphicString":     "GraphicString",
+	"VisibleString":     "VisibleString",
+	"ISO646String":      "VisibleString",
+	"GeneralString":     "GeneralString",
+	"UniversalString":   "UniversalString",
+	"BMPString":         "BMPString",
+	"DATE":              "DATE",
+	"TIME-OF-DAY":       "TIME-OF-DAY",
+	"DATE-TIME":         "DATE-TIME",
+	"DURATION":          "DURATION",
+	"OID-IRI":           "OID-IRI",
+	"RELATIVE-OID-IRI":  "RELATIVE-OID-IRI",
+}
+
+// A schemaParser parses ASN.1 modules.
+type schemaParser struct {
+	tokens []string
+	lines  []int
+	pos    int
+	schema *schema
+	// implicitDefault is true if the current module uses IMPLICIT or
+	// AUTOMATIC tagging, and automatic is true if the latter.
+	implicitDefault, automatic bool
+}
+
+// isIdentifierByte returns whether c may appear in an ASN.1 identifier.
+func isIdentifierByte(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+// tokenizeSchema splits text into ASN.1 lexical items, discarding comments.
+// It returns the tokens and the line number of each.
+func tokenizeSchema(text string) ([]string, []int, error) {
+	var tokens []string
+	var lines []int
+	line := 1
+	for i := 0; i < len(text); {
+		c := text[i]
+		start := i
+		switch {
+		case c == '\n':
+			line++
+			i++
+			continue
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f' || c == '\v':
+			i++
+			continue
+		case strings.HasPrefix(text[i:], "--"):
+			// Comments run until the end of the line or the next --.
+			i += 2
+			for i < len(text) && text[i] != '\n' && !strings.HasPrefix(text[i:], "--") {
+				i++
+			}
+			if strings.HasPrefix(text[i:], "--") {
+				i += 2
+			}
+			continue
+		case strings.HasPrefix(text[i:], "/*"):
+			depth := 0
+			for i < len(text) {
+				if strings.HasPrefix(text[i:], "/*") {
+					depth++
+					i += 2
+				} else if strings.HasPrefix(text[i:], "*/") {
+					depth--
+					i += 2
+					if depth == 0 {
+						break
+					}
+				} else {
+					if text[i] == '\n' {
+						line++
+					}
+					i++
+				}
+			}
+			if depth != 0 {
+				return nil, nil, fmt.Errorf("line %d: unterminated comment", line)
+			}
+			continue
+		case strings.HasPrefix(text[i:], "::="):
+			i += 3
+		case strings.HasPrefix(text[i:], "..."):
+			i += 3
+		case strings.HasPrefix(text[i:], ".."):
+			i += 2
+		case c == '"' || c == '\'':
+			i++
+			for i < len(text) && text[i] != c {
+				if text[i] == '\n' {
+					line++
+				}
+				i++
+			}
+			if i == len(text) {
+				return nil, nil, fmt.Errorf("line %d: unterminated string", line)
+			}
+			i++
+			// Binary and hex strings are suffixed with B or H.
+			if c == '\'' && i < len(text) && (text[i] == 'B' || text[i] == 'H') {
+				i++
+			}
+		case isIdentifierByte(c) || c == '&' || (c == '-' && i+1 < len(text) && '0' <= text[i+1] && text[i+1] <= '9'):
+			i++
+			for i < len(text) {
+				if isIdentifierByte(text[i]) {
+					i++
+				} else if text[i] == '-' && i+1 < len(text) && isIdentifierByte(text[i+1]) {
+					i++
+				} else {
+					break
+				}
+			}
+		default:
+			i++
+		}
+		tokens = append(tokens, text[start:i])
+		lines = append(lines, line)
+	}
+	return tokens, lines, nil
+}
+
+// parse parses text as a series of ASN.1 modules and adds the type
+// assignments to s.
+func (s *schema) parse(text string) error {
+	tokens, lines, err := tokenizeSchema(text)
+	if err != nil {
+		return err
+	}
+	p := &schemaParser{tokens: tokens, lines: lines, schema: s}
+	for !p.isEOF() {
+		if err := p.parseModule(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *schemaParser) isEOF() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *schemaParser) peek() string {
+	if p.isEOF() {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *schemaParser) peekAt(n int) string {
+	if p.pos+n >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos+n]
+}
+
+func (p *schemaParser) next() string {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *schemaParser) errorf(format string, args ...interface{}) error {
+	line := 0
+	if p.pos < len(p.lines) {
+		line = p.lines[p.pos]
+	} else if len(p.lines) > 0 {
+		line = p.lines[len(p.lines)-1]
+	}
+	return fmt.Errorf("line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+func (p *schemaParser) expect(t string) error {
+	if p.isEOF() {
+		return p.errorf("expected %q but found end of input", t)
+	}
+	if p.peek() != t {
+		return p.errorf("expected %q but found %q", t, p.peek())
+	}
+	p.pos++
+	return nil
+}
+
+// skipBalanced skips a bracketed group beginning at the current token.
+func (p *schemaParser) skipBalanced() error {
+	open := p.peek()
+	var close string
+	switch open {
+	case "{":
+		close = "}"
+	case "(":
+		close = ")"
+	case "[":
+		close = "]"
+	default:
+		return p.errorf("expected bracket but found %q", open)
+	}
+	depth := 0
+	for !p.isEOF() {
+		switch p.next() {
+		case open:
+			depth++
+		case close:
+			depth--
+			if depth == 0 {
+				return nil
+			}
+		}
+	}
+	return p.errorf("unmatched %q", open)
+}
+
+func isTypeReference(t string) bool {
+	return len(t) > 0 && 'A' <= t[0] && t[0] <= 'Z'
+}
+
+func isValueReference(t string) bool {
+	return len(t) > 0 && 'a' <= t[0] && t[0] <= 'z'
+}
+
+func (p *schemaParser) parseModule() error {
+	// ModuleIdentifier, with an optional DefinitiveIdentification.
+	if !isTypeReference(p.next()) {
+		return p.errorf("expected module name")
+	}
+	if p.peek() == "{" {
+		if err := p.skipBalanced(); err != nil {
+			return err
+		}
+	}
+	if err := p.expect("DEFINITIONS"); err != nil {
+		return err
+	}
+	p.implicitDefault, p.automatic = false, false
+	for p.peek() != "::=" {
+		switch t := p.next(); t {
+		case "EXPLICIT":
+		case "IMPLICIT":
+			p.implicitDefault = true
+		case "AUTOMATIC":
+			p.implicitDefault = true
+			p.automatic = true
+		case "TAGS", "EXTENSIBILITY", "IMPLIED", "INSTRUCTIONS":
+		case "":
+			return p.errorf("expected \"::=\"")
+		default:
+			if !isTypeReference(t) {
+				return p.errorf("unexpected %q in module header", t)
+			}
+		}
+	}
+	p.pos++
+	if err := p.expect("BEGIN"); err != nil {
+		return err
+	}
+
+	// Skip EXPORTS and IMPORTS. Types are resolved by name across all
+	// modules.
+	for p.peek() == "EXPORTS" || p.peek() == "IMPORTS" {
+		for !p.isEOF() && p.next() != ";" {
+		}
+	}
+
+	for p.peek() != "END" {
+		if p.isEOF() {
+			return p.errorf("expected END")
+		}
+		if err := p.parseAssignment(); err != nil {
+			return err
+		}
+	}
+	p.pos++
+	return nil
+}
+
+func (p *schemaParser) parseAssignment() error {
+	name := p.next()
+	if isValueReference(name) {
+		// A value assignment. Skip the type and the value.
+		if _, err := p.parseType(); err != nil {
+			return err
+		}
+		if err := p.expect("::="); err != nil {
+			return err
+		}
+		return p.skipValue()
+	}
+	if !isTypeReference(name) {
+		return p.errorf("unexpected %q", name)
+	}
+	if p.peek() == "{" {
+		return p.errorf("parameterized type %s is not supported", name)
+	}
+	if p.peek() != "::=" {
+		// An object or object set assignment. Skip the class and the
+		// value.
+		p.next()
+		if err := p.expect("::="); err != nil {
+			return err
+		}
+		return p.skipValue()
+	}
+	p.pos++
+
+	if p.peek() == "CLASS" {
+		// An information object class. Skip the definition and any WITH
+		// SYNTAX clause.
+		p.pos++
+		if err := p.skipBalanced(); err != nil {
+			return err
+		}
+		if p.peek() == "WITH" && p.peekAt(1) == "SYNTAX" {
+			p.pos += 2
+			return p.skipBalanced()
+		}
+		return nil
+	}
+
+	t, err := p.parseType()
+	if err != nil {
+		return err
+	}
+	if _, ok := p.schema.types[name]; ok {
+		return p.errorf("duplicate definition of %s", name)
+	}
+	p.schema.types[name] = t
+	p.schema.order = append(p.schema.order, name)
+	return nil
+}
+
+// skipValue skips a value, which is either a bracketed group or a single
+// token.
+func (p *schemaParser) skipValue() error {
+	if p.peek() == "{" {
+		return p.skipBalanced()
+	}
+	if p.isEOF() {
+		return p.errorf("expected value")
+	}
+	p.pos++
+	return nil
+}
+
+func (p *schemaParser) parseType() (*schemaType, error) {
+	t, err := p.parseUnconstrainedType()
+	if err != nil {
+		return nil, err
+	}
+	// Constraints are ignored, except for CONTAINING.
+	for p.peek() == "(" {
+		if p.peekAt(1) == "CONTAINING" {
+			p.pos += 2
+			inner, err := p.parseType()
+			if err != nil {
+				return nil, err
+			}
+			if t.kind == schemaUniversal && (t.name == "OCTET STRING" || t.name == "BIT STRING") {
+				t.inner = inner
+			}
+			// Skip any ENCODED BY clause.
+			for !p.isEOF() && p.peek() != ")" {
+				if p.peek() == "{" || p.peek() == "(" {
+					if err := p.skipBalanced(); err != nil {
+						return nil, err
+					}
+				} else {
+					p.pos++
+				}
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err := p.skipBalanced(); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+func (p *schemaParser) parseUnconstrainedType() (*schemaType, error) {
+	switch tok := p.next(); tok {
+	case "[":
+		return p.parseTaggedType()
+	case "SEQUENCE", "SET":
+		kind, ofKind := schemaSequence, schemaSequenceOf
+		if tok == "SET" {
+			kind, ofKind = schemaSet, schemaSetOf
+		}
+		// SIZE constraints may appear before OF.
+		if p.peek() == "SIZE" {
+			p.pos++
+		}
+		if p.peek() == "(" {
+			if err := p.skipBalanced(); err != nil {
+				return nil, err
+			}
+		}
+		if p.peek() == "OF" {
+			p.pos++
+			// The element type may be named.
+			if isValueReference(p.peek()) && p.peekAt(1) != "." {
+				p.pos++
+			}
+			inner, err := p.parseType()
+			if err != nil {
+				return nil, err
+			}
+			return &schemaType{kind: ofKind, inner: inner}, nil
+		}
+		t := &schemaType{kind: kind}
+		if err := p.parseComponents(t, false); err != nil {
+			return nil, err
+		}
+		return t, nil
+	case "CHOICE":
+		t := &schemaType{kind: schemaChoice}
+		if err := p.parseComponents(t, true); err != nil {
+			return nil, err
+		}
+		return t, nil
+	case "ANY":
+		t := &schemaType{kind: schemaAny}
+		if p.peek() == "DEFINED" {
+			p.pos++
+			if err := p.expect("BY"); err != nil {
+				return nil, err
+			}
+			t.definedBy = p.next()
+		}
+		return t, nil
+	case "INTEGER", "ENUMERATED", "BIT":
+		name := tok
+		if tok == "BIT" {
+			if err := p.expect("STRING"); err != nil {
+				return nil, err
+			}
+			name = "BIT STRING"
+		}
+		t := newUniversalSchemaType(name)
+		if p.peek() == "{" {
+			var err error
+			if t.namedValues, err = p.parseNamedValues(); err != nil {
+				return nil, err
+			}
+		}
+		return t, nil
+	case "OCTET", "CHARACTER":
+		if err := p.expect("STRING"); err != nil {
+			return nil, err
+		}
+		if tok == "CHARACTER" {
+			return nil, p.errorf("CHARACTER STRING is not supported")
+		}
+		return newUniversalSchemaType("OCTET STRING"), nil
+	case "OBJECT":
+		if err := p.expect("IDENTIFIER"); err != nil {
+			return nil, err
+		}
+		return newUniversalSchemaType("OBJECT IDENTIFIER"), nil
+	case "EMBEDDED":
+		if err := p.expect("PDV"); err != nil {
+			return nil, err
+		}
+		return newUniversalSchemaType("EMBEDDED PDV"), nil
+	case "":
+		return nil, p.errorf("expected type but found end of input")
+	default:
+		if _, ok := universalSchemaTypes[tok]; ok {
+			return newUniversalSchemaType(tok), nil
+		}
+		if !isTypeReference(tok) {
+			return nil, p.errorf("expected type but found %q", tok)
+		}
+		// References to other modules are resolved by name, and open
+		// types (CLASS.&Type) are treated as ANY.
+		name := tok
+		for p.peek() == "." {
+			p.pos++
+			if strings.HasPrefix(p.peek(), "&") {
+				p.pos++
+				return &schemaType{kind: schemaAny}, nil
+			}
+			name = p.next()
+		}
+		if p.peek() == "{" {
+			return nil, p.errorf("parameterized type %s is not supported", name)
+		}
+		return &schemaType{kind: schemaReference, name: name}, nil
+	}
+}
+
+func newUniversalSchemaType(name string) *schemaType {
+	tag, ok := internal.TagByName(universalSchemaTypes[name])
+	if !ok {
+		panic(name)
+	}
+	return &schemaType{kind: schemaUniversal, name: name, tag: tag}
+}
+
+func (p *schemaParser) parseTaggedType() (*schemaType, error) {
+	// The "[" has already been consumed.
+	t := &schemaType{kind: schemaTagged, implicitDefault: p.implicitDefault}
+	t.tag.Class = internal.ClassContextSpecific
+	switch p.peek() {
+	case "UNIVERSAL":
+		t.tag.Class = internal.ClassUniversal
+		p.pos++
+	case "APPLICATION":
+		t.tag.Class = internal.ClassApplication
+		p.pos++
+	case "PRIVATE":
+		t.tag.Class = internal.ClassPrivate
+		p.pos++
+	}
+	n, err := strconv.ParseUint(p.next(), 10, 64)
+	if err != nil {
+		return nil, p.errorf("invalid tag number: %s", err)
+	}
+	t.tag.Number = n
+	if err := p.expect("]"); err != nil {
+		return nil, err
+	}
+	switch p.peek() {
+	case "IMPLICIT":
+		t.mode = tagModeImplicit
+		p.pos++
+	case "EXPLICIT":
+		t.mode = tagModeExplicit
+		p.pos++
+	}
+	t.inner, err = p.parseType()
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (p *schemaParser) parseNamedValues() (map[int64]string, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	values := make(map[int64]string)
+	for {
+		switch t := p.next(); {
+		case t == "...":
+		case isValueReference(t):
+			if err := p.expect("("); err != nil {
+				return nil, err
+			}
+			n, err := strconv.ParseInt(p.next(), 10, 64)
+			if err == nil {
+				values[n] = t
+			}
+			// Values defined by reference are ignored.
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+		case t == "":
+			return nil, p.errorf("expected \"}\"")
+		default:
+			return nil, p.errorf("unexpected %q in named values", t)
+		}
+		switch t := p.next(); t {
+		case "}":
+			return values, nil
+		case ",":
+		default:
+			return nil, p.errorf("expected \",\" or \"}\" but found %q", t)
+		}
+	}
+}
+
+// parseComponents parses the components of a SEQUENCE or SET, or the
+// alternatives of a CHOICE, into t.
+func (p *schemaParser) parseComponents(t *schemaType, isChoice bool) error {
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	if p.peek() == "}" {
+		p.pos++
+		return nil
+	}
+	for {
+		// Skip version brackets.
+		for p.peek() == "[" && p.peekAt(1) == "[" {
+			p.pos += 2
+		}
+		switch tok := p.peek(); {
+		case tok == "...":
+			t.extensible = true
+			p.pos++
+			// Skip any exception specification.
+			if p.peek() == "!" {
+				p.pos++
+				if p.peek() == "(" {
+					if err := p.skipBalanced(); err != nil {
+						return err
+					}
+				} else {
+					p.pos++
+				}
+			}
+		case tok == "COMPONENTS":
+			return p.errorf("COMPONENTS OF is not supported")
+		case isValueReference(tok):
+			p.pos++
+			typ, err := p.parseType()
+			if err != nil {
+				return err
+			}
+			c := &schemaComponent{name: tok, typ: typ}
+			if !isChoice {
+				switch p.peek() {
+				case "OPTIONAL":
+					c.optional = true
+					p.pos++
+				case "DEFAULT":
+					c.optional = true
+					p.pos++
+					if err := p.skipValue(); err != nil {
+						return err
+					}
+				}
+			}
+			t.components = append(t.components, c)
+		default:
+			return p.errorf("expected component but found %q", tok)
+		}
+		for p.peek() == "]" && p.peekAt(1) == "]" {
+			p.pos += 2
+		}
+		switch tok := p.next(); tok {
+		case "}":
+			p.applyAutomaticTags(t)
+			return nil
+		case ",":
+		default:
+			return p.errorf("expected \",\" or \"}\" but found %q", tok)
+		}
+	}
+}
+
+// applyAutomaticTags tags the components of t if the module uses automatic
+// tagging and none of the components are tagged.
+func (p *schemaParser) applyAutomaticTags(t *schemaType) {
+	if !p.automatic {
+		return
+	}
+	for _, c := range t.components {
+		if c.typ.kind == schemaTagged {
+			return
+		}
+	}
+	for i, c := range t.components {
+		c.typ = &schemaType{
+			kind:            schemaTagged,
+			tag:             internal.Tag{Class: internal.ClassContextSpecific, Number: uint64(i)},
+			implicitDefault: true,
+			inner:           c.typ,
+		}
+	}
+}
+
+// parseSchema parses text as a series of ASN.1 modules and returns the
+// resulting schema.
+func parseSchema(text string) (*schema, error) {
+	s := newSchema()
+	if err := s.parse(text); err != nil {
+		return nil, err
+	}
+	if len(s.order) == 0 {
+		return nil, errors.New("no types defined")
+	}
+	for _, name := range s.order {
+		if err := s.checkReferences(s.types[name]); err != nil {
+			return nil, fmt.Errorf("%s: %s", name, err)
+		}
+	}
+	return s, nil
+}
+
+// parseType parses text as an ASN.1 type, which may refer to types in s.
+func (s *schema) parseType(text string) (*schemaType, error) {
+	tokens, lines, err := tokenizeSchema(text)
+	if err != nil {
+		return nil, err
+	}
+	p := &schemaParser{tokens: tokens, lines: lines, schema: s}
+	t, err := p.parseType()
+	if err != nil {
+		return nil, err
+	}
+	if !p.isEOF() {
+		return nil, p.errorf("unexpected %q after type", p.peek())
+	}
+	if err := s.checkReferences(t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// checkReferences returns an error if t refers to an undefined type.
+func (s *schema) checkReferences(t *schemaType) error {
+	if t.kind == schemaReference {
+		if _, ok := s.types[t.name]; !ok {
+			return fmt.Errorf("type %s is not defined", t.name)
+		}
+	}
+	if t.inner != nil {
+		if err := s.checkReferences(t.inner); err != nil {
+			return err
+		}
+	}
+	for _, c := range t.components {
+		if err := s.checkReferences(c.typ); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package der2ascii
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/der-ascii/internal"
+)
+
+const testSchema = `
+-- A test module.
+Test { 1 2 3 } DEFINITIONS IMPLICIT TAGS ::=
+BEGIN
+IMPORTS Other FROM OtherModule { 1 2 4 };
+
+Outer ::= SEQUENCE {
+  version [0] EXPLICIT Version DEFAULT v1,
+  id OBJECT IDENTIFIER,
+  params ANY DEFINED BY id OPTIONAL,
+  flags [1] Flags OPTIONAL,
+  names SEQUENCE SIZE (1..MAX) OF Name,
+  choice [2] Choice,
+  ...
+}
+
+Version ::= INTEGER { v1(0), v2(1) }
+
+Flags ::= BIT STRING { a(0), b(1), c(2) } (SIZE (1..8))
+
+Name ::= UTF8String (SIZE (1..64))
+
+Choice ::= CHOICE {
+  number INTEGER,
+  text /* inline comment */ IA5String
+}
+
+Wrapped ::= OCTET STRING (CONTAINING Outer)
+
+id-test OBJECT IDENTIFIER ::= { 1 2 840 }
+END
+
+Auto DEFINITIONS AUTOMATIC TAGS ::= BEGIN
+AutoSeq ::= SEQUENCE { a INTEGER, b BOOLEAN OPTIONAL, c Choice }
+AutoSet ::= SET { x INTEGER, y BOOLEAN }
+END
+`
+
+func TestParseSchema(t *testing.T) {
+	s, err := parseSchema(testSchema)
+	if err != nil {
+		t.Fatalf("parseSchema failed: %s", err)
+	}
+	want := []string{"Outer", "Version", "Flags", "Name", "Choice", "Wrapped", "AutoSeq", "AutoSet"}
+	if strings.Join(s.order, " ") != strings.Join(want, " ") {
+		t.Errorf("types = %v, want %v", s.order, want)
+	}
+
+	outer := s.lookup("Outer")
+	if outer.kind != schemaSequence || len(outer.components) != 6 || !outer.extensible {
+		t.Fatalf("Outer parsed incorrectly: %+v", outer)
+	}
+	version := outer.components[0]
+	if version.name != "version" || !version.optional || version.typ.kind != schemaTagged || s.isImplicit(version.typ) {
+		t.Errorf("version parsed incorrectly: %+v", version)
+	}
+	if params := outer.components[2]; params.typ.kind != schemaAny || params.typ.definedBy != "id" || !params.optional {
+		t.Errorf("params parsed incorrectly: %+v", params)
+	}
+	if flags := outer.components[3]; flags.typ.kind != schemaTagged || !s.isImplicit(flags.typ) {
+		t.Errorf("flags parsed incorrectly: %+v", flags)
+	}
+	if names := outer.components[4]; names.typ.kind != schemaSequenceOf || names.typ.inner.name != "Name" {
+		t.Errorf("names parsed incorrectly: %+v", names)
+	}
+	// Tags on CHOICE types are explicit, even in an IMPLICIT TAGS module.
+	if choice := outer.components[5]; choice.typ.kind != schemaTagged || s.isImplicit(choice.typ) {
+		t.Errorf("choice parsed incorrectly: %+v", choice)
+	}
+
+	if v := s.lookup("Version"); v.namedValues[1] != "v2" {
+		t.Errorf("Version named values = %v", v.namedValues)
+	}
+	if w := s.lookup("Wrapped"); w.inner == nil || w.inner.name != "Outer" {
+		t.Errorf("Wrapped parsed incorrectly: %+v", w)
+	}
+
+	auto := s.lookup("AutoSeq")
+	for i, c := range auto.components {
+		if c.typ.kind != schemaTagged || c.typ.tag.Class != internal.ClassContextSpecific || c.typ.tag.Number != uint64(i) {
+			t.Errorf("AutoSeq component %d not automatically tagged: %+v", i, c.typ)
+		}
+	}
+	if !s.isImplicit(auto.components[0].typ) || s.isImplicit(auto.components[2].typ) {
+		t.Errorf("AutoSeq tagging modes incorrect")
+	}
+}
+
+var parseSchemaErrorTests = []string{
+	"",
+	"M DEFINITIONS ::= BEGIN A ::= INTEGER",
+	"M DEFINITIONS ::= BEGIN A ::= Undefined END",
+	"M DEFINITIONS ::= BEGIN A ::= INTEGER A ::= BOOLEAN END",
+	"M DEFINITIONS ::= BEGIN A ::= SEQUENCE { a INTEGER b INTEGER } END",
+	"M DEFINITIONS ::= BEGIN A ::= [0 INTEGER END",
+	"M DEFINITIONS ::= BEGIN A ::= SEQUENCE { COMPONENTS OF B } B ::= SEQUENCE {} END",
+	"M DEFINITIONS ::= BEGIN A {T} ::= SEQUENCE { a T } END",
+	"M DEFINITIONS ::= BEGIN /* unterminated END",
+	"M DEFINITIONS ::= BEGIN END",
+}
+
+func TestParseSchemaErrors(t *testing.T) {
+	for i, tt := range parseSchemaErrorTests {
+		if _, err := parseSchema(tt); err == nil {
+			t.Errorf("%d. parseSchema(%q) unexpectedly succeeded", i, tt)
+		}
+	}
+}
@@ -0,0 +1,294 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package der2ascii
+
+import (
+	"fmt"
+
+	"github.com/google/der-ascii/internal"
+)
+
+// A node is a node in the parse tree of an input. It is either an element or,
+// if raw is not nil, trailing bytes which could not be parsed as an element.
+// The tree is built in a single pass and is used for JSON output and to locate
+// verification failures. DER ASCII output is written by a disassembler, which
+// does not need the whole input in memory.
+type node struct {
+	// offset is the offset of the node in the input.
+	offset int
+	// raw, if not nil, is the bytes which could not be parsed. The
+	// remaining fields are then unset.
+	raw []byte
+
+	elem element
+	// headerLen is the length of the element's tag and length.
+	headerLen int
+	// bodyLen is the length of the element's contents, excluding any
+	// end-of-contents marker.
+	bodyLen int
+	// eoc is true if the element is an end-of-contents marker which did
+	// not close an indefinite-length element.
+	eoc bool
+	// closed is true if the element is indefinite-length and its
+	// end-of-contents marker was found.
+	closed bool
+	// children, if the element's contents were parsed as a series of
+	// elements, contains them. The contents of primitive elements are only
+	// parsed if they look like elements. For a BIT STRING, this excludes
+	// the leading byte.
+	children []node
+	// context is the dotted form of the most recent OBJECT IDENTIFIER
+	// among the node's preceding siblings and those of its ancestors, or
+	// empty if there is none. It selects body renderers which apply in the
+	// context of an OID.
+	context string
+
+	// note, if not empty, explains why the node was not disassembled
+	// further because of a limit. If the node is an element, its
+	// contents, excluding any end-of-contents marker, are in unparsed.
+	note     string
+	unparsed []byte
+}
+
+// limits bounds the work done to disassemble an input, so hostile inputs
+// degrade to hex literals rather than exhausting the stack or memory. A zero
+// field means no limit.
+type limits struct {
+	// maxDepth is the maximum number of nested elements to disassemble.
+	// The contents of elements nested any deeper are written as hex.
+	maxDepth int
+	// maxElements is the maximum number of elements to disassemble. The
+	// input after that is written as hex.
+	maxElements int
+}
+
+// defaultLimits are the limits used if none are specified.
+var defaultLimits = limits{maxDepth: DefaultMaxDepth}
+
+// depthReached returns true if elements at the given depth, where top-level
+// elements have depth zero, should not have their contents disassembled.
+func (l limits) depthReached(depth int) bool {
+	return l.maxDepth > 0 && depth+1 >= l.maxDepth
+}
+
+func (l limits) depthNote() string {
+	return fmt.Sprintf("Depth limit of %d reached.", l.maxDepth)
+}
+
+func (l limits) elementsNote() string {
+	return fmt.Sprintf("Element limit of %d reached.", l.maxElements)
+}
+
+// parseTree parses in into a series of nodes, subject to lim.
+func parseTree(in []byte, lim limits) []node {
+	p := treeParser{lim: lim}
+	nodes, _ := p.parseSeries(in, 0, 0, false, "")
+	return nodes
+}
+
+// A treeParser builds parse trees.
+type treeParser struct {
+	lim limits
+	// elements is the number of elements parsed so far.
+	elements int
+	// free is the unused portion of the most recently allocated block of
+	// nodes. Small series are allocated from blocks to save allocations.
+	free []node
+}
+
+// nodeBlockSize is the number of nodes treeParser allocates at once.
+const nodeBlockSize = 256
+
+// alloc returns an empty slice with capacity for n nodes.
+func (p *treeParser) alloc(n int) []node {
+	if n == 0 {
+		return nil
+	}
+	if n > len(p.free) {
+		if n > nodeBlockSize/4 {
+			return make([]node, 0, n)
+		}
+		p.free = make([]node, nodeBlockSize)
+	}
+	nodes := p.free[:0:n]
+	p.free = p.free[n:]
+	return nodes
+}
+
+// countElements returns an estimate of the number of elements in in, which
+// is exact if in is a series of definite-length elements. It only examines
+// headers, so it is cheap compared to parsing the contents.
+func countElements(in []byte) int {
+	var count int
+	for len(in) != 0 {
+		elem, rest, ok := parseElement(in)
+		if !ok || elem.indefinite {
+			return count + 1
+		}
+		count++
+		in = rest
+	}
+	return count
+}
+
+// parseSeries parses in, which begins at offset in the input, into a series of
+// nodes at the given depth and in the given context. If stopAtEOC is true, it
+// will stop before an end-of-contents marker and return the remaining
+// unprocessed bytes of in.
+func (p *treeParser) parseSeries(in []byte, offset, depth int, stopAtEOC bool, context string) ([]node, []byte) {
+	var nodes []node
+	if !stopAtEOC {
+		nodes = p.alloc(countElements(in))
+	}
+	start := len(in)
+	for len(in) != 0 {
+		if stopAtEOC && startsWithEOC(in) {
+			// The caller will consume the EOC.
+			break
+		}
+		if p.lim.maxElements > 0 && p.elements >= p.lim.maxElements {
+			nodes = append(nodes, node{offset: offset + start - len(in), raw: in, note: p.lim.elementsNote()})
+			return nodes, nil
+		}
+		p.elements++
+		var n node
+		n, in = p.parseNode(in, offset+start-len(in), depth, context)
+		context = nextContext(n.elem, context)
+		nodes = append(nodes, n)
+	}
+	return nodes, in
+}
+
+// skipIndefinite returns the length of in, the contents of an
+// indefinite-length element, up to its end-of-contents marker, and whether
+// the marker was found. Unlike parseSeries, it does not recurse, so it may be
+// used on arbitrarily nested inputs.
+func skipIndefinite(in []byte) (int, bool) {
+	open := 1
+	for pos := 0; pos < len(in); {
+		if startsWithEOC(in[pos:]) {
+			open--
+			if open == 0 {
+				return pos, true
+			}
+			pos += 2
+			continue
+		}
+		elem, rest, ok := parseElement(in[pos:])
+		if !ok {
+			break
+		}
+		if elem.indefinite {
+			open++
+		}
+		pos = len(in) - len(rest)
+	}
+	return len(in), false
+}
+
+// parseNode parses a node from in, which begins at offset in the input, at the
+// given depth and in the given context. It returns the node and the remaining
+// unprocessed bytes of in.
+func (p *treeParser) parseNode(in []byte, offset, depth int, context string) (node, []byte) {
+	elem, rest, ok := parseElement(in)
+	if !ok {
+		return node{offset: offset, raw: in, context: context}, nil
+	}
+	n := node{offset: offset, elem: elem, eoc: startsWithEOC(in), context: context}
+	atLimit := p.lim.depthReached(depth)
+
+	if elem.indefinite {
+		n.headerLen = len(in) - len(rest)
+		var after []byte
+		if atLimit {
+			n.bodyLen, n.closed = skipIndefinite(rest)
+			if n.bodyLen != 0 {
+				n.unparsed = rest[:n.bodyLen]
+				n.note = p.lim.depthNote()
+			}
+			after = rest[n.bodyLen:]
+			if n.closed {
+				after = after[2:]
+			}
+			return n, after
+		}
+		n.children, after = p.parseSeries(rest, offset+n.headerLen, depth+1, true, context)
+		n.bodyLen = len(rest) - len(after)
+		if startsWithEOC(after) {
+			n.closed = true
+			after = after[2:]
+		}
+		return n, after
+	}
+
+	n.headerLen = len(in) - len(rest) - len(elem.body)
+	n.bodyLen = len(elem.body)
+	bodyOffset := offset + n.headerLen
+	// If ok is false, name will be empty.
+	name, _, _ := elem.tag.GetAlias()
+	if atLimit {
+		// Primitive elements are written as usual, without checking
+		// if the body looks like ASN.1.
+		if elem.tag.Constructed && len(elem.body) != 0 {
+			n.unparsed = elem.body
+			n.note = p.lim.depthNote()
+		}
+	} else if elem.tag.Constructed {
+		n.children, _ = p.parseSeries(elem.body, bodyOffset, depth+1, false, context)
+	} else if isPrimitiveDecoded(elem.tag, context) {
+		// The body is rendered based on its type.
+	} else if name == "BIT_STRING" {
+		// X.509 signatures and SPKIs are always logically treated as
+		// byte strings, but mistakenly encoded as a BIT STRING. In some
+		// cases, these byte strings are DER-encoded structures
+		// themselves.
+		if len(elem.body) > 1 && elem.body[0] == 0 {
+			n.children = p.parseElements(elem.body[1:], bodyOffset+1, depth+1, context)
+		}
+	} else {
+		// Keep parsing if the body looks like ASN.1.
+		n.children = p.parseElements(elem.body, bodyOffset, depth+1, context)
+	}
+	return n, rest
+}
+
+// parseElements speculatively parses in, which begins at offset in the input,
+// as a series of BER elements at the given depth and in the given context. It
+// returns the nodes if in is made of elements, as in isMadeOfElements, and nil
+// otherwise. Elements parsed from a rejected body do not count towards the
+// element limit.
+func (p *treeParser) parseElements(in []byte, offset, depth int, context string) []node {
+	elements := p.elements
+	nodes, _ := p.parseSeries(in, offset, depth, false, context)
+	for i := range nodes {
+		n := &nodes[i]
+		// parseElement will parse an unexpected EOC as an element with
+		// tag number zero. This would cause us to recurse into zero
+		// OCTET STRINGs, which is confusing, so reject it.
+		if n.raw != nil || n.eoc || n.elem.indefinite && !n.closed {
+			p.elements = elements
+			return nil
+		}
+	}
+	return nodes
+}
+
+// isPrimitiveDecoded returns true if primitive elements with the specified
+// tag, in the specified context, are decoded based on their type, rather than
+// checking if the body is made of elements.
+func isPrimitiveDecoded(tag internal.Tag, context string) bool {
+	r, ok := findRenderer(tag, context)
+	return ok && r.Decoded
+}
@@ -0,0 +1,305 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package der2ascii
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/der-ascii/internal"
+)
+
+// appendTestElement appends a DER element with the specified one-byte tag and
+// body to out.
+func appendTestElement(out []byte, tag byte, body []byte) []byte {
+	out = append(out, tag)
+	switch n := len(body); {
+	case n < 0x80:
+		out = append(out, byte(n))
+	case n < 0x100:
+		out = append(out, 0x81, byte(n))
+	case n < 0x10000:
+		out = append(out, 0x82, byte(n>>8), byte(n))
+	default:
+		out = append(out, 0x83, byte(n>>16), byte(n>>8), byte(n))
+	}
+	return append(out, body...)
+}
+
+// nestTestElements wraps inner in depth levels of elements with the specified
+// tag. If tag is constructed, the elements are indefinite-length.
+func nestTestElements(tag byte, depth int, inner []byte) []byte {
+	out := inner
+	for i := 0; i < depth; i++ {
+		if tag&0x20 != 0 {
+			out = append(append([]byte{tag, 0x80}, out...), 0x00, 0x00)
+		} else {
+			out = appendTestElement(nil, tag, out)
+		}
+	}
+	return out
+}
+
+func TestParseElements(t *testing.T) {
+	for i, tt := range isMadeOfElementsTests {
+		if out := new(treeParser).parseElements(tt.in, 0, 0, "") != nil; out != (tt.out && len(tt.in) != 0) {
+			t.Errorf("%d. parseElements(%x) returned nodes: %v, want %v.", i, tt.in, out, tt.out)
+		}
+	}
+}
+
+func TestParseTreeOffsets(t *testing.T) {
+	in := []byte{0x30, 0x80, 0x04, 0x05, 0x03, 0x03, 0x00, 0x02, 0x00, 0x00, 0x00, 0xff}
+	nodes := parseTree(in, defaultLimits)
+	if len(nodes) != 2 || nodes[1].offset != 11 || string(nodes[1].raw) != "\xff" {
+		t.Fatalf("parseTree(%x) = %+v, want an element and one raw byte.", in, nodes)
+	}
+	seq := nodes[0]
+	if !seq.closed || seq.headerLen != 2 || seq.bodyLen != 7 || len(seq.children) != 1 {
+		t.Fatalf("SEQUENCE node was %+v.", seq)
+	}
+	octet := seq.children[0]
+	if octet.offset != 2 || octet.headerLen != 2 || octet.bodyLen != 5 || len(octet.children) != 1 {
+		t.Fatalf("OCTET STRING node was %+v.", octet)
+	}
+	bitString := octet.children[0]
+	if bitString.offset != 4 || len(bitString.children) != 1 || bitString.children[0].offset != 7 {
+		t.Fatalf("BIT STRING node was %+v.", bitString)
+	}
+}
+
+// limitTests are inputs disassembled with limits.
+var limitTests = []struct {
+	in  []byte
+	lim limits
+	out string
+}{
+	// The contents of constructed elements at the depth limit are written
+	// as hex.
+	{
+		[]byte{0x30, 0x04, 0x30, 0x02, 0x05, 0x00},
+		limits{maxDepth: 1},
+		"SEQUENCE {\n  # Depth limit of 1 reached.\n  `30020500`\n}\n",
+	},
+	{
+		[]byte{0x30, 0x04, 0x30, 0x02, 0x05, 0x00},
+		limits{maxDepth: 2},
+		"SEQUENCE {\n  SEQUENCE {\n    # Depth limit of 2 reached.\n    `0500`\n  }\n}\n",
+	},
+	// Empty elements do not need a comment.
+	{
+		[]byte{0x30, 0x00},
+		limits{maxDepth: 1},
+		"SEQUENCE {}\n",
+	},
+	// Primitive elements at the depth limit are not checked for elements.
+	{
+		[]byte{0x30, 0x04, 0x04, 0x02, 0x05, 0x00},
+		limits{maxDepth: 2},
+		"SEQUENCE {\n  OCTET_STRING { `0500` }\n}\n",
+	},
+	{
+		[]byte{0x03, 0x07, 0x00, 0x05, 0x00, 0x05, 0x00, 0x05, 0x00},
+		limits{maxDepth: 1},
+		"BIT_STRING { `00` `050005000500` }\n",
+	},
+	// Indefinite-length elements at the depth limit are written as hex up to
+	// their EOC, if there is one.
+	{
+		[]byte{0x30, 0x80, 0x30, 0x80, 0x00, 0x00, 0x00, 0x00, 0x05, 0x00},
+		limits{maxDepth: 1},
+		"SEQUENCE indefinite {\n  # Depth limit of 1 reached.\n  `30800000`\n}\nNULL {}\n",
+	},
+	{
+		[]byte{0x30, 0x80, 0x30, 0x80, 0x00, 0x00},
+		limits{maxDepth: 1},
+		"SEQUENCE `80`\n  # Depth limit of 1 reached.\n  `30800000`\n",
+	},
+	{
+		[]byte{0x30, 0x80, 0x00, 0x00},
+		limits{maxDepth: 1},
+		"SEQUENCE indefinite {\n}\n",
+	},
+	// The input after the element limit is written as hex.
+	{
+		[]byte{0x30, 0x03, 0x02, 0x01, 0x01, 0x05, 0x00},
+		limits{maxElements: 1},
+		"SEQUENCE {\n  # Element limit of 1 reached.\n  `020101`\n}\n# Element limit of 1 reached.\n`0500`\n",
+	},
+	{
+		[]byte{0x30, 0x03, 0x02, 0x01, 0x01, 0x05, 0x00},
+		limits{maxElements: 2},
+		"SEQUENCE {\n  INTEGER { 1 }\n}\n# Element limit of 2 reached.\n`0500`\n",
+	},
+	// Indefinite-length elements remain closed if the limit is reached
+	// within them.
+	{
+		[]byte{0x30, 0x80, 0x05, 0x00, 0x05, 0x00, 0x00, 0x00, 0x05, 0x00},
+		limits{maxElements: 2},
+		"SEQUENCE indefinite {\n  NULL {}\n  # Element limit of 2 reached.\n  `0500`\n}\n# Element limit of 2 reached.\n`0500`\n",
+	},
+	// Primitive elements are not decoded as elements past the limit.
+	{
+		[]byte{0x04, 0x03, 0x02, 0x01, 0x01},
+		limits{maxElements: 1},
+		"OCTET_STRING { `020101` }\n",
+	},
+	// Bodies which are not made of elements do not count towards the limit,
+	// even if they begin with elements.
+	{
+		[]byte{0x30, 0x0f, 0x04, 0x05, 0x04, 0x00, 0x04, 0x00, 0xff, 0x03, 0x06, 0x00, 0x04, 0x00, 0x04, 0x00, 0xff, 0x05, 0x00},
+		limits{maxElements: 4},
+		"SEQUENCE {\n  OCTET_STRING { `04000400ff` }\n  BIT_STRING { `00` `04000400ff` }\n}\nNULL {}\n",
+	},
+}
+
+func TestLimits(t *testing.T) {
+	for i, tt := range limitTests {
+		if out := derToASCIIWithLimits(tt.in, nil, tt.lim); out != tt.out {
+			t.Errorf("%d. derToASCIIWithLimits(%x, %+v) = %q, want %q.", i, tt.in, tt.lim, out, tt.out)
+		}
+	}
+}
+
+func TestDefaultDepthLimit(t *testing.T) {
+	const depth = 5000
+	for _, tt := range []struct {
+		tag  byte
+		name string
+	}{
+		{0x04, "OCTET_STRING"},
+		{0x30, "SEQUENCE"},
+	} {
+		in := nestTestElements(tt.tag, depth, integerElement)
+		if out := derToASCII(in); strings.Count(out, tt.name) != defaultLimits.maxDepth {
+			t.Errorf("Disassembling %d levels of %s did not stop at the depth limit.", depth, tt.name)
+		}
+		if out := streamToASCII(t, in, 16, nil); strings.Count(out, tt.name) != defaultLimits.maxDepth {
+			t.Errorf("Streaming %d levels of %s did not stop at the depth limit.", depth, tt.name)
+		}
+	}
+}
+
+// fuzzSeeds returns the test inputs, as seeds for fuzz tests.
+func fuzzSeeds() [][]byte {
+	var seeds [][]byte
+	for _, tt := range derToASCIITests {
+		seeds = append(seeds, tt.in)
+	}
+	for _, tt := range limitTests {
+		seeds = append(seeds, tt.in)
+	}
+	// A tag and an OID with components at the size limit.
+	seeds = append(seeds, append(append([]byte{0xdf}, longBase128(internal.MaxBase128Length)...), 0x00))
+	seeds = append(seeds, appendTestElement(nil, 0x06, append([]byte{0x2a}, longBase128(internal.MaxBase128Length)...)))
+	return seeds
+}
+
+func FuzzDERToASCII(f *testing.F) {
+	for _, in := range fuzzSeeds() {
+		f.Add(in, uint8(0), uint8(0))
+		f.Add(in, uint8(2), uint8(3))
+	}
+	f.Fuzz(func(t *testing.T, in []byte, maxDepth, maxElements uint8) {
+		lim := limits{maxDepth: int(maxDepth), maxElements: int(maxElements)}
+		if lim.maxDepth == 0 {
+			lim.maxDepth = defaultLimits.maxDepth
+		}
+		out := derToASCIIWithLimits(in, nil, lim)
+		if err := verifyASCII(in, out, nil); err != nil {
+			t.Errorf("%x with limits %+v does not round-trip: %s", in, lim, err)
+		}
+		// Disassembling incrementally does not change the output.
+		if streamed := streamToASCIIWithLimits(t, in, 4, nil, lim); streamed != out {
+			t.Errorf("streaming %x with limits %+v = %q, want %q.", in, lim, streamed, out)
+		}
+	})
+}
+
+func FuzzDERToJSON(f *testing.F) {
+	for _, in := range fuzzSeeds() {
+		f.Add(in, uint8(0), uint8(0))
+		f.Add(in, uint8(2), uint8(3))
+	}
+	f.Fuzz(func(t *testing.T, in []byte, maxDepth, maxElements uint8) {
+		lim := limits{maxDepth: int(maxDepth), maxElements: int(maxElements)}
+		if lim.maxDepth == 0 {
+			lim.maxDepth = defaultLimits.maxDepth
+		}
+		elements := derToJSONWithLimits(in, lim)
+		if _, err := json.Marshal(elements); err != nil {
+			t.Errorf("json.Marshal failed on %x: %s", in, err)
+		} else if err := verifyJSON(in, elements); err != nil {
+			t.Errorf("%x with limits %+v does not round-trip: %s", in, lim, err)
+		}
+	})
+}
+
+var integerElement = []byte{0x02, 0x01, 0x01}
+
+// treeBenchmarks are synthetic inputs which stress the disassembler's
+// heuristics.
+var treeBenchmarks = []struct {
+	name string
+	in   []byte
+}{
+	{"DeepOctetString", nestTestElements(0x04, 1000, integerElement)},
+	{"DeepBitString", func() []byte {
+		out := integerElement
+		for i := 0; i < 1000; i++ {
+			out = appendTestElement(nil, 0x03, append([]byte{0x00}, out...))
+		}
+		return out
+	}()},
+	{"DeepIndefinite", nestTestElements(0x30, 1000, integerElement)},
+	{"DeepMixed", func() []byte {
+		out := integerElement
+		for i := 0; i < 500; i++ {
+			out = appendTestElement(nil, 0x04, nestTestElements(0x30, 1, out))
+		}
+		return out
+	}()},
+	{"DeepNotElements", nestTestElements(0x04, 1000, []byte{0x30, 0x80})},
+	{"WideSequence", appendTestElement(nil, 0x30, bytes.Repeat(appendTestElement(nil, 0x04, integerElement), 10000))},
+	{"WideOctetString", appendTestElement(nil, 0x04, bytes.Repeat(appendTestElement(nil, 0x04, integerElement), 10000))},
+	{"WideIndefinite", appendTestElement(nil, 0x04, bytes.Repeat(nestTestElements(0x30, 1, integerElement), 10000))},
+	{"LongTag", append(append([]byte{0xdf}, longBase128(400000)...), 0x00)},
+	{"LongOIDComponent", appendTestElement(nil, 0x06, append([]byte{0x2a}, longBase128(400000)...))},
+	{"LongOIDComponents", appendTestElement(nil, 0x06, bytes.Repeat(longBase128(internal.MaxBase128Length), 400))},
+}
+
+func BenchmarkDERToASCII(b *testing.B) {
+	for _, bb := range treeBenchmarks {
+		b.Run(bb.name, func(b *testing.B) {
+			b.SetBytes(int64(len(bb.in)))
+			for i := 0; i < b.N; i++ {
+				derToASCII(bb.in)
+			}
+		})
+	}
+}
+
+func BenchmarkDERToJSON(b *testing.B) {
+	for _, bb := range treeBenchmarks {
+		b.Run(bb.name, func(b *testing.B) {
+			b.SetBytes(int64(len(bb.in)))
+			for i := 0; i < b.N; i++ {
+				derToJSON(bb.in)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package der2ascii
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/der-ascii/internal"
+	"github.com/google/der-ascii/internal/ascii2der"
+)
+
+// verifyASCII checks that text, the disassembly of in, assembles back to in.
+// The pragmas for the tag aliases in v are assumed to precede text.
+func verifyASCII(in []byte, text string, v *internal.Vocabulary) error {
+	out, _, err := ascii2der.Assemble(vocabularyHeader(v)+text, nil)
+	if err != nil {
+		return fmt.Errorf("output does not assemble: %s", err)
+	}
+	return compareAssembled(in, out)
+}
+
+// verifyPEMBlock checks that text, the output of pemBlockToASCII, assembles
+// to the PEM encoding of block. The pragmas for the tag aliases in v are
+// assumed to precede text.
+func verifyPEMBlock(block *pem.Block, text string, v *internal.Vocabulary) error {
+	out, _, err := ascii2der.Assemble(vocabularyHeader(v)+text, nil)
+	if err != nil {
+		return fmt.Errorf("output does not assemble: %s", err)
+	}
+	if bytes.Equal(out, pem.EncodeToMemory(block)) {
+		return nil
+	}
+	// Find the difference in the contents, if that is where it is.
+	if outBlock, _ := pem.Decode(out); outBlock != nil {
+		if err := compareAssembled(block.Bytes, outBlock.Bytes); err != nil {
+			return err
+		}
+	}
+	return errors.New("output assembles to a different PEM encoding")
+}
+
+// verifyJSON checks that elements, the JSON disassembly of in, assembles back
+// to in.
+func verifyJSON(in []byte, elements []jsonElement) error {
+	doc, err := json.Marshal(elements)
+	if err != nil {
+		return err
+	}
+	out, _, err := ascii2der.AssembleJSON(doc)
+	if err != nil {
+		return fmt.Errorf("output does not assemble: %s", err)
+	}
+	return compareAssembled(in, out)
+}
+
+// compareAssembled returns nil if got, the assembled output, matches want, the
+// input. Otherwise, it returns an error describing the first difference.
+func compareAssembled(want, got []byte) error {
+	if bytes.Equal(want, got) {
+		return nil
+	}
+	off := 0
+	for off < len(want) && off < len(got) && want[off] == got[off] {
+		off++
+	}
+	if off == len(want) {
+		return fmt.Errorf("output assembles to %d bytes, but the input ends at offset %d", len(got), len(want))
+	}
+	return fmt.Errorf("output assembles to different bytes at offset %d, in %s", off, elementPath(parseTree(want, defaultLimits), off))
+}
+
+// elementPath describes the innermost node of nodes containing the byte at
+// off. The path matches the node's location in derToJSON's output.
+func elementPath(nodes []node, off int) string {
+	path := "elements"
+	var name string
+	var skip int
+	for {
+		var n *node
+		var index int
+		for i := range nodes {
+			if end := nodes[i].offset + nodeLen(&nodes[i]); nodes[i].offset <= off && off < end {
+				n, index = &nodes[i], i+skip
+				break
+			}
+		}
+		if n == nil {
+			break
+		}
+		path += fmt.Sprintf("[%d]", index)
+		if n.raw != nil {
+			name = "unparsed bytes"
+			break
+		}
+		name = tagToString(n.elem.tag)
+		if off < n.offset+n.headerLen {
+			name += " header"
+			break
+		}
+		path += ".children"
+		nodes = n.children
+		// The JSON output lists the leading byte of a BIT STRING as its
+		// first child.
+		skip = 0
+		if alias, _, _ := n.elem.tag.GetAlias(); alias == "BIT_STRING" && !n.elem.tag.Constructed {
+			skip = 1
+		}
+	}
+	path = strings.TrimSuffix(path, ".children")
+	if name == "" {
+		return path
+	}
+	return fmt.Sprintf("%s (%s)", path, name)
+}
+
+// nodeLen returns the length of n in the input.
+func nodeLen(n *node) int {
+	if n.raw != nil {
+		return len(n.raw)
+	}
+	l := n.headerLen + n.bodyLen
+	if n.closed {
+		l += 2
+	}
+	return l
+}
@@ -0,0 +1,96 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package der2ascii
+
+import (
+	"encoding/pem"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	for _, in := range fuzzSeeds() {
+		if err := verifyASCII(in, derToASCII(in), nil); err != nil {
+			t.Errorf("verifyASCII(%x) failed: %s", in, err)
+		}
+		if err := verifyJSON(in, derToJSON(in)); err != nil {
+			t.Errorf("verifyJSON(%x) failed: %s", in, err)
+		}
+		block := &pem.Block{Type: "TEST", Bytes: in}
+		if err := verifyPEMBlock(block, pemBlockToASCII(block, nil, defaultLimits, nil), nil); err != nil {
+			t.Errorf("verifyPEMBlock(%x) failed: %s", in, err)
+		}
+	}
+}
+
+var compareAssembledTests = []struct {
+	want, got []byte
+	err       string
+}{
+	{
+		[]byte{0x30, 0x06, 0x02, 0x01, 0x01, 0x02, 0x01, 0x02},
+		[]byte{0x30, 0x06, 0x02, 0x01, 0x01, 0x02, 0x01, 0x02},
+		"",
+	},
+	{
+		[]byte{0x30, 0x06, 0x02, 0x01, 0x01, 0x02, 0x01, 0x02},
+		[]byte{0x30, 0x06, 0x02, 0x01, 0x01, 0x02, 0x01, 0x03},
+		"output assembles to different bytes at offset 7, in elements[0].children[1] (INTEGER)",
+	},
+	{
+		[]byte{0x30, 0x06, 0x02, 0x01, 0x01, 0x02, 0x01, 0x02},
+		[]byte{0x30, 0x06, 0x02, 0x01, 0x01, 0x02, 0x81, 0x01, 0x02},
+		"output assembles to different bytes at offset 6, in elements[0].children[1] (INTEGER header)",
+	},
+	{
+		[]byte{0x30, 0x06, 0x02, 0x01, 0x01, 0x02, 0x01, 0x02},
+		[]byte{0x30, 0x06, 0x02, 0x01, 0x01},
+		"output assembles to different bytes at offset 5, in elements[0].children[1] (INTEGER header)",
+	},
+	{
+		[]byte{0x30, 0x00},
+		[]byte{0x30, 0x00, 0x00},
+		"output assembles to 3 bytes, but the input ends at offset 2",
+	},
+	// The leading byte of a BIT STRING is the first child in JSON.
+	{
+		[]byte{0x03, 0x04, 0x00, 0x02, 0x01, 0x01},
+		[]byte{0x03, 0x04, 0x00, 0x02, 0x01, 0x02},
+		"output assembles to different bytes at offset 5, in elements[0].children[1] (INTEGER)",
+	},
+	{
+		[]byte{0x03, 0x04, 0x00, 0x02, 0x01, 0x01},
+		[]byte{0x03, 0x04, 0x01, 0x02, 0x01, 0x01},
+		"output assembles to different bytes at offset 2, in elements[0] (BIT_STRING)",
+	},
+	// Bytes which do not parse are reported as such.
+	{
+		[]byte{0x30, 0x00, 0xff},
+		[]byte{0x30, 0x00, 0xfe},
+		"output assembles to different bytes at offset 2, in elements[1] (unparsed bytes)",
+	},
+}
+
+func TestCompareAssembled(t *testing.T) {
+	for i, tt := range compareAssembledTests {
+		err := compareAssembled(tt.want, tt.got)
+		var errStr string
+		if err != nil {
+			errStr = err.Error()
+		}
+		if errStr != tt.err {
+			t.Errorf("%d. compareAssembled(%x, %x) = %q, wanted %q.", i, tt.want, tt.got, errStr, tt.err)
+		}
+	}
+}
@@ -12,12 +12,14 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package main
+package der2ascii
 
 import (
 	"bytes"
 	"encoding/hex"
+	"encoding/pem"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"unicode"
@@ -74,17 +76,42 @@ func classToString(class internal.Class) string {
 	}
 }
 
+// vocabularyHeader returns the pragmas which select v's tag aliases, followed
+// by a blank line, or the empty string if v is empty.
+func vocabularyHeader(v *internal.Vocabulary) string {
+	var out bytes.Buffer
+	for _, name := range v.Builtins() {
+		fmt.Fprintf(&out, "vocabulary %s\n", name)
+	}
+	for _, a := range v.Custom() {
+		fmt.Fprintf(&out, "tag-alias %s %s\n", a.Name, tagToStringWithVocabulary(a.Tag, nil))
+	}
+	if out.Len() != 0 {
+		out.WriteString("\n")
+	}
+	return out.String()
+}
+
 func tagToString(tag internal.Tag) string {
+	return tagToStringWithVocabulary(tag, nil)
+}
+
+// tagToStringWithVocabulary returns the tag expression for tag, using the
+// aliases in v for non-universal tags.
+func tagToStringWithVocabulary(tag internal.Tag, v *internal.Vocabulary) string {
 	// Write a short name if possible.
 	name, includeConstructed, nameOk := tag.GetAlias()
 	if nameOk && tag.LongFormOverride == 0 && !includeConstructed {
 		return name
 	}
+	if !nameOk {
+		name, includeConstructed, nameOk = v.GetAlias(tag)
+	}
 	if !nameOk {
 		if tag.Class != internal.ClassContextSpecific {
-			name = fmt.Sprintf("%s %d", classToString(tag.Class), tag.Number)
+			name = fmt.Sprintf("%s %s", classToString(tag.Class), tag.NumberString())
 		} else {
-			name = fmt.Sprintf("%d", tag.Number)
+			name = tag.NumberString()
 		}
 		includeConstructed = !tag.Constructed
 	}
@@ -145,6 +172,52 @@ func bytesToQuotedString(in []byte) string {
 	return out.String()
 }
 
+// textStringTypes are the aliases of string types whose contents may be
+// written as a UTF-8 literal.
+var textStringTypes = []string{"UTF8String", "NumericString", "PrintableString", "T61String", "VideotexString", "IA5String", "GraphicString", "VisibleString", "GeneralString", "OBJECT_DESCRIPTOR"}
+
+// isUTF8Text returns whether in is valid UTF-8 containing non-ASCII
+// characters, so is more readable as a UTF-8 literal than as a quoted string.
+func isUTF8Text(in []byte) bool {
+	if !utf8.Valid(in) {
+		return false
+	}
+	for _, b := range in {
+		if b >= 0x80 {
+			return true
+		}
+	}
+	return false
+}
+
+func bytesToUTF8String(in []byte) string {
+	var out bytes.Buffer
+	out.WriteString(`u8"`)
+	for len(in) > 0 {
+		r, n := utf8.DecodeRune(in)
+		if r == utf8.RuneError && n <= 1 {
+			fmt.Fprintf(&out, `\x%02x`, in[0])
+		} else if r == '\n' {
+			out.WriteString(`\n`)
+		} else if r == '"' {
+			out.WriteString(`\"`)
+		} else if r == '\\' {
+			out.WriteString(`\\`)
+		} else if unicode.IsPrint(r) {
+			out.WriteRune(r)
+		} else if r < 0x80 {
+			fmt.Fprintf(&out, `\x%02x`, r)
+		} else if r <= 0xffff {
+			fmt.Fprintf(&out, `\u%04x`, r)
+		} else {
+			fmt.Fprintf(&out, `\U%08x`, r)
+		}
+		in = in[n:]
+	}
+	out.WriteString(`"`)
+	return out.String()
+}
+
 func bytesToUTF16String(in []byte) string {
 	var out bytes.Buffer
 	out.WriteString(`u"`)
@@ -232,6 +305,56 @@ func integerToString(in []byte) string {
 	return bytesToHexString(in)
 }
 
+func realToString(in []byte) string {
+	if v, ok := decodeReal(in); ok {
+		return "real:" + v
+	}
+	return bytesToHexString(in)
+}
+
+// typedStringTypes are the aliases of types whose values may be written as
+// typed string tokens, such as "date:2024-02-29".
+var typedStringTypes = []string{"TIME", "DATE", "TIME-OF-DAY", "DATE-TIME", "DURATION", "OID-IRI", "RELATIVE-OID-IRI"}
+
+// typedStringToString returns the representation of in, the body of an element
+// of type name, and a comment to emit before it. If in is a valid value, it is
+// written as a typed string token and, for OID-IRIs, the comment gives the
+// corresponding dotted OID. Otherwise, the comment describes the error.
+func typedStringToString(name string, in []byte) (value, comment string) {
+	s := string(in)
+	var err error
+	switch name {
+	case "OID-IRI":
+		_, err = internal.ParseOIDIRI(s, false)
+	case "RELATIVE-OID-IRI":
+		_, err = internal.ParseOIDIRI(s, true)
+	default:
+		err = internal.ValidateTime(name, s)
+	}
+	if err != nil {
+		return bytesToString(in), fmt.Sprintf("Invalid %s: %s", name, err)
+	}
+	if name == "OID-IRI" {
+		if dotted, err := internal.OIDIRIToDotted(s); err == nil {
+			comment = dotted
+			if oidName, ok := dottedOIDToName(dotted); ok {
+				comment += " (" + oidName + ")"
+			}
+		}
+	}
+	return strings.ToLower(name) + ":" + s, comment
+}
+
+// dottedOIDToName returns the name of oid, a dotted OID, if known.
+func dottedOIDToName(oid string) (string, bool) {
+	for _, entry := range oidNames {
+		if objectIdentifierToString(entry.oid) == oid {
+			return entry.name, true
+		}
+	}
+	return "", false
+}
+
 func objectIdentifierToName(oid []byte) (string, bool) {
 	// TODO(davidben): Now that this list is generated, we may as well sort
 	// them in the generator and do a binary search here.
@@ -253,7 +376,7 @@ func objectIdentifierToString(in []byte) string {
 		if i != 0 {
 			out.WriteString(".")
 		}
-		out.WriteString(strconv.FormatUint(uint64(v), 10))
+		out.WriteString(v.String())
 	}
 	return out.String()
 }
@@ -266,7 +389,7 @@ func relativeOIDToString(in []byte) string {
 	var out bytes.Buffer
 	for _, v := range oid {
 		out.WriteString(".")
-		out.WriteString(strconv.FormatUint(uint64(v), 10))
+		out.WriteString(v.String())
 	}
 	return out.String()
 }
@@ -283,162 +406,73 @@ func startsWithEOC(in []byte) bool {
 	return len(in) >= 2 && in[0] == 0 && in[1] == 0
 }
 
-// derToASCIIImpl disassembles in and writes the result to out with the given
-// indent. If stopAtEOC is true, it will stop after an end-of-contents marker
-// and return the remaining unprocessed bytes of in.
-func derToASCIIImpl(out *bytes.Buffer, in []byte, indent int, stopAtEOC bool) []byte {
-	for len(in) != 0 {
-		if stopAtEOC && startsWithEOC(in) {
-			// The caller will consume the EOC.
-			return in
-		}
-
-		elem, rest, ok := parseElement(in)
-		if !ok {
-			// Nothing more to encode. Write the rest as bytes.
-			addLine(out, indent, bytesToString(in))
-			return nil
-		}
-		in = rest
+// elementHeader returns the opening line of elem, a definite-length element,
+// up to and including the curly brace, using the tag aliases in v.
+func elementHeader(elem element, v *internal.Vocabulary) string {
+	if elem.longFormOverride == 0 {
+		return fmt.Sprintf("%s {", tagToStringWithVocabulary(elem.tag, v))
+	}
+	return fmt.Sprintf("%s long-form:%d {", tagToStringWithVocabulary(elem.tag, v), elem.longFormOverride)
+}
 
-		if elem.indefinite {
-			// If the indefinite-length element is properly closed,
-			// we write curly braces with an indefinite modifier.
-			// Otherwise, we must write a raw `80` literal. Write
-			// the body to a buffer so we may decide this later.
-			var child bytes.Buffer
-			in = derToASCIIImpl(&child, in, indent+1, true)
-			if startsWithEOC(in) {
-				addLine(out, indent, fmt.Sprintf("%s indefinite {", tagToString(elem.tag)))
-				out.Write(child.Bytes())
-				addLine(out, indent, "}")
-				in = in[2:]
-			} else {
-				addLine(out, indent, fmt.Sprintf("%s `80`", tagToString(elem.tag)))
-				out.Write(child.Bytes())
-			}
-			continue
-		}
+func derToASCII(in []byte) string {
+	return derToASCIIWithStructure(in, nil)
+}
 
-		var header string
-		if elem.longFormOverride == 0 {
-			header = fmt.Sprintf("%s {", tagToString(elem.tag))
-		} else {
-			header = fmt.Sprintf("%s long-form:%d {", tagToString(elem.tag), elem.longFormOverride)
-		}
+// derToASCIIWithStructure disassembles in, annotating the output with comments
+// from s.
+func derToASCIIWithStructure(in []byte, s structure) string {
+	return derToASCIIWithLimits(in, s, defaultLimits)
+}
 
-		if len(elem.body) == 0 {
-			// If the body is empty, skip the newlines.
-			addLine(out, indent, fmt.Sprintf("%s}", header))
-			continue
-		}
+// derToASCIIWithLimits behaves like derToASCIIWithStructure, but disassembles
+// subject to lim.
+func derToASCIIWithLimits(in []byte, s structure, lim limits) string {
+	var out bytes.Buffer
+	writeASCII(&out, in, 0, s, lim, nil)
+	return out.String()
+}
 
-		if elem.tag.Constructed {
-			// If the element is constructed, recurse.
-			addLine(out, indent, header)
-			derToASCIIImpl(out, elem.body, indent+1, false)
-			addLine(out, indent, "}")
-		} else {
-			// The element is primitive. By default, emit the body
-			// on the same line as curly braces. However, in some
-			// cases, we heuristically decode the body as DER too.
-			// In this case, the newlines are inserted as in the
-			// constructed case.
-
-			// If ok is false, name will be empty. There is also no
-			// need to check toggleConstructed as we already know
-			// the tag is primitive.
-			name, _, _ := elem.tag.GetAlias()
-			switch name {
-			case "INTEGER":
-				addLine(out, indent, fmt.Sprintf("%s %s }", header, integerToString(elem.body)))
-			case "OBJECT_IDENTIFIER":
-				if name, ok := objectIdentifierToName(elem.body); ok {
-					addLine(out, indent, fmt.Sprintf("# %s", name))
-				}
-				addLine(out, indent, fmt.Sprintf("%s %s }", header, objectIdentifierToString(elem.body)))
-			case "RELATIVE_OID":
-				addLine(out, indent, fmt.Sprintf("%s %s }", header, relativeOIDToString(elem.body)))
-			case "BOOLEAN":
-				var encoded string
-				if len(elem.body) == 1 && elem.body[0] == 0x00 {
-					encoded = "FALSE"
-				} else if len(elem.body) == 1 && elem.body[0] == 0xff {
-					encoded = "TRUE"
-				} else {
-					encoded = bytesToHexString(elem.body)
-				}
-				addLine(out, indent, fmt.Sprintf("%s %s }", header, encoded))
-			case "BIT_STRING":
-				if len(elem.body) > 1 && elem.body[0] == 0 && isMadeOfElements(elem.body[1:]) {
-					// X.509 signatures and SPKIs are always logically treated
-					// as byte strings, but mistakenly encoded as a BIT STRING.
-					// In some cases, these byte strings are DER-encoded
-					// structures themselves. Keep parsing if this is detected.
-					addLine(out, indent, header)
-					// Emit number of unused bits.
-					addLine(out, indent+1, "`00`")
-					// Emit the remaining as a DER element.
-					derToASCIIImpl(out, elem.body[1:], indent+1, false) // Adds a trailing newline.
-					addLine(out, indent, "}")
-				} else if len(elem.body) == 1 && elem.body[0] == 0 {
-					addLine(out, indent, fmt.Sprintf("%s b`` }", header))
-				} else if len(elem.body) > 1 && len(elem.body) <= 5 && elem.body[0] < 8 {
-					// Convert to a b`` literal when the leading byte is valid and the
-					// number of data octets is at most 4; we limit the length for
-					// readability.
-					bits := new(strings.Builder)
-
-					// The first octet is the number of unused bits.
-					significant := 8 - elem.body[0]
-					for i, octet := range elem.body[1:] {
-						// Last octet gets some special handling.
-						isLast := i == len(elem.body)-2
-						for j := 0; j < 8; j++ {
-							if isLast && int(significant) == j {
-								if octet == 0 {
-									break
-								}
-								bits.WriteRune('|')
-							}
-
-							if octet&0x80 == 0 {
-								bits.WriteRune('0')
-							} else {
-								bits.WriteRune('1')
-							}
-							octet <<= 1
-						}
-					}
-
-					addLine(out, indent, fmt.Sprintf("%s b`%s` }", header, bits))
-				} else if len(elem.body) > 1 && elem.body[0] < 8 {
-					// The first byte is the number of unused bits.
-					addLine(out, indent, fmt.Sprintf("%s %s %s }", header, bytesToString(elem.body[:1]), bytesToString(elem.body[1:])))
-				} else {
-					addLine(out, indent, fmt.Sprintf("%s %s }", header, bytesToString(elem.body)))
-				}
-			case "BMPString":
-				addLine(out, indent, fmt.Sprintf("%s %s }", header, bytesToUTF16String(elem.body)))
-			case "UniversalString":
-				addLine(out, indent, fmt.Sprintf("%s %s }", header, bytesToUTF32String(elem.body)))
-			default:
-				// Keep parsing if the body looks like ASN.1.
-				if isMadeOfElements(elem.body) {
-					addLine(out, indent, header)
-					derToASCIIImpl(out, elem.body, indent+1, false)
-					addLine(out, indent, "}")
-				} else {
-					addLine(out, indent, fmt.Sprintf("%s %s }", header, bytesToString(elem.body)))
-				}
-			}
-		}
-	}
-	return nil
+// writeASCII disassembles in, subject to lim, and writes the result to out with
+// the given indent, using the tag aliases in v. If s is not nil, the output is
+// annotated with comments from s. The input is already in memory, so it is
+// read into the disassembler whole.
+func writeASCII(out *bytes.Buffer, in []byte, indent int, s structure, lim limits, v *internal.Vocabulary) {
+	d := newDisassembler(out, bytes.NewReader(in), int64(len(in)), lim)
+	d.vocabulary = v
+	d.disassemble(0, int64(len(in)), indent, false, s, "")
+	// Neither reading from in nor writing to out can fail.
+	d.w.Flush()
 }
 
-func derToASCII(in []byte) string {
+// pemBlockToASCII disassembles block as a DER ASCII pem block, which
+// assembles to the PEM encoding of block. If s is not nil, the output is
+// annotated with comments from s. The block's contents are disassembled
+// subject to lim, using the tag aliases in v.
+func pemBlockToASCII(block *pem.Block, s structure, lim limits, v *internal.Vocabulary) string {
 	var out bytes.Buffer
-	derToASCIIImpl(&out, in, 0, false)
+	header := fmt.Sprintf("pem %s", bytesToQuotedString([]byte(block.Type)))
+	// Match the header order of pem.Encode, so the output is reproduced
+	// exactly.
+	var keys []string
+	for k := range block.Headers {
+		if k != "Proc-Type" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	if _, ok := block.Headers["Proc-Type"]; ok {
+		keys = append([]string{"Proc-Type"}, keys...)
+	}
+	for _, k := range keys {
+		header += fmt.Sprintf(" header %s %s", bytesToQuotedString([]byte(k)), bytesToQuotedString([]byte(block.Headers[k])))
+	}
+	if len(block.Bytes) == 0 {
+		addLine(&out, 0, header+" {}")
+		return out.String()
+	}
+	addLine(&out, 0, header+" {")
+	writeASCII(&out, block.Bytes, 1, s, lim, v)
+	addLine(&out, 0, "}")
 	return out.String()
 }
@@ -12,9 +12,10 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package main
+package der2ascii
 
 import (
+	"encoding/pem"
 	"testing"
 
 	"github.com/google/der-ascii/internal"
@@ -43,22 +44,23 @@ var tagToStringTests = []struct {
 	in  internal.Tag
 	out string
 }{
-	{internal.Tag{internal.ClassUniversal, 16, true, 0}, "SEQUENCE"},
-	{internal.Tag{internal.ClassUniversal, 16, true, 1}, "[long-form:1 SEQUENCE]"},
-	{internal.Tag{internal.ClassUniversal, 16, false, 0}, "[SEQUENCE PRIMITIVE]"},
-	{internal.Tag{internal.ClassUniversal, 16, false, 1}, "[long-form:1 SEQUENCE PRIMITIVE]"},
-	{internal.Tag{internal.ClassUniversal, 2, true, 0}, "[INTEGER CONSTRUCTED]"},
-	{internal.Tag{internal.ClassUniversal, 2, false, 0}, "INTEGER"},
-	{internal.Tag{internal.ClassUniversal, 1234, true, 0}, "[UNIVERSAL 1234]"},
-	{internal.Tag{internal.ClassContextSpecific, 0, true, 0}, "[0]"},
-	{internal.Tag{internal.ClassContextSpecific, 0, true, 1}, "[long-form:1 0]"},
-	{internal.Tag{internal.ClassContextSpecific, 0, false, 0}, "[0 PRIMITIVE]"},
-	{internal.Tag{internal.ClassApplication, 0, true, 0}, "[APPLICATION 0]"},
-	{internal.Tag{internal.ClassApplication, 0, true, 1}, "[long-form:1 APPLICATION 0]"},
-	{internal.Tag{internal.ClassApplication, 0, false, 0}, "[APPLICATION 0 PRIMITIVE]"},
-	{internal.Tag{internal.ClassApplication, 0, false, 1}, "[long-form:1 APPLICATION 0 PRIMITIVE]"},
-	{internal.Tag{internal.ClassPrivate, 0, true, 0}, "[PRIVATE 0]"},
-	{internal.Tag{internal.ClassPrivate, 0, false, 0}, "[PRIVATE 0 PRIMITIVE]"},
+	{internal.Tag{Class: internal.ClassUniversal, Number: 16, Constructed: true}, "SEQUENCE"},
+	{internal.Tag{Class: internal.ClassUniversal, Number: 16, Constructed: true, LongFormOverride: 1}, "[long-form:1 SEQUENCE]"},
+	{internal.Tag{Class: internal.ClassUniversal, Number: 16}, "[SEQUENCE PRIMITIVE]"},
+	{internal.Tag{Class: internal.ClassUniversal, Number: 16, LongFormOverride: 1}, "[long-form:1 SEQUENCE PRIMITIVE]"},
+	{internal.Tag{Class: internal.ClassUniversal, Number: 2, Constructed: true}, "[INTEGER CONSTRUCTED]"},
+	{internal.Tag{Class: internal.ClassUniversal, Number: 2}, "INTEGER"},
+	{internal.Tag{Class: internal.ClassUniversal, Number: 1234, Constructed: true}, "[UNIVERSAL 1234]"},
+	{internal.Tag{Class: internal.ClassPrivate, Constructed: true, BigNumber: "18446744073709551616"}, "[PRIVATE 18446744073709551616]"},
+	{internal.Tag{Class: internal.ClassContextSpecific, Constructed: true}, "[0]"},
+	{internal.Tag{Class: internal.ClassContextSpecific, Constructed: true, LongFormOverride: 1}, "[long-form:1 0]"},
+	{internal.Tag{Class: internal.ClassContextSpecific}, "[0 PRIMITIVE]"},
+	{internal.Tag{Class: internal.ClassApplication, Constructed: true}, "[APPLICATION 0]"},
+	{internal.Tag{Class: internal.ClassApplication, Constructed: true, LongFormOverride: 1}, "[long-form:1 APPLICATION 0]"},
+	{internal.Tag{Class: internal.ClassApplication}, "[APPLICATION 0 PRIMITIVE]"},
+	{internal.Tag{Class: internal.ClassApplication, LongFormOverride: 1}, "[long-form:1 APPLICATION 0 PRIMITIVE]"},
+	{internal.Tag{Class: internal.ClassPrivate, Constructed: true}, "[PRIVATE 0]"},
+	{internal.Tag{Class: internal.ClassPrivate}, "[PRIVATE 0 PRIMITIVE]"},
 }
 
 func TestTagToString(t *testing.T) {
@@ -70,6 +72,40 @@ func TestTagToString(t *testing.T) {
 
 }
 
+func TestTagToStringWithVocabulary(t *testing.T) {
+	v := new(internal.Vocabulary)
+	if err := v.AddVocabulary("kerberos"); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Add("Foo", internal.Tag{Class: internal.ClassPrivate, Number: 5}); err != nil {
+		t.Fatal(err)
+	}
+	for i, tt := range []struct {
+		in  internal.Tag
+		out string
+	}{
+		{internal.Tag{Class: internal.ClassApplication, Number: 30, Constructed: true}, "[KRB-ERROR]"},
+		{internal.Tag{Class: internal.ClassApplication, Number: 30}, "[KRB-ERROR PRIMITIVE]"},
+		{internal.Tag{Class: internal.ClassApplication, Number: 30, Constructed: true, LongFormOverride: 2}, "[long-form:2 KRB-ERROR]"},
+		{internal.Tag{Class: internal.ClassApplication, Number: 31, Constructed: true}, "[APPLICATION 31]"},
+		{internal.Tag{Class: internal.ClassPrivate, Number: 5}, "[Foo]"},
+		{internal.Tag{Class: internal.ClassPrivate, Number: 5, Constructed: true}, "[Foo CONSTRUCTED]"},
+		{internal.Tag{Class: internal.ClassUniversal, Number: 16, Constructed: true}, "SEQUENCE"},
+	} {
+		if out := tagToStringWithVocabulary(tt.in, v); out != tt.out {
+			t.Errorf("%d. tagToStringWithVocabulary(%v) = %v, want %v.", i, tt.in, out, tt.out)
+		}
+	}
+
+	want := "vocabulary kerberos\ntag-alias Foo [PRIVATE 5 PRIMITIVE]\n\n"
+	if out := vocabularyHeader(v); out != want {
+		t.Errorf("vocabularyHeader = %q, want %q.", out, want)
+	}
+	if out := vocabularyHeader(nil); out != "" {
+		t.Errorf("vocabularyHeader(nil) = %q, want \"\".", out)
+	}
+}
+
 type convertFuncTest struct {
 	in  []byte
 	out string
@@ -267,6 +303,53 @@ var derToASCIITests = []convertFuncTest{
 		[]byte{0x01, 0x01, 0x42},
 		"BOOLEAN { `42` }\n",
 	},
+	// REALs decode into REAL tokens when canonical.
+	{
+		[]byte{0x09, 0x03, 0x80, 0xff, 0x03},
+		"REAL { real:1.5 }\n",
+	},
+	{
+		[]byte{0x09, 0x01, 0x40},
+		"REAL { real:PLUS-INFINITY }\n",
+	},
+	{
+		[]byte("\x09\x07\x0310.E+0"),
+		"REAL { `0331302e452b30` }\n",
+	},
+	{
+		[]byte("\x09\x06\x031.E-1"),
+		"REAL { real:nr3:1.E-1 }\n",
+	},
+	{
+		[]byte{0x09, 0x04, 0x80, 0x00, 0x00, 0x01},
+		"REAL { `80000001` }\n",
+	},
+	// Time types decode into typed string tokens when valid.
+	{
+		[]byte("\x1f\x1f\x0a2024-02-29"),
+		"DATE { date:2024-02-29 }\n",
+	},
+	{
+		[]byte("\x0e\x09PT1H30M/Z"),
+		"# Invalid TIME: expected 4-digit year at offset 8\nTIME { \"PT1H30M/Z\" }\n",
+	},
+	{
+		[]byte("\x1f\x1f\x0a2023-02-29"),
+		"# Invalid DATE: invalid day 29\nDATE { \"2023-02-29\" }\n",
+	},
+	// OID-IRIs are annotated with the corresponding OID, when known.
+	{
+		[]byte("\x1f\x23\x21/ISO/Member-Body/840/113549/1/1/1"),
+		"# 1.2.840.113549.1.1.1 (rsaEncryption)\nOID-IRI { oid-iri:/ISO/Member-Body/840/113549/1/1/1 }\n",
+	},
+	{
+		[]byte("\x1f\x23\x0e/ISO/Unknown/1"),
+		"OID-IRI { oid-iri:/ISO/Unknown/1 }\n",
+	},
+	{
+		[]byte("\x1f\x24\x0cExample//123"),
+		"# Invalid RELATIVE-OID-IRI: empty label\nRELATIVE-OID-IRI { \"Example//123\" }\n",
+	},
 	// BMPStrings decode into UTF-16 literals.
 	{
 		[]byte("\x1e\x14\x00h\x00e\x00l\x00l\x00o\x00 \x26\x03\x00 \xd8\x34\xdd\x1e"),
@@ -293,6 +376,35 @@ var derToASCIITests = []convertFuncTest{
 		[]byte("\x1e\x06\x00\n\x00\"\x00\\"),
 		`BMPString { u"\n\"\\" }` + "\n",
 	},
+	// Text strings with non-ASCII UTF-8 decode into UTF-8 literals.
+	{
+		[]byte("\x0c\x0fZ\xc3\xbcrich \xe2\x98\x83 \xe6\x9d\xb1"),
+		"UTF8String { u8\"Zürich ☃ 東\" }\n",
+	},
+	{
+		[]byte("\x16\x0fm\xc3\xbcller.example"),
+		"IA5String { u8\"müller.example\" }\n",
+	},
+	// ASCII text strings continue to use quoted strings.
+	{
+		[]byte("\x0c\x05hello"),
+		"UTF8String { \"hello\" }\n",
+	},
+	// Invalid UTF-8 is encoded as usual.
+	{
+		[]byte("\x0c\x0chello world\xff"),
+		"UTF8String { \"hello world\\xff\" }\n",
+	},
+	// Special and non-printable characters are escaped.
+	{
+		[]byte("\x0c\x0c\xc3\xbc\n\"\\\x00\xc2\x80\xf3\xa0\x80\x81"),
+		`UTF8String { u8"ü\n\"\\\x00\u0080\U000e0001" }` + "\n",
+	},
+	// Other types are not decoded as UTF-8.
+	{
+		[]byte("\x04\x14Z\xc3\xbcrich, Switzerland"),
+		"OCTET_STRING { \"Z\\xc3\\xbcrich, Switzerland\" }\n",
+	},
 	// UniversalStrings decode into UTF-32 literals.
 	{
 		[]byte("\x1c\x24\x00\x00\x00h\x00\x00\x00e\x00\x00\x00l\x00\x00\x00l\x00\x00\x00o\x00\x00\x00 \x00\x00\x26\x03\x00\x00\x00 \x00\x01\xd1\x1e"),
@@ -419,3 +531,46 @@ SEQUENCE {
 func TestDERToASCII(t *testing.T) {
 	testConvertFunc(t, "derToASCII", derToASCII, derToASCIITests)
 }
+
+var pemBlockToASCIITests = []struct {
+	in  pem.Block
+	out string
+}{
+	{
+		pem.Block{Type: "CERTIFICATE", Bytes: []byte{0x30, 0x03, 0x02, 0x01, 0x01}},
+		`pem "CERTIFICATE" {
+  SEQUENCE {
+    INTEGER { 1 }
+  }
+}
+`,
+	},
+	{
+		pem.Block{Type: "EMPTY"},
+		"pem \"EMPTY\" {}\n",
+	},
+	// Headers are written in the same order as pem.Encode.
+	{
+		pem.Block{
+			Type: "RSA PRIVATE KEY",
+			Headers: map[string]string{
+				"DEK-Info":  "AES-128-CBC,00",
+				"Proc-Type": "4,ENCRYPTED",
+				"Comment":   "\"quoted\"",
+			},
+			Bytes: []byte{0xff},
+		},
+		`pem "RSA PRIVATE KEY" header "Proc-Type" "4,ENCRYPTED" header "Comment" "\"quoted\"" header "DEK-Info" "AES-128-CBC,00" {
+  ` + "`ff`" + `
+}
+`,
+	},
+}
+
+func TestPEMBlockToASCII(t *testing.T) {
+	for i, tt := range pemBlockToASCIITests {
+		if out := pemBlockToASCII(&tt.in, nil, defaultLimits, nil); out != tt.out {
+			t.Errorf("%d. pemBlockToASCII(%v) = %q, want %q.", i, tt.in, out, tt.out)
+		}
+	}
+}
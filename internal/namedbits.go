@@ -0,0 +1,133 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import "fmt"
+
+// namedBitTypes is the registry of BIT STRING types with named bits. Each type
+// lists its bit names by position, with empty strings for unnamed positions.
+var namedBitTypes = map[string][]string{
+	// RFC 5280, section 4.2.1.3.
+	"keyUsage": {
+		"digitalSignature",
+		"nonRepudiation",
+		"keyEncipherment",
+		"dataEncipherment",
+		"keyAgreement",
+		"keyCertSign",
+		"cRLSign",
+		"encipherOnly",
+		"decipherOnly",
+	},
+	// Netscape certificate extensions.
+	"netscapeCertType": {
+		"sslClient",
+		"sslServer",
+		"smime",
+		"objectSigning",
+		"reserved",
+		"sslCA",
+		"smimeCA",
+		"objectSigningCA",
+	},
+	// RFC 5280, section 4.2.1.13.
+	"reasonFlags": {
+		"unused",
+		"keyCompromise",
+		"cACompromise",
+		"affiliationChanged",
+		"superseded",
+		"cessationOfOperation",
+		"certificateHold",
+		"privilegeWithdrawn",
+		"aACompromise",
+	},
+	// RFC 4120, section 5.3.
+	"ticketFlags": {
+		"reserved",
+		"forwardable",
+		"forwarded",
+		"proxiable",
+		"proxy",
+		"may-postdate",
+		"postdated",
+		"invalid",
+		"renewable",
+		"initial",
+		"pre-authent",
+		"hw-authent",
+		"transited-policy-checked",
+		"ok-as-delegate",
+	},
+	// RFC 4120, section 5.4.1.
+	"kdcOptions": {
+		0:  "reserved",
+		1:  "forwardable",
+		2:  "forwarded",
+		3:  "proxiable",
+		4:  "proxy",
+		5:  "allow-postdate",
+		6:  "postdated",
+		7:  "unused7",
+		8:  "renewable",
+		9:  "unused9",
+		10: "unused10",
+		11: "opt-hardware-auth",
+		26: "disable-transited-check",
+		27: "renewable-ok",
+		28: "enc-tkt-in-skey",
+		30: "renew",
+		31: "validate",
+	},
+	// RFC 4120, section 5.5.1.
+	"apOptions": {
+		"reserved",
+		"use-session-key",
+		"mutual-required",
+	},
+}
+
+// IsNamedBitType returns whether typ is the name of a BIT STRING type in the
+// named bit registry, such as "keyUsage".
+func IsNamedBitType(typ string) bool {
+	_, ok := namedBitTypes[typ]
+	return ok
+}
+
+// LookupNamedBit returns the position of the bit with the specified name in
+// typ.
+func LookupNamedBit(typ, name string) (int, error) {
+	names, ok := namedBitTypes[typ]
+	if !ok {
+		return 0, fmt.Errorf("unknown named bit type %q", typ)
+	}
+	if name != "" {
+		for i, n := range names {
+			if n == name {
+				return i, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unknown bit %q in %s", name, typ)
+}
+
+// NamedBit returns the name of the bit at position bit in typ, if any.
+func NamedBit(typ string, bit int) (string, bool) {
+	names := namedBitTypes[typ]
+	if bit < 0 || bit >= len(names) || names[bit] == "" {
+		return "", false
+	}
+	return names[bit], true
+}
@@ -0,0 +1,55 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import "testing"
+
+func TestNamedBits(t *testing.T) {
+	for typ, names := range namedBitTypes {
+		for i, name := range names {
+			if name == "" {
+				if got, ok := NamedBit(typ, i); ok {
+					t.Errorf("NamedBit(%q, %d) = %q, wanted none", typ, i, got)
+				}
+				continue
+			}
+			if got, ok := NamedBit(typ, i); !ok || got != name {
+				t.Errorf("NamedBit(%q, %d) = %q, %v, wanted %q", typ, i, got, ok, name)
+			}
+			if got, err := LookupNamedBit(typ, name); err != nil || got != i {
+				t.Errorf("LookupNamedBit(%q, %q) = %d, %v, wanted %d", typ, name, got, err, i)
+			}
+		}
+		if _, ok := NamedBit(typ, -1); ok {
+			t.Errorf("NamedBit(%q, -1) unexpectedly succeeded", typ)
+		}
+		if _, ok := NamedBit(typ, len(names)); ok {
+			t.Errorf("NamedBit(%q, %d) unexpectedly succeeded", typ, len(names))
+		}
+		if _, err := LookupNamedBit(typ, ""); err == nil {
+			t.Errorf("LookupNamedBit(%q, \"\") unexpectedly succeeded", typ)
+		}
+	}
+
+	if !IsNamedBitType("keyUsage") || IsNamedBitType("KeyUsage") {
+		t.Errorf("IsNamedBitType is case-insensitive or missing keyUsage")
+	}
+	if got, err := LookupNamedBit("kdcOptions", "validate"); err != nil || got != 31 {
+		t.Errorf("LookupNamedBit(\"kdcOptions\", \"validate\") = %d, %v, wanted 31", got, err)
+	}
+	if _, err := LookupNamedBit("bogus", "validate"); err == nil {
+		t.Errorf("LookupNamedBit(\"bogus\", \"validate\") unexpectedly succeeded")
+	}
+}
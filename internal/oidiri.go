@@ -0,0 +1,167 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// oidIRILabels is the registry of non-integer Unicode labels, from X.660,
+// used to convert between OID-IRIs and dotted OIDs.
+var oidIRILabels = []struct {
+	oid   string
+	label string
+}{
+	{"0", "ITU-T"},
+	{"1", "ISO"},
+	{"2", "Joint-ISO-ITU-T"},
+	{"0.0", "Recommendation"},
+	{"0.1", "Question"},
+	{"0.2", "Administration"},
+	{"0.3", "Network-Operator"},
+	{"0.4", "Identified-Organization"},
+	{"1.0", "Standard"},
+	{"1.1", "Registration-Authority"},
+	{"1.2", "Member-Body"},
+	{"1.3", "Identified-Organization"},
+	{"2.25", "UUID"},
+	{"2.999", "Example"},
+}
+
+// isIntegerLabel returns whether s is an integer-valued Unicode label: a
+// decimal integer with no leading zeros.
+func isIntegerLabel(s string) bool {
+	if len(s) == 0 || (s[0] == '0' && len(s) > 1) {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func validateLabel(s string) error {
+	if len(s) == 0 {
+		return errors.New("empty label")
+	}
+	if !utf8.ValidString(s) {
+		return errors.New("invalid UTF-8")
+	}
+	isDigits := true
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			isDigits = false
+		}
+		if r < 0x80 {
+			if !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' || strings.ContainsRune("-._~", r)) {
+				return fmt.Errorf("invalid character %q in label %q", r, s)
+			}
+		} else if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r) {
+			return fmt.Errorf("invalid character %q in label %q", r, s)
+		}
+	}
+	if isDigits && !isIntegerLabel(s) {
+		return fmt.Errorf("integer label %q has leading zeros", s)
+	}
+	if s[0] == '-' || s[len(s)-1] == '-' {
+		return fmt.Errorf("label %q begins or ends with '-'", s)
+	}
+	return nil
+}
+
+// ParseOIDIRI checks that s is an OID-IRI, or a RELATIVE-OID-IRI if relative
+// is true, and returns its Unicode labels.
+func ParseOIDIRI(s string, relative bool) ([]string, error) {
+	if !relative {
+		var ok bool
+		if s, ok = strings.CutPrefix(s, "/"); !ok {
+			return nil, errors.New("OID-IRI does not begin with '/'")
+		}
+	}
+	labels := strings.Split(s, "/")
+	for _, l := range labels {
+		if err := validateLabel(l); err != nil {
+			return nil, err
+		}
+	}
+	return labels, nil
+}
+
+// OIDIRIToDotted converts s, an OID-IRI, to a dotted OID. Non-integer labels
+// must be in the registry.
+func OIDIRIToDotted(s string) (string, error) {
+	labels, err := ParseOIDIRI(s, false)
+	if err != nil {
+		return "", err
+	}
+	var oid string
+	for _, l := range labels {
+		arc := l
+		if !isIntegerLabel(l) {
+			arc = ""
+			for _, e := range oidIRILabels {
+				i := strings.LastIndexByte(e.oid, '.')
+				parent := ""
+				if i >= 0 {
+					parent = e.oid[:i]
+				}
+				if e.label == l && parent == oid {
+					arc = e.oid[i+1:]
+					break
+				}
+			}
+			if arc == "" {
+				return "", fmt.Errorf("unknown label %q", l)
+			}
+		}
+		if oid != "" {
+			oid += "."
+		}
+		oid += arc
+	}
+	return oid, nil
+}
+
+// DottedToOIDIRI converts s, a dotted OID, to an OID-IRI. Arcs are written with
+// their registered Unicode labels, if any, and as integers otherwise.
+func DottedToOIDIRI(s string) (string, error) {
+	var b strings.Builder
+	var oid string
+	for _, arc := range strings.Split(s, ".") {
+		if !isIntegerLabel(arc) {
+			return "", fmt.Errorf("invalid OID component %q", arc)
+		}
+		if oid != "" {
+			oid += "."
+		}
+		oid += arc
+		label := arc
+		for _, e := range oidIRILabels {
+			if e.oid == oid {
+				label = e.label
+				break
+			}
+		}
+		b.WriteString("/")
+		b.WriteString(label)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,95 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import "testing"
+
+var parseOIDIRITests = []struct {
+	input    string
+	relative bool
+	ok       bool
+}{
+	{"/ISO/Member-Body/840", false, true},
+	{"/Joint-ISO-ITU-T/Example/Ελλάδα", false, true},
+	{"/a.b_c~d", false, true},
+	{"/0", false, true},
+	{"ISO/Member-Body", false, false},
+	{"ISO/Member-Body", true, true},
+	{"/ISO", true, false},
+	{"/", false, false},
+	{"/ISO//840", false, false},
+	{"/ISO/", false, false},
+	{"/01", false, false},
+	{"/-a", false, false},
+	{"/a-", false, false},
+	{"/a b", false, false},
+	{"/a\xff", false, false},
+	{"", true, false},
+}
+
+func TestParseOIDIRI(t *testing.T) {
+	for i, tt := range parseOIDIRITests {
+		if _, err := ParseOIDIRI(tt.input, tt.relative); (err == nil) != tt.ok {
+			t.Errorf("%d. ParseOIDIRI(%q, %v) = %v, wanted success=%v", i, tt.input, tt.relative, err, tt.ok)
+		}
+	}
+}
+
+var oidIRIToDottedTests = []struct {
+	input string
+	out   string
+	ok    bool
+}{
+	{"/ISO/Member-Body/840/113549", "1.2.840.113549", true},
+	{"/1/2/840/113549", "1.2.840.113549", true},
+	{"/Joint-ISO-ITU-T/Example", "2.999", true},
+	{"/ITU-T/Identified-Organization/4", "0.4.4", true},
+	{"/ISO/Identified-Organization/6", "1.3.6", true},
+	{"/ISO/Example", "", false},
+	{"/ISO/Member-Body/Unknown", "", false},
+	{"ISO", "", false},
+}
+
+func TestOIDIRIToDotted(t *testing.T) {
+	for i, tt := range oidIRIToDottedTests {
+		out, err := OIDIRIToDotted(tt.input)
+		if out != tt.out || (err == nil) != tt.ok {
+			t.Errorf("%d. OIDIRIToDotted(%q) = %q, err=%v, wanted %q, success=%v", i, tt.input, out, err, tt.out, tt.ok)
+		}
+	}
+}
+
+var dottedToOIDIRITests = []struct {
+	input string
+	out   string
+	ok    bool
+}{
+	{"1.2.840.113549", "/ISO/Member-Body/840/113549", true},
+	{"2.999.1", "/Joint-ISO-ITU-T/Example/1", true},
+	{"2.5.4.3", "/Joint-ISO-ITU-T/5/4/3", true},
+	{"3", "/3", true},
+	{"1.02", "", false},
+	{"1..2", "", false},
+	{"", "", false},
+}
+
+func TestDottedToOIDIRI(t *testing.T) {
+	for i, tt := range dottedToOIDIRITests {
+		out, err := DottedToOIDIRI(tt.input)
+		if out != tt.out || (err == nil) != tt.ok {
+			t.Errorf("%d. DottedToOIDIRI(%q) = %q, err=%v, wanted %q, success=%v", i, tt.input, out, err, tt.out, tt.ok)
+		}
+	}
+}
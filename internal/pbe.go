@@ -0,0 +1,128 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"crypto/hmac"
+	"errors"
+	"hash"
+	"math/big"
+	"unicode/utf16"
+)
+
+// This file implements the password-based key derivation functions used by
+// PKCS #5 and PKCS #12.
+
+// PBKDF2 derives a keyLen-byte key from password and salt, as specified in RFC
+// 8018, section 5.2, using HMAC with h as the PRF.
+func PBKDF2(h func() hash.Hash, password, salt []byte, iterations, keyLen int) []byte {
+	prf := hmac.New(h, password)
+	var out []byte
+	for block := uint32(1); len(out) < keyLen; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		prf.Write([]byte{byte(block >> 24), byte(block >> 16), byte(block >> 8), byte(block)})
+		u := prf.Sum(nil)
+		t := append([]byte{}, u...)
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		out = append(out, t...)
+	}
+	return out[:keyLen]
+}
+
+// PKCS #12 key derivation IDs, from RFC 7292, appendix B.3.
+const (
+	PKCS12KeyID = 1
+	PKCS12IVID  = 2
+	PKCS12MACID = 3
+)
+
+// PKCS12Password encodes password as a PKCS #12 password, a NUL-terminated
+// BMPString.
+func PKCS12Password(password string) []byte {
+	var out []byte
+	for _, r := range utf16.Encode([]rune(password)) {
+		out = append(out, byte(r>>8), byte(r))
+	}
+	return append(out, 0, 0)
+}
+
+// fillBlocks returns in repeated to fill a whole number of v-byte blocks.
+func fillBlocks(in []byte, v int) []byte {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make([]byte, v*((len(in)+v-1)/v))
+	for i := range out {
+		out[i] = in[i%len(in)]
+	}
+	return out
+}
+
+// PKCS12KDF derives size bytes of key material from password, an encoded
+// PKCS #12 password, as specified in RFC 7292, appendix B.2.
+func PKCS12KDF(h func() hash.Hash, id byte, password, salt []byte, iterations, size int) ([]byte, error) {
+	if iterations < 1 {
+		return nil, errors.New("invalid iteration count")
+	}
+	d := h()
+	v := d.BlockSize()
+
+	diversifier := make([]byte, v)
+	for i := range diversifier {
+		diversifier[i] = id
+	}
+	in := append(fillBlocks(salt, v), fillBlocks(password, v)...)
+
+	var out []byte
+	one := big.NewInt(1)
+	for {
+		d.Reset()
+		d.Write(diversifier)
+		d.Write(in)
+		a := d.Sum(nil)
+		for i := 1; i < iterations; i++ {
+			d.Reset()
+			d.Write(a)
+			a = d.Sum(a[:0])
+		}
+		out = append(out, a...)
+		if len(out) >= size {
+			return out[:size], nil
+		}
+
+		// Set each v-byte block of in to in + B + 1, where B is a
+		// repeated to fill v bytes.
+		b := new(big.Int).SetBytes(fillBlocks(a, v))
+		b.Add(b, one)
+		for j := 0; j < len(in); j += v {
+			block := new(big.Int).SetBytes(in[j : j+v])
+			block.Add(block, b)
+			sum := block.Bytes()
+			if len(sum) > v {
+				sum = sum[len(sum)-v:]
+			}
+			copy(in[j:j+v], make([]byte, v-len(sum)))
+			copy(in[j+v-len(sum):j+v], sum)
+		}
+	}
+}
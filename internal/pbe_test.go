@@ -0,0 +1,77 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"testing"
+)
+
+// pbkdf2Tests are the test vectors from RFC 6070.
+var pbkdf2Tests = []struct {
+	password, salt string
+	iterations     int
+	key            string
+}{
+	{"password", "salt", 1, "0c60c80f961f0e71f3a9b524af6012062fe037a6"},
+	{"password", "salt", 2, "ea6c014dc72d6f8ccd1ed92ace1d41f0d8de8957"},
+	{"password", "salt", 4096, "4b007901b765489abead49d926f721d065a429c1"},
+	{"passwordPASSWORDpassword", "saltSALTsaltSALTsaltSALTsaltSALTsalt", 4096, "3d2eec4fe41c849b80c8d83662c0e44a8b291a964cf2f07038"},
+	{"pass\x00word", "sa\x00lt", 4096, "56fa6aa75548099dcc37d7f03425e0c3"},
+}
+
+func TestPBKDF2(t *testing.T) {
+	for i, tt := range pbkdf2Tests {
+		want, _ := hex.DecodeString(tt.key)
+		key := PBKDF2(sha1.New, []byte(tt.password), []byte(tt.salt), tt.iterations, len(want))
+		if !bytes.Equal(key, want) {
+			t.Errorf("%d. PBKDF2 = %x, want %x", i, key, want)
+		}
+	}
+}
+
+var pkcs12KDFTests = []struct {
+	password   string
+	id         byte
+	salt       string
+	iterations int
+	key        string
+}{
+	{"queeg", PKCS12KeyID, "05dec959acff72f7", 1000, "ed2034e36328830ff09df1e1a07dd357185dac0d4f9eb3d4"},
+	{"queeg", PKCS12IVID, "05dec959acff72f7", 1000, "11dedad7758d4860"},
+}
+
+func TestPKCS12KDF(t *testing.T) {
+	for i, tt := range pkcs12KDFTests {
+		salt, _ := hex.DecodeString(tt.salt)
+		want, _ := hex.DecodeString(tt.key)
+		key, err := PKCS12KDF(sha1.New, tt.id, PKCS12Password(tt.password), salt, tt.iterations, len(want))
+		if err != nil {
+			t.Errorf("%d. PKCS12KDF failed: %s", i, err)
+			continue
+		}
+		if !bytes.Equal(key, want) {
+			t.Errorf("%d. PKCS12KDF = %x, want %x", i, key, want)
+		}
+	}
+}
+
+func TestPKCS12Password(t *testing.T) {
+	if got, want := PKCS12Password("Beavis"), []byte{0, 'B', 0, 'e', 0, 'a', 0, 'v', 0, 'i', 0, 's', 0, 0}; !bytes.Equal(got, want) {
+		t.Errorf("PKCS12Password = %x, want %x", got, want)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// rc2Tests are the test vectors from RFC 2268, section 5.
+var rc2Tests = []struct {
+	key           string
+	effectiveBits int
+	plaintext     string
+	ciphertext    string
+}{
+	{"0000000000000000", 63, "0000000000000000", "ebb773f993278eff"},
+	{"ffffffffffffffff", 64, "ffffffffffffffff", "278b27e42e2f0d49"},
+	{"3000000000000000", 64, "1000000000000001", "30649edf9be7d2c2"},
+	{"88", 64, "0000000000000000", "61a8a244adacccf0"},
+	{"88bca90e90875a", 64, "0000000000000000", "6ccf4308974c267f"},
+	{"88bca90e90875a7f0f79c384627bafb2", 64, "0000000000000000", "1a807d272bbe5db1"},
+	{"88bca90e90875a7f0f79c384627bafb2", 128, "0000000000000000", "2269552ab0f85ca6"},
+	{"88bca90e90875a7f0f79c384627bafb216f80a6f85920584c42fceb0be255daf1e", 129, "0000000000000000", "5b78d3a43dfff1f1"},
+}
+
+func TestRC2(t *testing.T) {
+	for i, tt := range rc2Tests {
+		key, _ := hex.DecodeString(tt.key)
+		plaintext, _ := hex.DecodeString(tt.plaintext)
+		ciphertext, _ := hex.DecodeString(tt.ciphertext)
+		c, err := NewRC2Cipher(key, tt.effectiveBits)
+		if err != nil {
+			t.Errorf("%d. NewRC2Cipher failed: %s", i, err)
+			continue
+		}
+		out := make([]byte, 8)
+		c.Encrypt(out, plaintext)
+		if !bytes.Equal(out, ciphertext) {
+			t.Errorf("%d. Encrypt(%x) = %x, want %x", i, plaintext, out, ciphertext)
+		}
+		c.Decrypt(out, ciphertext)
+		if !bytes.Equal(out, plaintext) {
+			t.Errorf("%d. Decrypt(%x) = %x, want %x", i, ciphertext, out, plaintext)
+		}
+	}
+}
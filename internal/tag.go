@@ -15,6 +15,8 @@
 // Package internal contains common routines between der2ascii and ascii2der.
 package internal
 
+import "strconv"
+
 type Class byte
 
 const (
@@ -26,18 +28,36 @@ const (
 
 type Tag struct {
 	Class       Class
-	Number      uint32
+	Number      uint64
 	Constructed bool
 	// LongFormOverride, if non-zero, is how many bytes this tag is encoded
 	// with in long form, excluding the initial byte.
 	LongFormOverride int
+	// BigNumber, if not empty, is the tag number in decimal, when it is too
+	// large for Number. Number is then zero. It is a string so that Tag
+	// remains comparable.
+	BigNumber string
+}
+
+// MaxBase128Length is the maximum length, in bytes, of the base-128 encoding of
+// a tag number or OID component. Converting between base-128 and decimal takes
+// time quadratic in the length, so larger values are not converted: der2ascii
+// writes them as hex and ascii2der rejects them.
+const MaxBase128Length = 1024
+
+// NumberString returns the tag number in decimal.
+func (t Tag) NumberString() string {
+	if t.BigNumber != "" {
+		return t.BigNumber
+	}
+	return strconv.FormatUint(t.Number, 10)
 }
 
 // GetAlias looks up the alias for the given tag. If one exists, it returns the
 // name and sets toggleConstructed if the tag's constructed bit does not match
 // the alias's default. Otherwise it sets ok to false.
 func (t Tag) GetAlias() (name string, toggleConstructed bool, ok bool) {
-	if t.Class != ClassUniversal {
+	if t.Class != ClassUniversal || t.BigNumber != "" {
 		return
 	}
 	for _, u := range universalTags {
@@ -52,7 +72,7 @@ func (t Tag) GetAlias() (name string, toggleConstructed bool, ok bool) {
 }
 
 var universalTags = []struct {
-	number      uint32
+	number      uint64
 	name        string
 	constructed bool
 }{
@@ -98,7 +118,7 @@ var universalTags = []struct {
 func TagByName(name string) (Tag, bool) {
 	for _, u := range universalTags {
 		if u.name == name {
-			return Tag{ClassUniversal, u.number, u.constructed, 0}, true
+			return Tag{ClassUniversal, u.number, u.constructed, 0, ""}, true
 		}
 	}
 	return Tag{}, false
@@ -22,14 +22,15 @@ var tagGetAliasTests = []struct {
 	toggleConstructed bool
 	ok                bool
 }{
-	{Tag{ClassUniversal, 16, true, 0}, "SEQUENCE", false, true},
-	{Tag{ClassUniversal, 16, true, 5}, "SEQUENCE", false, true},
-	{Tag{ClassUniversal, 16, false, 0}, "SEQUENCE", true, true},
-	{Tag{ClassUniversal, 16, false, 5}, "SEQUENCE", true, true},
-	{Tag{ClassUniversal, 2, true, 0}, "INTEGER", true, true},
-	{Tag{ClassUniversal, 2, false, 0}, "INTEGER", false, true},
-	{Tag{ClassApplication, 2, false, 0}, "", false, false},
-	{Tag{ClassUniversal, 0, false, 0}, "", false, false},
+	{Tag{ClassUniversal, 16, true, 0, ""}, "SEQUENCE", false, true},
+	{Tag{ClassUniversal, 16, true, 5, ""}, "SEQUENCE", false, true},
+	{Tag{ClassUniversal, 16, false, 0, ""}, "SEQUENCE", true, true},
+	{Tag{ClassUniversal, 16, false, 5, ""}, "SEQUENCE", true, true},
+	{Tag{ClassUniversal, 2, true, 0, ""}, "INTEGER", true, true},
+	{Tag{ClassUniversal, 2, false, 0, ""}, "INTEGER", false, true},
+	{Tag{ClassApplication, 2, false, 0, ""}, "", false, false},
+	{Tag{ClassUniversal, 0, false, 0, ""}, "", false, false},
+	{Tag{ClassUniversal, 0, false, 0, "18446744073709551618"}, "", false, false},
 }
 
 func TestTagGetAlias(t *testing.T) {
@@ -53,10 +54,10 @@ var tagByNameTests = []struct {
 	ok   bool
 }{
 	{"BOGUS", Tag{}, false},
-	{"SEQUENCE", Tag{ClassUniversal, 16, true, 0}, true},
-	{"INTEGER", Tag{ClassUniversal, 2, false, 0}, true},
+	{"SEQUENCE", Tag{ClassUniversal, 16, true, 0, ""}, true},
+	{"INTEGER", Tag{ClassUniversal, 2, false, 0, ""}, true},
 	{"OCTET STRING", Tag{}, false},
-	{"OCTET_STRING", Tag{ClassUniversal, 4, false, 0}, true},
+	{"OCTET_STRING", Tag{ClassUniversal, 4, false, 0, ""}, true},
 }
 
 func TestTagByName(t *testing.T) {
@@ -0,0 +1,423 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TimeTypes are the aliases of the ASN.1 time types, whose values are ISO 8601
+// strings.
+var TimeTypes = []string{"TIME", "DATE", "TIME-OF-DAY", "DATE-TIME", "DURATION"}
+
+// ValidateTime returns an error if s is not a valid value of the time type
+// with the given alias. DATE, TIME-OF-DAY, and DATE-TIME values must be of the
+// forms YYYY-MM-DD, HH:MM:SS, and YYYY-MM-DDTHH:MM:SS, respectively. DURATION
+// values must be ISO 8601 durations. TIME values may be any ISO 8601 date,
+// time of day, date and time, duration, interval, or recurring interval in the
+// extended format.
+func ValidateTime(name, s string) error {
+	p := timeParser{s: s}
+	var err error
+	switch name {
+	case "TIME":
+		err = p.parseTime()
+	case "DATE":
+		err = p.parseCalendarDate()
+	case "TIME-OF-DAY":
+		err = p.parseTimeOfDay()
+	case "DATE-TIME":
+		if err = p.parseCalendarDate(); err == nil {
+			if err = p.expect('T'); err == nil {
+				err = p.parseTimeOfDay()
+			}
+		}
+	case "DURATION":
+		err = p.parseDuration()
+	default:
+		return fmt.Errorf("%s is not a time type", name)
+	}
+	if err == nil && !p.done() {
+		err = fmt.Errorf("unexpected %q", p.s[p.pos:])
+	}
+	return err
+}
+
+// A timeParser parses ISO 8601 strings.
+type timeParser struct {
+	s   string
+	pos int
+}
+
+func (p *timeParser) done() bool {
+	return p.pos >= len(p.s)
+}
+
+func (p *timeParser) peek() byte {
+	if p.done() {
+		return 0
+	}
+	return p.s[p.pos]
+}
+
+// consume advances past c and returns true if it is the next byte. Otherwise,
+// it returns false.
+func (p *timeParser) consume(c byte) bool {
+	if !p.done() && p.s[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *timeParser) expect(c byte) error {
+	if !p.consume(c) {
+		return fmt.Errorf("expected %q at offset %d", c, p.pos)
+	}
+	return nil
+}
+
+// countDigits returns the number of consecutive digits at the current
+// position.
+func (p *timeParser) countDigits() int {
+	n := 0
+	for p.pos+n < len(p.s) && '0' <= p.s[p.pos+n] && p.s[p.pos+n] <= '9' {
+		n++
+	}
+	return n
+}
+
+// parseNumber parses n digits and checks the value is between min and max.
+func (p *timeParser) parseNumber(what string, n, min, max int) (int, error) {
+	if p.countDigits() < n {
+		return 0, fmt.Errorf("expected %d-digit %s at offset %d", n, what, p.pos)
+	}
+	v := 0
+	for _, c := range p.s[p.pos : p.pos+n] {
+		v = v*10 + int(c-'0')
+	}
+	if v < min || v > max {
+		return 0, fmt.Errorf("invalid %s %s", what, p.s[p.pos:p.pos+n])
+	}
+	p.pos += n
+	return v, nil
+}
+
+// parseFraction parses an optional decimal fraction.
+func (p *timeParser) parseFraction() error {
+	if !p.consume('.') && !p.consume(',') {
+		return nil
+	}
+	n := p.countDigits()
+	if n == 0 {
+		return fmt.Errorf("expected fraction at offset %d", p.pos)
+	}
+	p.pos += n
+	return nil
+}
+
+func isLeapYear(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
+
+// weeksInYear returns the number of ISO 8601 weeks in year. A year has 53
+// weeks if it starts on a Thursday, or on a Wednesday in a leap year.
+func weeksInYear(year int) int {
+	switch time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC).Weekday() {
+	case time.Thursday:
+		return 53
+	case time.Wednesday:
+		if isLeapYear(year) {
+			return 53
+		}
+	}
+	return 52
+}
+
+func daysInMonth(year, month int) int {
+	switch month {
+	case 2:
+		if isLeapYear(year) {
+			return 29
+		}
+		return 28
+	case 4, 6, 9, 11:
+		return 30
+	}
+	return 31
+}
+
+// parseCalendarDate parses a date of the form YYYY-MM-DD.
+func (p *timeParser) parseCalendarDate() error {
+	year, err := p.parseNumber("year", 4, 0, 9999)
+	if err != nil {
+		return err
+	}
+	if err := p.expect('-'); err != nil {
+		return err
+	}
+	month, err := p.parseNumber("month", 2, 1, 12)
+	if err != nil {
+		return err
+	}
+	if err := p.expect('-'); err != nil {
+		return err
+	}
+	_, err = p.parseNumber("day", 2, 1, daysInMonth(year, month))
+	return err
+}
+
+// parseTimeOfDay parses a time of day of the form HH:MM:SS.
+func (p *timeParser) parseTimeOfDay() error {
+	hour, err := p.parseNumber("hour", 2, 0, 24)
+	if err != nil {
+		return err
+	}
+	if err := p.expect(':'); err != nil {
+		return err
+	}
+	minute, err := p.parseNumber("minute", 2, 0, 59)
+	if err != nil {
+		return err
+	}
+	if err := p.expect(':'); err != nil {
+		return err
+	}
+	second, err := p.parseNumber("second", 2, 0, 60)
+	if err != nil {
+		return err
+	}
+	if hour == 24 && (minute != 0 || second != 0) {
+		return errors.New("invalid time after 24:00:00")
+	}
+	return nil
+}
+
+// parseDate parses a calendar, ordinal, or week date, or a reduced-precision
+// calendar or week date. If full is true, reduced precision is not allowed.
+func (p *timeParser) parseDate(full bool) error {
+	year, err := p.parseNumber("year", 4, 0, 9999)
+	if err != nil {
+		return err
+	}
+	if !p.consume('-') {
+		if full {
+			return fmt.Errorf("expected '-' at offset %d", p.pos)
+		}
+		return nil
+	}
+	if p.consume('W') {
+		// Week date.
+		if _, err := p.parseNumber("week", 2, 1, weeksInYear(year)); err != nil {
+			return err
+		}
+		if !p.consume('-') {
+			if full {
+				return fmt.Errorf("expected '-' at offset %d", p.pos)
+			}
+			return nil
+		}
+		_, err := p.parseNumber("weekday", 1, 1, 7)
+		return err
+	}
+	if p.countDigits() == 3 {
+		// Ordinal date.
+		days := 365
+		if isLeapYear(year) {
+			days = 366
+		}
+		_, err := p.parseNumber("day", 3, 1, days)
+		return err
+	}
+	month, err := p.parseNumber("month", 2, 1, 12)
+	if err != nil {
+		return err
+	}
+	if !p.consume('-') {
+		if full {
+			return fmt.Errorf("expected '-' at offset %d", p.pos)
+		}
+		return nil
+	}
+	_, err = p.parseNumber("day", 2, 1, daysInMonth(year, month))
+	return err
+}
+
+// parseTimeWithZone parses a time of day of the forms HH, HH:MM, or HH:MM:SS,
+// with an optional fraction and time zone.
+func (p *timeParser) parseTimeWithZone() error {
+	hour, err := p.parseNumber("hour", 2, 0, 24)
+	if err != nil {
+		return err
+	}
+	nonZero := false
+	if p.consume(':') {
+		minute, err := p.parseNumber("minute", 2, 0, 59)
+		if err != nil {
+			return err
+		}
+		nonZero = minute != 0
+		if p.consume(':') {
+			second, err := p.parseNumber("second", 2, 0, 60)
+			if err != nil {
+				return err
+			}
+			nonZero = nonZero || second != 0
+		}
+	}
+	start := p.pos
+	if err := p.parseFraction(); err != nil {
+		return err
+	}
+	if strings.Trim(p.s[start:p.pos], ".,0") != "" {
+		nonZero = true
+	}
+	if hour == 24 && nonZero {
+		return errors.New("invalid time after 24:00:00")
+	}
+
+	// Parse the time zone, if any.
+	if p.consume('Z') {
+		return nil
+	}
+	if p.consume('+') || p.consume('-') {
+		if _, err := p.parseNumber("time zone hour", 2, 0, 23); err != nil {
+			return err
+		}
+		if p.consume(':') {
+			if _, err := p.parseNumber("time zone minute", 2, 0, 59); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// parseDuration parses a duration of the forms PnW or PnYnMnDTnHnMnS, where
+// components may be omitted and the last component may have a fraction.
+func (p *timeParser) parseDuration() error {
+	if err := p.expect('P'); err != nil {
+		return err
+	}
+	const dateUnits, timeUnits = "YMD", "HMS"
+	units := dateUnits
+	inTime := false
+	components := 0
+	for !p.done() && p.peek() != '/' {
+		if !inTime && p.consume('T') {
+			if p.countDigits() == 0 {
+				return fmt.Errorf("expected time component at offset %d", p.pos)
+			}
+			units = timeUnits
+			inTime = true
+			continue
+		}
+		n := p.countDigits()
+		if n == 0 {
+			return fmt.Errorf("expected number at offset %d", p.pos)
+		}
+		p.pos += n
+		fracStart := p.pos
+		if err := p.parseFraction(); err != nil {
+			return err
+		}
+		hasFraction := p.pos != fracStart
+		if components == 0 && !inTime && p.consume('W') {
+			components++
+			if !p.done() && p.peek() != '/' {
+				return errors.New("weeks may not be combined with other components")
+			}
+			break
+		}
+		i := strings.IndexByte(units, p.peek())
+		if p.done() || i < 0 {
+			return fmt.Errorf("expected duration unit at offset %d", p.pos)
+		}
+		p.pos++
+		units = units[i+1:]
+		components++
+		if hasFraction && !p.done() && p.peek() != '/' {
+			return errors.New("only the last component may have a fraction")
+		}
+	}
+	if components == 0 {
+		return errors.New("duration has no components")
+	}
+	return nil
+}
+
+// parseTimePoint parses a date, a time of day, or a date and time.
+func (p *timeParser) parseTimePoint() error {
+	// A time of day is two digits followed by ':', a fraction, a time zone,
+	// or the end of the string. Otherwise, the value begins with a year.
+	if p.countDigits() == 2 {
+		return p.parseTimeWithZone()
+	}
+	start := p.pos
+	if err := p.parseDate(false); err != nil {
+		return err
+	}
+	if p.consume('T') {
+		// Reduced precision is not allowed with a time.
+		p.pos = start
+		if err := p.parseDate(true); err != nil {
+			return err
+		}
+		p.pos++
+		return p.parseTimeWithZone()
+	}
+	return nil
+}
+
+// parseIntervalPart parses a time point or duration.
+func (p *timeParser) parseIntervalPart() (isDuration bool, err error) {
+	if p.peek() == 'P' {
+		return true, p.parseDuration()
+	}
+	return false, p.parseTimePoint()
+}
+
+// parseTime parses any TIME value.
+func (p *timeParser) parseTime() error {
+	recurring := p.consume('R')
+	if recurring {
+		// Recurring interval, with an optional number of repetitions.
+		p.pos += p.countDigits()
+		if err := p.expect('/'); err != nil {
+			return err
+		}
+	}
+	startIsDuration, err := p.parseIntervalPart()
+	if err != nil {
+		return err
+	}
+	if !p.consume('/') {
+		if recurring {
+			return errors.New("recurring interval has no end")
+		}
+		return nil
+	}
+	endIsDuration, err := p.parseIntervalPart()
+	if err != nil {
+		return err
+	}
+	if startIsDuration && endIsDuration {
+		return errors.New("interval may not consist of two durations")
+	}
+	return nil
+}
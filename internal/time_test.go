@@ -0,0 +1,88 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import "testing"
+
+var validateTimeTests = []struct {
+	name  string
+	value string
+	ok    bool
+}{
+	{"DATE", "2024-02-29", true},
+	{"DATE", "2023-02-29", false},
+	{"DATE", "2024-13-01", false},
+	{"DATE", "2024-04-31", false},
+	{"DATE", "20240229", false},
+	{"DATE", "2024-02", false},
+	{"DATE", "2024-02-29T00:00:00", false},
+	{"TIME-OF-DAY", "23:59:60", true},
+	{"TIME-OF-DAY", "24:00:00", true},
+	{"TIME-OF-DAY", "24:00:01", false},
+	{"TIME-OF-DAY", "12:60:00", false},
+	{"TIME-OF-DAY", "12:00", false},
+	{"TIME-OF-DAY", "12:00:00Z", false},
+	{"DATE-TIME", "2024-02-29T12:30:00", true},
+	{"DATE-TIME", "2024-02-29 12:30:00", false},
+	{"DATE-TIME", "2024-02-29T12:30", false},
+	{"DURATION", "P1Y2M3DT4H5M6S", true},
+	{"DURATION", "P2W", true},
+	{"DURATION", "PT0.5S", true},
+	{"DURATION", "P1D", true},
+	{"DURATION", "P", false},
+	{"DURATION", "PT", false},
+	{"DURATION", "P1M1Y", false},
+	{"DURATION", "P1.5DT1H", false},
+	{"DURATION", "P1W1D", false},
+	{"DURATION", "P1H", false},
+	{"TIME", "2024-02-29", true},
+	{"TIME", "2024-02", true},
+	{"TIME", "2024", true},
+	{"TIME", "2024-060", true},
+	{"TIME", "2023-366", false},
+	{"TIME", "2024-W05-3", true},
+	{"TIME", "2024-W54", false},
+	{"TIME", "2024-W53", false},
+	{"TIME", "2024-W52-7", true},
+	{"TIME", "2026-W53-4", true},
+	{"TIME", "2020-W53", true},
+	{"TIME", "2021-W53", false},
+	{"TIME", "2015-W53-1", true},
+	{"TIME", "2019-W53-1", false},
+	{"TIME", "12", true},
+	{"TIME", "12:30:00.25Z", true},
+	{"TIME", "12:30+05:30", true},
+	{"TIME", "24:00:00.5", false},
+	{"TIME", "2024-02-29T12:30:00-08", true},
+	{"TIME", "2024-02T12:30", false},
+	{"TIME", "P1D", true},
+	{"TIME", "2024-02-29/P1D", true},
+	{"TIME", "P1D/2024-02-29", true},
+	{"TIME", "2024-02-29/2024-03-01", true},
+	{"TIME", "P1D/P2D", false},
+	{"TIME", "R/2024-02-29/P1D", true},
+	{"TIME", "R5/P1D/2024-02-29", true},
+	{"TIME", "R5/2024-02-29", false},
+	{"TIME", "", false},
+	{"UTCTime", "2024-02-29", false},
+}
+
+func TestValidateTime(t *testing.T) {
+	for i, tt := range validateTimeTests {
+		if err := ValidateTime(tt.name, tt.value); (err == nil) != tt.ok {
+			t.Errorf("%d. ValidateTime(%q, %q) = %v, wanted success=%v", i, tt.name, tt.value, err, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"fmt"
+	"sort"
+)
+
+// A TagAlias is a name for a non-universal tag. Tag.Constructed is the default
+// constructed bit when the alias is used.
+type TagAlias struct {
+	Name string
+	Tag  Tag
+}
+
+// vocabularies contains the built-in tag vocabularies, by protocol.
+var vocabularies = map[string][]TagAlias{
+	// RFC 4120, section 5.
+	"kerberos": {
+		{"Ticket", Tag{ClassApplication, 1, true, 0, ""}},
+		{"Authenticator", Tag{ClassApplication, 2, true, 0, ""}},
+		{"EncTicketPart", Tag{ClassApplication, 3, true, 0, ""}},
+		{"AS-REQ", Tag{ClassApplication, 10, true, 0, ""}},
+		{"AS-REP", Tag{ClassApplication, 11, true, 0, ""}},
+		{"TGS-REQ", Tag{ClassApplication, 12, true, 0, ""}},
+		{"TGS-REP", Tag{ClassApplication, 13, true, 0, ""}},
+		{"AP-REQ", Tag{ClassApplication, 14, true, 0, ""}},
+		{"AP-REP", Tag{ClassApplication, 15, true, 0, ""}},
+		{"KRB-SAFE", Tag{ClassApplication, 20, true, 0, ""}},
+		{"KRB-PRIV", Tag{ClassApplication, 21, true, 0, ""}},
+		{"KRB-CRED", Tag{ClassApplication, 22, true, 0, ""}},
+		{"EncASRepPart", Tag{ClassApplication, 25, true, 0, ""}},
+		{"EncTGSRepPart", Tag{ClassApplication, 26, true, 0, ""}},
+		{"EncAPRepPart", Tag{ClassApplication, 27, true, 0, ""}},
+		{"EncKrbPrivPart", Tag{ClassApplication, 28, true, 0, ""}},
+		{"EncKrbCredPart", Tag{ClassApplication, 29, true, 0, ""}},
+		{"KRB-ERROR", Tag{ClassApplication, 30, true, 0, ""}},
+	},
+	// RFC 4511, section 4.
+	"ldap": {
+		{"BindRequest", Tag{ClassApplication, 0, true, 0, ""}},
+		{"BindResponse", Tag{ClassApplication, 1, true, 0, ""}},
+		{"UnbindRequest", Tag{ClassApplication, 2, false, 0, ""}},
+		{"SearchRequest", Tag{ClassApplication, 3, true, 0, ""}},
+		{"SearchResultEntry", Tag{ClassApplication, 4, true, 0, ""}},
+		{"SearchResultDone", Tag{ClassApplication, 5, true, 0, ""}},
+		{"ModifyRequest", Tag{ClassApplication, 6, true, 0, ""}},
+		{"ModifyResponse", Tag{ClassApplication, 7, true, 0, ""}},
+		{"AddRequest", Tag{ClassApplication, 8, true, 0, ""}},
+		{"AddResponse", Tag{ClassApplication, 9, true, 0, ""}},
+		{"DelRequest", Tag{ClassApplication, 10, false, 0, ""}},
+		{"DelResponse", Tag{ClassApplication, 11, true, 0, ""}},
+		{"ModifyDNRequest", Tag{ClassApplication, 12, true, 0, ""}},
+		{"ModifyDNResponse", Tag{ClassApplication, 13, true, 0, ""}},
+		{"CompareRequest", Tag{ClassApplication, 14, true, 0, ""}},
+		{"CompareResponse", Tag{ClassApplication, 15, true, 0, ""}},
+		{"AbandonRequest", Tag{ClassApplication, 16, false, 0, ""}},
+		{"SearchResultReference", Tag{ClassApplication, 19, true, 0, ""}},
+		{"ExtendedRequest", Tag{ClassApplication, 23, true, 0, ""}},
+		{"ExtendedResponse", Tag{ClassApplication, 24, true, 0, ""}},
+		{"IntermediateResponse", Tag{ClassApplication, 25, true, 0, ""}},
+	},
+	// RFC 1157, RFC 2578, and RFC 3416. The PDUs are context-specific,
+	// constructed tags, while the exceptions in variable bindings share
+	// their numbers as primitive tags.
+	"snmp": {
+		{"IpAddress", Tag{ClassApplication, 0, false, 0, ""}},
+		{"Counter32", Tag{ClassApplication, 1, false, 0, ""}},
+		{"Gauge32", Tag{ClassApplication, 2, false, 0, ""}},
+		{"TimeTicks", Tag{ClassApplication, 3, false, 0, ""}},
+		{"Opaque", Tag{ClassApplication, 4, false, 0, ""}},
+		{"Counter64", Tag{ClassApplication, 6, false, 0, ""}},
+		{"GetRequest-PDU", Tag{ClassContextSpecific, 0, true, 0, ""}},
+		{"GetNextRequest-PDU", Tag{ClassContextSpecific, 1, true, 0, ""}},
+		{"Response-PDU", Tag{ClassContextSpecific, 2, true, 0, ""}},
+		{"SetRequest-PDU", Tag{ClassContextSpecific, 3, true, 0, ""}},
+		{"Trap-PDU", Tag{ClassContextSpecific, 4, true, 0, ""}},
+		{"GetBulkRequest-PDU", Tag{ClassContextSpecific, 5, true, 0, ""}},
+		{"InformRequest-PDU", Tag{ClassContextSpecific, 6, true, 0, ""}},
+		{"SNMPv2-Trap-PDU", Tag{ClassContextSpecific, 7, true, 0, ""}},
+		{"Report-PDU", Tag{ClassContextSpecific, 8, true, 0, ""}},
+		{"noSuchObject", Tag{ClassContextSpecific, 0, false, 0, ""}},
+		{"noSuchInstance", Tag{ClassContextSpecific, 1, false, 0, ""}},
+		{"endOfMibView", Tag{ClassContextSpecific, 2, false, 0, ""}},
+	},
+}
+
+// VocabularyNames returns the names of the built-in tag vocabularies, sorted.
+func VocabularyNames() []string {
+	var names []string
+	for name := range vocabularies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// A Vocabulary is a set of tag aliases for non-universal tags, such as a
+// protocol's application tags. A nil *Vocabulary is empty.
+type Vocabulary struct {
+	// builtins are the names of the built-in vocabularies which were added.
+	builtins []string
+	// custom are the aliases which were added individually.
+	custom []TagAlias
+	// aliases contains all the aliases in v.
+	aliases []TagAlias
+}
+
+// IsTagAliasName returns whether name may be used as a tag alias. It must
+// consist of letters, digits, '-', and '_', begin with a letter, and not be a
+// universal tag name or a keyword in tag expressions.
+func IsTagAliasName(name string) bool {
+	if len(name) == 0 || !('a' <= name[0] && name[0] <= 'z' || 'A' <= name[0] && name[0] <= 'Z') {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '-' || c == '_') {
+			return false
+		}
+	}
+	switch name {
+	case "APPLICATION", "PRIVATE", "UNIVERSAL", "CONSTRUCTED", "PRIMITIVE":
+		return false
+	}
+	_, ok := TagByName(name)
+	return !ok
+}
+
+// AddVocabulary adds the aliases of the built-in vocabulary with the specified
+// name to v.
+func (v *Vocabulary) AddVocabulary(name string) error {
+	aliases, ok := vocabularies[name]
+	if !ok {
+		return fmt.Errorf("unknown vocabulary %q", name)
+	}
+	for _, b := range v.builtins {
+		if b == name {
+			return nil
+		}
+	}
+	for _, a := range aliases {
+		if _, err := v.add(a); err != nil {
+			return fmt.Errorf("vocabulary %s: %s", name, err)
+		}
+	}
+	v.builtins = append(v.builtins, name)
+	return nil
+}
+
+// Add adds an alias for tag to v. tag's constructed bit is the default when
+// the alias is used.
+func (v *Vocabulary) Add(name string, tag Tag) error {
+	if !IsTagAliasName(name) {
+		return fmt.Errorf("invalid tag alias name %q", name)
+	}
+	if tag.Class == ClassUniversal {
+		return fmt.Errorf("tag alias %s may not be universal", name)
+	}
+	tag.LongFormOverride = 0
+	added, err := v.add(TagAlias{name, tag})
+	if err != nil {
+		return err
+	}
+	if added {
+		v.custom = append(v.custom, TagAlias{name, tag})
+	}
+	return nil
+}
+
+// add adds a to v's aliases. If an identical alias already exists, it returns
+// false. Duplicate definitions are allowed so that the output of der2ascii may
+// be combined.
+func (v *Vocabulary) add(a TagAlias) (bool, error) {
+	for _, b := range v.aliases {
+		if b.Name == a.Name {
+			if b.Tag == a.Tag {
+				return false, nil
+			}
+			return false, fmt.Errorf("conflicting definitions of tag alias %s", a.Name)
+		}
+	}
+	v.aliases = append(v.aliases, a)
+	return true, nil
+}
+
+// Builtins returns the names of the built-in vocabularies added to v, in order.
+func (v *Vocabulary) Builtins() []string {
+	if v == nil {
+		return nil
+	}
+	return v.builtins
+}
+
+// Custom returns the aliases added to v individually, in order.
+func (v *Vocabulary) Custom() []TagAlias {
+	if v == nil {
+		return nil
+	}
+	return v.custom
+}
+
+// TagByName returns the tag with the specified alias in v or false if there is
+// none.
+func (v *Vocabulary) TagByName(name string) (Tag, bool) {
+	if v == nil {
+		return Tag{}, false
+	}
+	for _, a := range v.aliases {
+		if a.Name == name {
+			return a.Tag, true
+		}
+	}
+	return Tag{}, false
+}
+
+// GetAlias looks up the alias for the given tag in v. It behaves like
+// Tag.GetAlias, but an alias whose constructed bit matches the tag is
+// preferred.
+func (v *Vocabulary) GetAlias(t Tag) (name string, toggleConstructed bool, ok bool) {
+	if v == nil {
+		return
+	}
+	for _, a := range v.aliases {
+		if a.Tag.Class != t.Class || a.Tag.Number != t.Number || a.Tag.BigNumber != t.BigNumber {
+			continue
+		}
+		if a.Tag.Constructed == t.Constructed {
+			return a.Name, false, true
+		}
+		if !ok {
+			name, toggleConstructed, ok = a.Name, true, true
+		}
+	}
+	return
+}
@@ -0,0 +1,130 @@
+// Copyright 2026 The DER ASCII Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import "testing"
+
+func TestBuiltinVocabularies(t *testing.T) {
+	for _, name := range VocabularyNames() {
+		var v Vocabulary
+		if err := v.AddVocabulary(name); err != nil {
+			t.Errorf("AddVocabulary(%q) failed: %s", name, err)
+			continue
+		}
+		for _, a := range vocabularies[name] {
+			if !IsTagAliasName(a.Name) {
+				t.Errorf("%s: invalid alias name %q", name, a.Name)
+			}
+			if a.Tag.Class == ClassUniversal {
+				t.Errorf("%s: alias %s is universal", name, a.Name)
+			}
+			if tag, ok := v.TagByName(a.Name); !ok || tag != a.Tag {
+				t.Errorf("%s: TagByName(%q) = %v, %v, wanted %v", name, a.Name, tag, ok, a.Tag)
+			}
+			if got, toggle, ok := v.GetAlias(a.Tag); !ok || got != a.Name || toggle {
+				t.Errorf("%s: GetAlias(%v) = %q, %v, %v, wanted %q", name, a.Tag, got, toggle, ok, a.Name)
+			}
+		}
+	}
+}
+
+var vocabularyGetAliasTests = []struct {
+	tag               Tag
+	name              string
+	toggleConstructed bool
+	ok                bool
+}{
+	{Tag{ClassApplication, 30, true, 0, ""}, "KRB-ERROR", false, true},
+	{Tag{ClassApplication, 30, false, 0, ""}, "KRB-ERROR", true, true},
+	{Tag{ClassApplication, 30, true, 2, ""}, "KRB-ERROR", false, true},
+	{Tag{ClassContextSpecific, 0, true, 0, ""}, "GetRequest-PDU", false, true},
+	{Tag{ClassContextSpecific, 0, false, 0, ""}, "noSuchObject", false, true},
+	{Tag{ClassContextSpecific, 3, false, 0, ""}, "SetRequest-PDU", true, true},
+	{Tag{ClassContextSpecific, 30, true, 0, ""}, "", false, false},
+	{Tag{ClassPrivate, 0, true, 0, "18446744073709551616"}, "Big", false, true},
+	{Tag{ClassPrivate, 0, true, 0, ""}, "", false, false},
+	{Tag{ClassUniversal, 16, true, 0, ""}, "", false, false},
+}
+
+func TestVocabularyGetAlias(t *testing.T) {
+	var v Vocabulary
+	if err := v.AddVocabulary("kerberos"); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.AddVocabulary("snmp"); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Add("Big", Tag{ClassPrivate, 0, true, 0, "18446744073709551616"}); err != nil {
+		t.Fatal(err)
+	}
+	for i, tt := range vocabularyGetAliasTests {
+		name, toggleConstructed, ok := v.GetAlias(tt.tag)
+		if name != tt.name || toggleConstructed != tt.toggleConstructed || ok != tt.ok {
+			t.Errorf("%d. GetAlias(%v) = %q, %v, %v, wanted %q, %v, %v", i, tt.tag, name, toggleConstructed, ok, tt.name, tt.toggleConstructed, tt.ok)
+		}
+	}
+
+	var nilVocabulary *Vocabulary
+	if _, _, ok := nilVocabulary.GetAlias(Tag{ClassApplication, 30, true, 0, ""}); ok {
+		t.Errorf("GetAlias unexpectedly succeeded on a nil vocabulary")
+	}
+	if _, ok := nilVocabulary.TagByName("KRB-ERROR"); ok {
+		t.Errorf("TagByName unexpectedly succeeded on a nil vocabulary")
+	}
+}
+
+func TestVocabularyAdd(t *testing.T) {
+	var v Vocabulary
+	if err := v.AddVocabulary("ldap"); err != nil {
+		t.Fatal(err)
+	}
+	// Re-adding a vocabulary or an identical alias is a no-op.
+	if err := v.AddVocabulary("ldap"); err != nil {
+		t.Errorf("AddVocabulary(\"ldap\") failed the second time: %s", err)
+	}
+	if err := v.Add("BindRequest", Tag{ClassApplication, 0, true, 0, ""}); err != nil {
+		t.Errorf("Add of an identical alias failed: %s", err)
+	}
+	if err := v.Add("Foo", Tag{ClassPrivate, 1, false, 3, ""}); err != nil {
+		t.Errorf("Add(\"Foo\") failed: %s", err)
+	}
+	if err := v.Add("Foo", Tag{ClassPrivate, 1, false, 0, ""}); err != nil {
+		t.Errorf("Add of an identical alias failed: %s", err)
+	}
+	if len(v.Builtins()) != 1 || len(v.Custom()) != 1 || v.Custom()[0].Tag.LongFormOverride != 0 {
+		t.Errorf("Builtins() = %v, Custom() = %v, wanted [ldap] and [Foo]", v.Builtins(), v.Custom())
+	}
+
+	for _, tt := range []struct {
+		name string
+		tag  Tag
+	}{
+		{"BindRequest", Tag{ClassApplication, 1, true, 0, ""}},
+		{"Foo", Tag{ClassPrivate, 2, false, 0, ""}},
+		{"SEQUENCE", Tag{ClassPrivate, 2, false, 0, ""}},
+		{"APPLICATION", Tag{ClassPrivate, 2, false, 0, ""}},
+		{"1Foo", Tag{ClassPrivate, 2, false, 0, ""}},
+		{"Foo Bar", Tag{ClassPrivate, 2, false, 0, ""}},
+		{"", Tag{ClassPrivate, 2, false, 0, ""}},
+		{"Bar", Tag{ClassUniversal, 2, false, 0, ""}},
+	} {
+		if err := v.Add(tt.name, tt.tag); err == nil {
+			t.Errorf("Add(%q, %v) unexpectedly succeeded", tt.name, tt.tag)
+		}
+	}
+	if err := v.AddVocabulary("bogus"); err == nil {
+		t.Errorf("AddVocabulary(\"bogus\") unexpectedly succeeded")
+	}
+}
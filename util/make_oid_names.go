@@ -28,7 +28,7 @@ import (
 
 const (
 	oidNamesTxt = "util/oid_names.txt"
-	oidNamesGo  = "cmd/der2ascii/oid_names.go"
+	oidNamesGo  = "internal/der2ascii/oid_names.go"
 )
 
 func makeOIDNames() error {
@@ -56,7 +56,7 @@ func makeOIDNames() error {
 // This file is generated by make_oid_names.go. Do not edit by hand.
 // To regenerate, run "go run util/make_oid_names.go" from the top-level directory.
 
-package main
+package der2ascii
 
 var oidNames = []struct {
 	oid  []byte